@@ -8,13 +8,16 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"sync"
 	"syscall"
 	"time"
 
 	"newsWebApp/app/apiService/internal/config"
 	"newsWebApp/app/apiService/internal/server/handler"
 	"newsWebApp/app/apiService/internal/server/server"
+	"newsWebApp/app/apiService/internal/services/activitypub"
 	"newsWebApp/app/apiService/internal/services/authgrpc"
+	"newsWebApp/app/apiService/internal/services/events"
 	"newsWebApp/app/apiService/internal/services/fetcher"
 	"newsWebApp/app/apiService/internal/services/newsgrpc"
 	"newsWebApp/app/apiService/internal/storage/cache"
@@ -27,6 +30,8 @@ type App struct {
 	log     *slog.Logger
 	cache   *cache.Cache
 	fetcher *fetcher.NewsFetcher
+	hub     *events.Hub
+	actor   *activitypub.Actor
 	handler http.Handler
 	srv     *server.Server
 }
@@ -74,24 +79,52 @@ func New() *App {
 		os.Exit(1)
 	}
 
+	// Cache holds more than a page, so a short cache means there is no next page.
 	a.cache, err = connectToCache(ctx,
 		a.cfg.Cache.Host,
 		a.cfg.Cache.Port,
-		a.cfg.Manager.ArticlesLimit,
+		max(a.cfg.Manager.CacheSize, a.cfg.Manager.ArticlesLimit+1),
 	)
 	if err != nil {
 		a.log.Error("Failed to create new articles cache", "err", err.Error())
 		os.Exit(1)
 	}
 
-	a.fetcher = fetcher.New(newsClient, a.cache, a.cfg.Manager.RefreshInterval, a.log)
+	var actor handler.FediverseActor
+	var announcers []fetcher.Announcer
+
+	if a.cfg.ActivityPub.Enabled {
+		apActor, err := newActor(&a.cfg.ActivityPub, a.cfg.Server.PublicURL, newsClient, a.cache, a.log)
+		if err != nil {
+			a.log.Error("Failed to create activitypub actor", "err", err.Error())
+			os.Exit(1)
+		}
+
+		a.actor = apActor
+		actor = apActor
+		announcers = append(announcers, apActor)
+	}
+
+	a.fetcher = fetcher.New(newsClient, a.cache, announcers, a.cfg.Manager.ArticlesLimit, a.log)
+
+	a.hub = events.New(a.cache, a.log)
 
 	a.handler, err = handler.New(authClient,
+		newsClient,
+		newsClient,
+		newsClient,
+		newsClient,
+		newsClient,
 		newsClient,
 		a.fetcher,
+		a.hub,
+		a.cache,
+		actor,
+		a.cfg.Admin.UserNames,
+		a.cfg.Server.PublicURL,
 		a.cfg.TokenManager.RefreshTokenTTL,
-		a.cfg.Manager.RefreshInterval,
 		a.cfg.Server.Timeout,
+		a.cfg.Server.Heartbeat,
 		a.log,
 	)
 	if err != nil {
@@ -110,7 +143,16 @@ func (a *App) MustRun() {
 	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
 	defer cancel()
 
+	// Deliveries are stopped after the server, so activities of last requests are sent.
+	deliveryCtx, stopDelivery := context.WithCancel(context.Background())
+	defer stopDelivery()
+
+	var wg sync.WaitGroup
+
+	wg.Add(1)
 	go func() {
+		defer wg.Done()
+
 		if err := a.fetcher.Start(ctx); err != nil {
 			if !errors.Is(err, context.Canceled) {
 				a.log.Error("Failed ower working fetcher in api service", "err", err.Error())
@@ -119,6 +161,26 @@ func (a *App) MustRun() {
 		}
 	}()
 
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+
+		if err := a.hub.Start(ctx); err != nil && !errors.Is(err, context.Canceled) {
+			a.log.Error("Failed ower working events hub in api service", "err", err.Error())
+		}
+	}()
+
+	if a.actor != nil {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			if err := a.actor.Start(deliveryCtx); err != nil && !errors.Is(err, context.Canceled) {
+				a.log.Error("Failed ower working activitypub delivery in api service", "err", err.Error())
+			}
+		}()
+	}
+
 	go func() {
 		if err := a.srv.Start(); err != nil {
 			if !errors.Is(err, http.ErrServerClosed) {
@@ -132,10 +194,14 @@ func (a *App) MustRun() {
 	signal.Notify(stop, syscall.SIGTERM, syscall.SIGINT)
 	<-stop
 
-	a.mustStop()
+	cancel()
+
+	a.mustStop(&wg, stopDelivery)
 }
 
-func (a *App) mustStop() {
+// mustStop waits for in-flight requests, then stops deliveries and waits for background
+// loops before closing connections they use.
+func (a *App) mustStop(loops *sync.WaitGroup, stopDelivery context.CancelFunc) {
 	ctx, cancel := context.WithTimeout(context.Background(), a.cfg.Server.Timeout)
 	defer cancel()
 
@@ -143,6 +209,20 @@ func (a *App) mustStop() {
 		a.log.Error("Closing connection to api server", "err", err.Error())
 	}
 
+	stopDelivery()
+
+	done := make(chan struct{})
+	go func() {
+		loops.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(a.cfg.Server.Timeout):
+		a.log.Warn("Background loops didn't stop in time")
+	}
+
 	if err := a.cache.CloseConn(); err != nil {
 		a.log.Error("Closing connection to articles cache", "err", err.Error())
 	}
@@ -150,12 +230,26 @@ func (a *App) mustStop() {
 	a.log.Info("Api service stoped gracefully")
 }
 
-func connectToCache(ctx context.Context, host string, port string, limit int) (*cache.Cache, error) {
+func newActor(cfg *config.ActivityPub,
+	publicURL string,
+	followers activitypub.FollowerService,
+	articles activitypub.ArticleSource,
+	log *slog.Logger,
+) (*activitypub.Actor, error) {
+	key, err := activitypub.LoadKey(cfg.KeyFile)
+	if err != nil {
+		return nil, err
+	}
+
+	return activitypub.New(publicURL, cfg.Name, key, followers, articles, cfg.Timeout, cfg.Insecure, log)
+}
+
+func connectToCache(ctx context.Context, host string, port string, size int) (*cache.Cache, error) {
 	var err error
 	var c *cache.Cache
 
 	for i := 1; i <= 5; i++ {
-		c, err = cache.New(ctx, host, port, limit)
+		c, err = cache.New(ctx, host, port, size)
 		if err != nil {
 			time.Sleep(time.Duration(i) * time.Second)
 		} else {
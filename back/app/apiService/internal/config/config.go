@@ -17,12 +17,16 @@ type Config struct {
 	Cache        Redis          `yaml:"redis_storage"`
 	Manager      ArticleManager `yaml:"news_managment"`
 	TokenManager TokenManager   `yaml:"token_managment"`
+	Admin        Admin          `yaml:"admin"`
+	ActivityPub  ActivityPub    `yaml:"activitypub"`
 }
 
 type ApiServer struct {
 	Address     string        `yaml:"address" env-default:"localhost:8080"`
 	Timeout     time.Duration `yaml:"timeout" env-default:"5s"`
 	IdleTimeout time.Duration `yaml:"idle_timeout" env-default:"90s"`
+	Heartbeat   time.Duration `yaml:"events_heartbeat" env-default:"15s"`
+	PublicURL   string        `yaml:"public_url" env-default:"http://localhost:8080"`
 }
 
 type GRPCConfig struct {
@@ -33,8 +37,8 @@ type GRPCConfig struct {
 }
 
 type ArticleManager struct {
-	ArticlesLimit   int           `yaml:"articles_limit"`
-	RefreshInterval time.Duration `yaml:"refresh_interval"`
+	ArticlesLimit int `yaml:"articles_limit"`
+	CacheSize     int `yaml:"cache_size" env-default:"200"`
 }
 
 type Redis struct {
@@ -42,6 +46,18 @@ type Redis struct {
 	Port string `yaml:"port"`
 }
 
+type Admin struct {
+	UserNames []string `yaml:"user_names"`
+}
+
+type ActivityPub struct {
+	Enabled  bool          `yaml:"enabled"`
+	Name     string        `yaml:"name" env-default:"news"`
+	KeyFile  string        `yaml:"key_file" env:"ACTIVITYPUB_KEY_FILE" env-default:"activitypub.pem"`
+	Timeout  time.Duration `yaml:"timeout" env-default:"10s"`
+	Insecure bool          `yaml:"insecure"`
+}
+
 type TokenManager struct {
 	AccessTokenTTL  time.Duration `yaml:"access_token_ttl"`
 	RefreshTokenTTL time.Duration `yaml:"refresh_token_ttl"`
@@ -1,6 +1,7 @@
 package models
 
 import (
+	"encoding/json"
 	"time"
 )
 
@@ -13,6 +14,140 @@ type Article struct {
 	Excerpt    string    `json:"excerpt"`
 	ImageURL   string    `json:"image_url"`
 	PostedAt   time.Time `json:"posted_at"`
+	Status     string    `json:"status,omitempty"`
+	Reason     string    `json:"reason,omitempty"`
+}
+
+type ArticleContent struct {
+	HTML           string `json:"html"`
+	Text           string `json:"text"`
+	WordCount      int64  `json:"word_count"`
+	ReadingMinutes int64  `json:"reading_minutes"`
+	Byline         string `json:"byline,omitempty"`
+	Language       string `json:"language,omitempty"`
+}
+
+type ReaderArticle struct {
+	Article
+	Content ArticleContent `json:"content"`
+}
+
+type SearchResult struct {
+	Article
+	Rank    float32 `json:"rank"`
+	Snippet string  `json:"snippet"`
+}
+
+// Types of live events.
+const (
+	EventArticle    = "article"
+	EventSubmission = "submission"
+)
+
+// Event is a live update sent to browsers. Event with User is sent only to this user.
+type Event struct {
+	ID   string          `json:"id,omitempty"`
+	Type string          `json:"type"`
+	User string          `json:"user,omitempty"`
+	Data json.RawMessage `json:"data"`
+}
+
+// Statuses of articles offered by users. Pending article is posted after it's approved.
+const (
+	StatusPending  = "pending"
+	StatusApproved = "approved"
+	StatusRejected = "rejected"
+	StatusPosted   = "posted"
+)
+
+type SubmissionStatus struct {
+	ArticleID int64  `json:"article_id"`
+	Status    string `json:"status"`
+	Reason    string `json:"reason,omitempty"`
+}
+
+// Submission is an article offered by the user as moderators see it.
+type Submission struct {
+	Article
+	UserID      int64  `json:"user_id"`
+	Note        string `json:"note,omitempty"`
+	CreatedAt   string `json:"created_at"`
+	ModeratedBy string `json:"moderated_by,omitempty"`
+	ModeratedAt string `json:"moderated_at,omitempty"`
+}
+
+type Source struct {
+	SourceID      int64  `json:"source_id"`
+	Name          string `json:"name"`
+	FeedURL       string `json:"feed_url"`
+	Enabled       bool   `json:"enabled"`
+	FailureCount  int64  `json:"failure_count,omitempty"`
+	LastError     string `json:"last_error,omitempty"`
+	LastErrorAt   string `json:"last_error_at,omitempty"`
+	LastSuccessAt string `json:"last_success_at,omitempty"`
+	NextFetchAt   string `json:"next_fetch_at,omitempty"`
+}
+
+type OPMLEntry struct {
+	Title   string `json:"title"`
+	FeedURL string `json:"feed_url"`
+	Reason  string `json:"reason,omitempty"`
+}
+
+type OPMLReport struct {
+	Added    []OPMLEntry `json:"added"`
+	Skipped  []OPMLEntry `json:"skipped"`
+	Rejected []OPMLEntry `json:"rejected"`
+}
+
+type FilterPreview struct {
+	Accepted     bool     `json:"accepted"`
+	Rule         string   `json:"rule"`
+	MatchedRules []string `json:"matched_rules"`
+	Article      Article  `json:"article"`
+	Categories   []string `json:"categories"`
+}
+
+type ReloadReport struct {
+	Rules         int64  `json:"rules"`
+	FetchInterval string `json:"fetch_interval"`
+	ReloadedAt    string `json:"reloaded_at"`
+}
+
+type Webhook struct {
+	WebhookID int64    `json:"webhook_id"`
+	URL       string   `json:"url"`
+	Events    []string `json:"events"`
+	Enabled   bool     `json:"enabled"`
+	CreatedAt string   `json:"created_at"`
+	Secret    string   `json:"secret,omitempty"`
+}
+
+type WebhookDelivery struct {
+	DeliveryID    int64           `json:"delivery_id"`
+	WebhookID     int64           `json:"webhook_id"`
+	Event         string          `json:"event"`
+	Payload       json.RawMessage `json:"payload"`
+	Status        string          `json:"status"`
+	Attempts      int64           `json:"attempts"`
+	ResponseCode  int64           `json:"response_code,omitempty"`
+	LastError     string          `json:"last_error,omitempty"`
+	CreatedAt     string          `json:"created_at"`
+	NextAttemptAt string          `json:"next_attempt_at,omitempty"`
+	DeliveredAt   string          `json:"delivered_at,omitempty"`
+}
+
+type DigestSubscription struct {
+	Frequency  string `json:"frequency"`
+	CreatedAt  string `json:"created_at"`
+	LastSentAt string `json:"last_sent_at,omitempty"`
+	NextSendAt string `json:"next_send_at"`
+}
+
+type Follower struct {
+	ActorID     string
+	Inbox       string
+	SharedInbox string
 }
 
 type Art struct {
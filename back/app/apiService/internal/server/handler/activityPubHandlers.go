@@ -0,0 +1,114 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"newsWebApp/app/apiService/internal/services"
+	"newsWebApp/app/apiService/internal/services/activitypub"
+)
+
+const maxActivitySize = 1 << 18
+
+// webFinger resolves acct:name@host to the actor, it's how Mastodon users find it.
+func webFinger(actor FediverseActor, slog *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		jrd, err := actor.WebFinger(r.URL.Query().Get("resource"))
+		if err != nil {
+			http.NotFound(w, r)
+			return
+		}
+
+		responseActivityJSON(w, "application/jrd+json", jrd, slog)
+	}
+}
+
+func actorDocument(actor FediverseActor, slog *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		responseActivityJSON(w, activitypub.ContentType, actor.Document(), slog)
+	}
+}
+
+func actorOutbox(timeout time.Duration, actor FediverseActor, slog *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+
+		outbox, err := actor.Outbox(ctx)
+		if err != nil {
+			slog.Error("Can't get outbox", "err", err.Error())
+			http.Error(w, "Internal server error", http.StatusInternalServerError)
+			return
+		}
+
+		responseActivityJSON(w, activitypub.ContentType, outbox, slog)
+	}
+}
+
+func actorFollowers(timeout time.Duration, actor FediverseActor, slog *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+
+		followers, err := actor.Followers(ctx)
+		if err != nil {
+			slog.Error("Can't get followers", "err", err.Error())
+			http.Error(w, "Internal server error", http.StatusInternalServerError)
+			return
+		}
+
+		responseActivityJSON(w, activitypub.ContentType, followers, slog)
+	}
+}
+
+// actorInbox accepts signed activities of other servers.
+func actorInbox(timeout time.Duration, actor FediverseActor, slog *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		defer r.Body.Close()
+
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxActivitySize))
+		if err != nil || len(body) == 0 {
+			http.Error(w, "Bad request", http.StatusBadRequest)
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+
+		if err := actor.HandleInbox(ctx, r, body); err != nil {
+			switch {
+			case errors.Is(err, services.ErrInvalidSignature):
+				http.Error(w, "Invalid signature", http.StatusUnauthorized)
+			case errors.Is(err, services.ErrInvalidActivity), errors.Is(err, services.ErrInvalidFollower):
+				http.Error(w, "Invalid activity", http.StatusBadRequest)
+			default:
+				slog.Error("Can't handle inbox activity", "err", err.Error())
+				http.Error(w, "Internal server error", http.StatusInternalServerError)
+			}
+			return
+		}
+
+		w.WriteHeader(http.StatusAccepted)
+	}
+}
+
+func responseActivityJSON(w http.ResponseWriter, contentType string, document any, slog *slog.Logger) {
+	documentJSON, err := json.Marshal(document)
+	if err != nil {
+		slog.Error("Can't marshal activity document", "err", err.Error())
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Cache-Control", "max-age=60")
+
+	if _, err := w.Write(documentJSON); err != nil {
+		slog.Error("Can't make response", "err", err.Error())
+	}
+}
@@ -0,0 +1,397 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"newsWebApp/app/apiService/internal/models"
+	"newsWebApp/app/apiService/internal/services"
+)
+
+func listSources(timeout time.Duration, sources SourceService, slog *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, uName, acToken := getInfoFromCtx(r)
+
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+
+		srcs, err := sources.ListSources(ctx)
+		if err != nil {
+			if errors.Is(err, services.ErrNoSources) {
+				err = responseJSONError(w, http.StatusNoContent, id, acToken, "There are no sources")
+				if err != nil {
+					slog.Error("Can't make response", "err", err.Error())
+				}
+				return
+			}
+			slog.Error("Can't list sources", "err", err.Error())
+
+			err = responseJSONError(w, http.StatusInternalServerError, id, acToken, "Internal error")
+			if err != nil {
+				slog.Error("Can't make response", "err", err.Error())
+			}
+			return
+		}
+
+		respBody := respBody{
+			UserID:   id,
+			UserName: uName,
+			AcToken:  acToken,
+			Sources:  srcs,
+		}
+
+		if err = responseJSONOk(w, http.StatusOK, respBody); err != nil {
+			slog.Error("Can't make response", "err", err.Error())
+		}
+	}
+}
+
+func sourcesHealth(timeout time.Duration, sources SourceService, slog *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, uName, acToken := getInfoFromCtx(r)
+
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+
+		srcs, err := sources.ListSourceHealth(ctx)
+		if err != nil {
+			if errors.Is(err, services.ErrNoSources) {
+				err = responseJSONError(w, http.StatusNoContent, id, acToken, "There are no sources")
+				if err != nil {
+					slog.Error("Can't make response", "err", err.Error())
+				}
+				return
+			}
+			slog.Error("Can't list sources health", "err", err.Error())
+
+			err = responseJSONError(w, http.StatusInternalServerError, id, acToken, "Internal error")
+			if err != nil {
+				slog.Error("Can't make response", "err", err.Error())
+			}
+			return
+		}
+
+		respBody := respBody{
+			UserID:   id,
+			UserName: uName,
+			AcToken:  acToken,
+			Sources:  srcs,
+		}
+
+		if err = responseJSONOk(w, http.StatusOK, respBody); err != nil {
+			slog.Error("Can't make response", "err", err.Error())
+		}
+	}
+}
+
+type sourceRequest struct {
+	SourceID int64  `json:"source_id"`
+	Name     string `json:"name"`
+	FeedURL  string `json:"feed_url"`
+	Enabled  bool   `json:"enabled"`
+}
+
+func addSource(timeout time.Duration, sources SourceService, slog *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		defer r.Body.Close()
+		req := sourceRequest{}
+
+		id, uName, acToken := getInfoFromCtx(r)
+
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			if !strings.Contains(err.Error(), "EOF") {
+				slog.Debug("Can't decode body from add-source request", "err", err.Error())
+
+				err = responseJSONError(w, http.StatusBadRequest, id, acToken, "Bad request")
+				if err != nil {
+					slog.Error("Can't make response", "err", err.Error())
+				}
+				return
+			}
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+
+		src, err := sources.AddSource(ctx, req.Name, req.FeedURL)
+		if err != nil {
+			responseSourceError(w, err, id, acToken, slog)
+			return
+		}
+
+		respBody := respBody{
+			UserID:   id,
+			UserName: uName,
+			AcToken:  acToken,
+			Sources:  []models.Source{*src},
+		}
+
+		if err = responseJSONOk(w, http.StatusCreated, respBody); err != nil {
+			slog.Error("Can't make response", "err", err.Error())
+		}
+	}
+}
+
+func updateSource(timeout time.Duration, sources SourceService, slog *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		defer r.Body.Close()
+		req := sourceRequest{}
+
+		id, uName, acToken := getInfoFromCtx(r)
+
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			if !strings.Contains(err.Error(), "EOF") {
+				slog.Debug("Can't decode body from update-source request", "err", err.Error())
+
+				err = responseJSONError(w, http.StatusBadRequest, id, acToken, "Bad request")
+				if err != nil {
+					slog.Error("Can't make response", "err", err.Error())
+				}
+				return
+			}
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+
+		src, err := sources.UpdateSource(ctx, req.SourceID, req.Name, req.FeedURL)
+		if err != nil {
+			responseSourceError(w, err, id, acToken, slog)
+			return
+		}
+
+		respBody := respBody{
+			UserID:   id,
+			UserName: uName,
+			AcToken:  acToken,
+			Sources:  []models.Source{*src},
+		}
+
+		if err = responseJSONOk(w, http.StatusAccepted, respBody); err != nil {
+			slog.Error("Can't make response", "err", err.Error())
+		}
+	}
+}
+
+func setSourceEnabled(timeout time.Duration, sources SourceService, slog *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		defer r.Body.Close()
+		req := sourceRequest{}
+
+		id, uName, acToken := getInfoFromCtx(r)
+
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			if !strings.Contains(err.Error(), "EOF") {
+				slog.Debug("Can't decode body from enable-source request", "err", err.Error())
+
+				err = responseJSONError(w, http.StatusBadRequest, id, acToken, "Bad request")
+				if err != nil {
+					slog.Error("Can't make response", "err", err.Error())
+				}
+				return
+			}
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+
+		src, err := sources.SetSourceEnabled(ctx, req.SourceID, req.Enabled)
+		if err != nil {
+			responseSourceError(w, err, id, acToken, slog)
+			return
+		}
+
+		respBody := respBody{
+			UserID:   id,
+			UserName: uName,
+			AcToken:  acToken,
+			Sources:  []models.Source{*src},
+		}
+
+		if err = responseJSONOk(w, http.StatusAccepted, respBody); err != nil {
+			slog.Error("Can't make response", "err", err.Error())
+		}
+	}
+}
+
+func deleteSource(timeout time.Duration, sources SourceService, slog *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		defer r.Body.Close()
+		req := sourceRequest{}
+
+		id, uName, acToken := getInfoFromCtx(r)
+
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			if !strings.Contains(err.Error(), "EOF") {
+				slog.Debug("Can't decode body from delete-source request", "err", err.Error())
+
+				err = responseJSONError(w, http.StatusBadRequest, id, acToken, "Bad request")
+				if err != nil {
+					slog.Error("Can't make response", "err", err.Error())
+				}
+				return
+			}
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+
+		if err := sources.DeleteSource(ctx, req.SourceID); err != nil {
+			responseSourceError(w, err, id, acToken, slog)
+			return
+		}
+
+		respBody := respBody{
+			UserID:   id,
+			UserName: uName,
+			AcToken:  acToken,
+		}
+
+		if err := responseJSONOk(w, http.StatusOK, respBody); err != nil {
+			slog.Error("Can't make response", "err", err.Error())
+		}
+	}
+}
+
+const maxOPMLSize = 1 << 20
+
+func importOPML(timeout time.Duration, sources SourceService, slog *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		defer r.Body.Close()
+
+		id, uName, acToken := getInfoFromCtx(r)
+
+		document, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxOPMLSize))
+		if err != nil || len(document) == 0 {
+			slog.Debug("Can't read opml document from import request")
+
+			err = responseJSONError(w, http.StatusBadRequest, id, acToken, "Bad request")
+			if err != nil {
+				slog.Error("Can't make response", "err", err.Error())
+			}
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+
+		report, err := sources.ImportOPML(ctx, document)
+		if err != nil {
+			if errors.Is(err, services.ErrInvalidOPML) {
+				err = responseJSONError(w, http.StatusBadRequest, id, acToken, "Invalid OPML document")
+				if err != nil {
+					slog.Error("Can't make response", "err", err.Error())
+				}
+				return
+			}
+			slog.Error("Can't import opml", "err", err.Error())
+
+			err = responseJSONError(w, http.StatusInternalServerError, id, acToken, "Internal error")
+			if err != nil {
+				slog.Error("Can't make response", "err", err.Error())
+			}
+			return
+		}
+
+		respBody := respBody{
+			UserID:   id,
+			UserName: uName,
+			AcToken:  acToken,
+			Import:   report,
+		}
+
+		if err = responseJSONOk(w, http.StatusOK, respBody); err != nil {
+			slog.Error("Can't make response", "err", err.Error())
+		}
+	}
+}
+
+func exportOPML(timeout time.Duration, sources SourceService, slog *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, _, acToken := getInfoFromCtx(r)
+
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+
+		document, err := sources.ExportOPML(ctx)
+		if err != nil {
+			slog.Error("Can't export opml", "err", err.Error())
+
+			err = responseJSONError(w, http.StatusInternalServerError, id, acToken, "Internal error")
+			if err != nil {
+				slog.Error("Can't make response", "err", err.Error())
+			}
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/x-opml; charset=utf-8")
+		w.Header().Set("Content-Disposition", `attachment; filename="sources.opml"`)
+
+		if _, err := w.Write(document); err != nil {
+			slog.Error("Can't make response", "err", err.Error())
+		}
+	}
+}
+
+func reloadConfig(timeout time.Duration, configs ConfigService, slog *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, uName, acToken := getInfoFromCtx(r)
+
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+
+		report, err := configs.ReloadConfig(ctx)
+		if err != nil {
+			if errors.Is(err, services.ErrInvalidConfig) {
+				err = responseJSONError(w, http.StatusUnprocessableEntity, id, acToken, err.Error())
+				if err != nil {
+					slog.Error("Can't make response", "err", err.Error())
+				}
+				return
+			}
+			slog.Error("Can't reload config", "err", err.Error())
+
+			err = responseJSONError(w, http.StatusInternalServerError, id, acToken, "Internal error")
+			if err != nil {
+				slog.Error("Can't make response", "err", err.Error())
+			}
+			return
+		}
+
+		respBody := respBody{
+			UserID:   id,
+			UserName: uName,
+			AcToken:  acToken,
+			Reload:   report,
+		}
+
+		if err = responseJSONOk(w, http.StatusOK, respBody); err != nil {
+			slog.Error("Can't make response", "err", err.Error())
+		}
+	}
+}
+
+func responseSourceError(w http.ResponseWriter, err error, id int64, acToken string, slog *slog.Logger) {
+	switch {
+	case errors.Is(err, services.ErrInvalidSource):
+		err = responseJSONError(w, http.StatusBadRequest, id, acToken, "Invalid source")
+	case errors.Is(err, services.ErrSourceExists):
+		err = responseJSONError(w, http.StatusConflict, id, acToken, "Source already exists")
+	case errors.Is(err, services.ErrSourceNotFound):
+		err = responseJSONError(w, http.StatusNotFound, id, acToken, "Source not found")
+	default:
+		slog.Error("Can't manage source", "err", err.Error())
+
+		err = responseJSONError(w, http.StatusInternalServerError, id, acToken, "Internal error")
+	}
+
+	if err != nil {
+		slog.Error("Can't make response", "err", err.Error())
+	}
+}
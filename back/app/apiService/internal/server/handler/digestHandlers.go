@@ -0,0 +1,196 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"html/template"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"newsWebApp/app/apiService/internal/services"
+)
+
+type digestRequest struct {
+	Frequency string `json:"frequency"`
+}
+
+func getDigest(timeout time.Duration, digests DigestService, slog *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, uName, acToken := getInfoFromCtx(r)
+
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+
+		sub, err := digests.GetDigestSubscription(ctx, id)
+		if err != nil {
+			responseDigestError(w, err, id, acToken, slog)
+			return
+		}
+
+		respBody := respBody{
+			UserID:   id,
+			UserName: uName,
+			AcToken:  acToken,
+			Digest:   sub,
+		}
+
+		if err = responseJSONOk(w, http.StatusOK, respBody); err != nil {
+			slog.Error("Can't make response", "err", err.Error())
+		}
+	}
+}
+
+// subscribeDigest subscribes the user to daily or weekly digest or changes its frequency.
+func subscribeDigest(timeout time.Duration, digests DigestService, slog *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		defer r.Body.Close()
+		req := digestRequest{}
+
+		id, uName, acToken := getInfoFromCtx(r)
+
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			if !strings.Contains(err.Error(), "EOF") {
+				slog.Debug("Can't decode body from subscribe-digest request", "err", err.Error())
+
+				err = responseJSONError(w, http.StatusBadRequest, id, acToken, "Bad request")
+				if err != nil {
+					slog.Error("Can't make response", "err", err.Error())
+				}
+				return
+			}
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+
+		sub, err := digests.SubscribeDigest(ctx, id, req.Frequency)
+		if err != nil {
+			responseDigestError(w, err, id, acToken, slog)
+			return
+		}
+
+		respBody := respBody{
+			UserID:   id,
+			UserName: uName,
+			AcToken:  acToken,
+			Digest:   sub,
+		}
+
+		if err = responseJSONOk(w, http.StatusOK, respBody); err != nil {
+			slog.Error("Can't make response", "err", err.Error())
+		}
+	}
+}
+
+func unsubscribeDigest(timeout time.Duration, digests DigestService, slog *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, uName, acToken := getInfoFromCtx(r)
+
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+
+		if err := digests.UnsubscribeDigest(ctx, id); err != nil {
+			responseDigestError(w, err, id, acToken, slog)
+			return
+		}
+
+		respBody := respBody{
+			UserID:   id,
+			UserName: uName,
+			AcToken:  acToken,
+		}
+
+		if err := responseJSONOk(w, http.StatusOK, respBody); err != nil {
+			slog.Error("Can't make response", "err", err.Error())
+		}
+	}
+}
+
+var unsubscribePage = template.Must(template.New("unsubscribe").Parse(`<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>News digest</title></head>
+<body style="font-family: sans-serif; max-width: 480px; margin: 40px auto;">
+{{if .Confirm}}<form method="post">
+<input type="hidden" name="token" value="{{.Token}}">
+<p>Stop getting the news digest?</p>
+<button type="submit">Unsubscribe</button>
+</form>
+{{else}}<p>{{.Message}}</p>
+{{end}}</body>
+</html>
+`))
+
+type unsubscribeView struct {
+	Confirm bool
+	Token   string
+	Message string
+}
+
+// confirmUnsubscribe shows a button to unsubscribe by the link from a digest. Link
+// doesn't unsubscribe by itself, mail scanners open links too.
+func confirmUnsubscribe(slog *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		token := r.URL.Query().Get("token")
+		if token == "" {
+			responseUnsubscribePage(w, http.StatusBadRequest, unsubscribeView{Message: "The link is invalid."}, slog)
+			return
+		}
+
+		responseUnsubscribePage(w, http.StatusOK, unsubscribeView{Confirm: true, Token: token}, slog)
+	}
+}
+
+// unsubscribeByToken unsubscribes without logging in. It's posted by the confirm
+// page and by mail clients supporting one-click List-Unsubscribe.
+func unsubscribeByToken(timeout time.Duration, digests DigestService, slog *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		token := r.URL.Query().Get("token")
+		if token == "" {
+			token = r.PostFormValue("token")
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+
+		err := digests.UnsubscribeDigestByToken(ctx, token)
+
+		switch {
+		case err == nil, errors.Is(err, services.ErrSubscriptionNotFound):
+			responseUnsubscribePage(w, http.StatusOK, unsubscribeView{Message: "You won't get the news digest anymore."}, slog)
+		case errors.Is(err, services.ErrInvalidToken):
+			responseUnsubscribePage(w, http.StatusBadRequest, unsubscribeView{Message: "The link is invalid."}, slog)
+		default:
+			slog.Error("Can't unsubscribe from digest", "err", err.Error())
+			responseUnsubscribePage(w, http.StatusInternalServerError, unsubscribeView{Message: "Something went wrong, try again later."}, slog)
+		}
+	}
+}
+
+func responseUnsubscribePage(w http.ResponseWriter, status int, view unsubscribeView, slog *slog.Logger) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(status)
+
+	if err := unsubscribePage.Execute(w, view); err != nil {
+		slog.Error("Can't make response", "err", err.Error())
+	}
+}
+
+func responseDigestError(w http.ResponseWriter, err error, id int64, acToken string, slog *slog.Logger) {
+	switch {
+	case errors.Is(err, services.ErrInvalidFrequency):
+		err = responseJSONError(w, http.StatusBadRequest, id, acToken, "Frequency must be daily or weekly")
+	case errors.Is(err, services.ErrSubscriptionNotFound):
+		err = responseJSONError(w, http.StatusNotFound, id, acToken, "Not subscribed to digest")
+	default:
+		slog.Error("Can't manage digest subscription", "err", err.Error())
+
+		err = responseJSONError(w, http.StatusInternalServerError, id, acToken, "Internal error")
+	}
+
+	if err != nil {
+		slog.Error("Can't make response", "err", err.Error())
+	}
+}
@@ -0,0 +1,167 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"newsWebApp/app/apiService/internal/models"
+	"newsWebApp/app/apiService/internal/storage"
+)
+
+// streamEvents streams live events as Server-Sent Events. EventSource can't send headers,
+// so access token may be passed in access_token query parameter too. Article events
+// carry their id, so a reconnecting browser gets articles it missed after Last-Event-ID.
+func streamEvents(timeout time.Duration, heartbeat time.Duration, auth AuthService, hub EventService, articles ArticleCache, slog *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		_, uName, acToken := getInfoFromCtx(r)
+
+		if token := r.URL.Query().Get("access_token"); uName == "" && token != "" {
+			ctx, cancel := context.WithTimeout(context.Background(), timeout)
+			_, name, err := auth.Parse(ctx, token)
+			cancel()
+
+			if err != nil {
+				slog.Debug("Can't parse access token of events", "err", err.Error())
+			} else {
+				uName = name
+			}
+		}
+
+		rc := http.NewResponseController(w)
+
+		// Stream outlives write timeout of the server.
+		if err := rc.SetWriteDeadline(time.Time{}); err != nil {
+			slog.Error("Can't stream events", "err", err.Error())
+
+			if err = responseJSONError(w, http.StatusInternalServerError, 0, acToken, "Internal server error"); err != nil {
+				slog.Error("Can't make response", "err", err.Error())
+			}
+			return
+		}
+
+		lastID := r.Header.Get("Last-Event-ID")
+		if lastID == "" {
+			lastID = r.URL.Query().Get("last_event_id")
+		}
+
+		// Subscribe first, so nothing published while missed articles are read is lost.
+		evs, unsubscribe := hub.Subscribe(uName)
+		defer unsubscribe()
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.Header().Set("Connection", "keep-alive")
+		w.Header().Set("X-Accel-Buffering", "no")
+		w.WriteHeader(http.StatusOK)
+
+		sent := make(map[string]struct{})
+
+		if id, err := strconv.ParseInt(lastID, 10, 64); err == nil && id > 0 {
+			ctx, cancel := context.WithTimeout(context.Background(), timeout)
+			missed, err := articles.ArticlesAfter(ctx, id)
+			cancel()
+
+			switch {
+			case err == nil:
+				for _, art := range missed {
+					artJSON, err := json.Marshal(art)
+					if err != nil {
+						slog.Error("Can't marshal article", "err", err.Error())
+						return
+					}
+
+					event := models.Event{ID: strconv.FormatInt(art.ArticleID, 10), Type: models.EventArticle, Data: artJSON}
+
+					if err := writeEvent(w, event, uName); err != nil {
+						return
+					}
+
+					sent[event.ID] = struct{}{}
+				}
+			case errors.Is(err, storage.ErrNotCached):
+				slog.Debug("Can't resume events", "last event id", lastID)
+			default:
+				slog.Error("Can't get missed articles", "err", err.Error())
+			}
+		}
+
+		if err := rc.Flush(); err != nil {
+			slog.Error("Can't flush events", "err", err.Error())
+			return
+		}
+
+		ticker := time.NewTicker(heartbeat)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-r.Context().Done():
+				return
+			case <-ticker.C:
+				if _, err := fmt.Fprint(w, ": heartbeat\n\n"); err != nil {
+					return
+				}
+			case event, ok := <-evs:
+				if !ok {
+					// Client is too slow, browser reconnects with Last-Event-ID.
+					return
+				}
+
+				if _, ok := sent[event.ID]; ok && event.ID != "" {
+					continue
+				}
+
+				if err := writeEvent(w, event, uName); err != nil {
+					return
+				}
+			}
+
+			if err := rc.Flush(); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// writeEvent writes the event and, when the article was offered by the user,
+// change of its submission status.
+func writeEvent(w http.ResponseWriter, event models.Event, uName string) error {
+	if err := writeSSE(w, event); err != nil {
+		return err
+	}
+
+	if event.Type != models.EventArticle || uName == "" {
+		return nil
+	}
+
+	var art models.Article
+
+	if err := json.Unmarshal(event.Data, &art); err != nil || art.UserName != uName {
+		return nil
+	}
+
+	status, err := json.Marshal(models.SubmissionStatus{ArticleID: art.ArticleID, Status: models.StatusPosted})
+	if err != nil {
+		return err
+	}
+
+	return writeSSE(w, models.Event{Type: models.EventSubmission, Data: status})
+}
+
+func writeSSE(w http.ResponseWriter, event models.Event) error {
+	if event.ID != "" {
+		if _, err := fmt.Fprintf(w, "id: %s\n", event.ID); err != nil {
+			return err
+		}
+	}
+
+	_, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.Type, event.Data)
+
+	return err
+}
@@ -0,0 +1,99 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"newsWebApp/app/apiService/internal/models"
+	"newsWebApp/app/apiService/internal/services/feed"
+	"newsWebApp/app/apiService/internal/storage"
+)
+
+const (
+	feedItems = 50
+	// feedScan bounds articles taken from cache before filtering; cache holds fewer.
+	feedScan = 1000
+)
+
+// publicFeed serves the feed of latest articles in the format, /feed.rss, /feed.atom
+// or /feed.json. Articles may be filtered by source and user query parameters.
+func publicFeed(format string, timeout time.Duration, publicURL string, articles ArticleCache, slog *slog.Logger) http.HandlerFunc {
+	contentType := feed.ContentType(format)
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+
+		arts, err := articles.GetLatestArticles(ctx, feedScan)
+		if err != nil && !errors.Is(err, storage.ErrCacheEmpty) {
+			slog.Error("Can't get articles of feed", "err", err.Error())
+
+			if err = responseJSONError(w, http.StatusInternalServerError, 0, "", "Internal server error"); err != nil {
+				slog.Error("Can't make response", "err", err.Error())
+			}
+			return
+		}
+
+		arts = filterFeed(arts, r.URL.Query().Get("source"), r.URL.Query().Get("user"))
+
+		selfURL := strings.TrimRight(publicURL, "/") + "/feed." + format
+		if r.URL.RawQuery != "" {
+			selfURL += "?" + r.URL.RawQuery
+		}
+
+		body, err := feed.Render(format, feed.Meta{
+			Title:       "News",
+			Description: "Latest published articles",
+			HomeURL:     publicURL,
+			SelfURL:     selfURL,
+		}, arts)
+		if err != nil {
+			slog.Error("Can't render feed", "format", format, "err", err.Error())
+
+			if err = responseJSONError(w, http.StatusInternalServerError, 0, "", "Internal server error"); err != nil {
+				slog.Error("Can't make response", "err", err.Error())
+			}
+			return
+		}
+
+		sum := sha256.Sum256(body)
+
+		w.Header().Set("Content-Type", contentType)
+		w.Header().Set("ETag", `"`+hex.EncodeToString(sum[:16])+`"`)
+		w.Header().Set("Cache-Control", "public, max-age=60")
+
+		// ServeContent answers If-None-Match and If-Modified-Since with 304.
+		http.ServeContent(w, r, "", feed.Updated(arts), bytes.NewReader(body))
+	}
+}
+
+// filterFeed keeps articles of the source and the user, empty filter matches any,
+// and returns at most feedItems of them.
+func filterFeed(arts []models.Article, source string, user string) []models.Article {
+	filtered := make([]models.Article, 0, min(len(arts), feedItems))
+
+	for _, art := range arts {
+		if len(filtered) == feedItems {
+			break
+		}
+
+		if source != "" && !strings.EqualFold(art.SourceName, source) {
+			continue
+		}
+
+		if user != "" && !strings.EqualFold(art.UserName, user) {
+			continue
+		}
+
+		filtered = append(filtered, art)
+	}
+
+	return filtered
+}
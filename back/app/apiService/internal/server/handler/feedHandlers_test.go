@@ -0,0 +1,152 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"newsWebApp/app/apiService/internal/models"
+)
+
+type fakeArticleCache struct {
+	articles []models.Article
+}
+
+func (c *fakeArticleCache) ArticlesAfter(ctx context.Context, id int64) ([]models.Article, error) {
+	return nil, nil
+}
+
+func (c *fakeArticleCache) GetLatestArticles(ctx context.Context, n int) ([]models.Article, error) {
+	return c.articles, nil
+}
+
+func TestPublicFeed(t *testing.T) {
+	cache := &fakeArticleCache{articles: []models.Article{
+		{ArticleID: 2, UserName: "alice", Title: "By user", Link: "https://example.com/2", PostedAt: time.Date(2024, 2, 7, 10, 0, 0, 0, time.UTC)},
+		{ArticleID: 1, SourceName: "go.dev", Title: "By bot", Link: "https://example.com/1", PostedAt: time.Date(2024, 2, 6, 10, 0, 0, 0, time.UTC)},
+	}}
+
+	h, err := New(nil, nil, nil, nil, nil, nil, nil, nil, nil, cache, nil,
+		nil, "https://news.example.com", time.Hour, time.Second, time.Second,
+		slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Server is used instead of a recorder, chi wraps writer of ServeContent as io.ReaderFrom.
+	srv := httptest.NewServer(h)
+	defer srv.Close()
+
+	get := func(path string, header http.Header) *http.Response {
+		req, err := http.NewRequest(http.MethodGet, srv.URL+path, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for k, v := range header {
+			req.Header[k] = v
+		}
+
+		resp, err := srv.Client().Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body = io.NopCloser(bytes.NewReader(body))
+
+		return resp
+	}
+
+	t.Run("formats", func(t *testing.T) {
+		for path, contentType := range map[string]string{
+			"/feed.rss":  "application/rss+xml",
+			"/feed.atom": "application/atom+xml",
+			"/feed.json": "application/feed+json",
+		} {
+			rec := get(path, nil)
+
+			if rec.StatusCode != http.StatusOK {
+				t.Errorf("GET %s status = %d, want 200", path, rec.StatusCode)
+				continue
+			}
+
+			if got := rec.Header.Get("Content-Type"); !strings.HasPrefix(got, contentType) {
+				t.Errorf("GET %s Content-Type = %q, want %q", path, got, contentType)
+			}
+
+			if got := rec.Header.Get("Last-Modified"); got != "Wed, 07 Feb 2024 10:00:00 GMT" {
+				t.Errorf("GET %s Last-Modified = %q", path, got)
+			}
+		}
+	})
+
+	t.Run("filter", func(t *testing.T) {
+		rec := get("/feed.json?source=GO.DEV", nil)
+
+		var doc struct {
+			FeedURL string `json:"feed_url"`
+			Items   []struct {
+				ID string `json:"id"`
+			} `json:"items"`
+		}
+		if err := json.NewDecoder(rec.Body).Decode(&doc); err != nil {
+			t.Fatalf("feed isn't valid json: %v", err)
+		}
+
+		if len(doc.Items) != 1 || doc.Items[0].ID != "1" {
+			t.Errorf("items = %+v, want article 1 only", doc.Items)
+		}
+
+		if doc.FeedURL != "https://news.example.com/feed.json?source=GO.DEV" {
+			t.Errorf("feed_url = %q", doc.FeedURL)
+		}
+	})
+
+	t.Run("not modified", func(t *testing.T) {
+		etag := get("/feed.atom", nil).Header.Get("ETag")
+		if etag == "" {
+			t.Fatal("no ETag")
+		}
+
+		if rec := get("/feed.atom", http.Header{"If-None-Match": {etag}}); rec.StatusCode != http.StatusNotModified {
+			t.Errorf("If-None-Match status = %d, want 304", rec.StatusCode)
+		}
+
+		if rec := get("/feed.atom", http.Header{"If-None-Match": {`"other"`}}); rec.StatusCode != http.StatusOK {
+			t.Errorf("other If-None-Match status = %d, want 200", rec.StatusCode)
+		}
+
+		if rec := get("/feed.atom", http.Header{"If-Modified-Since": {"Wed, 07 Feb 2024 10:00:00 GMT"}}); rec.StatusCode != http.StatusNotModified {
+			t.Errorf("If-Modified-Since status = %d, want 304", rec.StatusCode)
+		}
+
+		if rec := get("/feed.rss", http.Header{"If-None-Match": {etag}}); rec.StatusCode != http.StatusOK {
+			t.Errorf("ETag of atom feed for rss status = %d, want 200", rec.StatusCode)
+		}
+	})
+
+	t.Run("other paths keep extension", func(t *testing.T) {
+		for _, path := range []string{"/feed", "/feed.xml"} {
+			if rec := get(path, nil); rec.StatusCode != http.StatusNotFound {
+				t.Errorf("GET %s status = %d, want 404", path, rec.StatusCode)
+			}
+		}
+
+		// Id with extension isn't an id, it would be one if the path was rewritten.
+		body, _ := io.ReadAll(get("/articles/5.json", nil).Body)
+		if !strings.Contains(string(body), `"status":400`) {
+			t.Errorf("GET /articles/5.json body = %s, want bad request", body)
+		}
+	})
+}
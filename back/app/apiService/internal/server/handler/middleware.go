@@ -49,6 +49,57 @@ func authenticate(timeout, refTokTTL time.Duration, service AuthService, slog *s
 	}
 }
 
+func authorizeAdmin(timeout time.Duration, admins []string, service AuthService, slog *slog.Logger) func(http.Handler) http.Handler {
+	adminSet := make(map[string]struct{}, len(admins))
+
+	for _, name := range admins {
+		adminSet[name] = struct{}{}
+	}
+
+	return func(next http.Handler) http.Handler {
+
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			auth := r.Header.Get("Authorization")
+			if auth == "" {
+				slog.Debug("Can't authorize admin, access token is empty")
+
+				err := responseJSONError(w, http.StatusNotFound, 0, "", "Empty Authorization")
+				if err != nil {
+					slog.Error("Can't make response", "err", err.Error())
+				}
+				return
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), timeout)
+			defer cancel()
+
+			id, uName, err := service.Parse(ctx, auth)
+			if err != nil {
+				slog.Debug("Can't authorize admin", "error", err.Error())
+
+				err = responseJSONError(w, http.StatusUnauthorized, 0, "", "Authorization expired")
+				if err != nil {
+					slog.Error("Can't make response", "err", err.Error())
+				}
+				return
+			}
+
+			if _, ok := adminSet[uName]; !ok {
+				slog.Debug("User is not admin", "user name", uName)
+
+				err = responseJSONError(w, http.StatusForbidden, id, "", "Forbidden")
+				if err != nil {
+					slog.Error("Can't make response", "err", err.Error())
+				}
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		}
+		return http.HandlerFunc(fn)
+	}
+}
+
 func refresh(timeout, refTokTTL time.Duration, service AuthService, slog *slog.Logger) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 
@@ -12,6 +12,8 @@ import (
 
 	"newsWebApp/app/apiService/internal/metrics"
 	"newsWebApp/app/apiService/internal/services"
+
+	"github.com/go-chi/chi"
 )
 
 func home(timeout time.Duration, fetcher NewsFetcher, slog *slog.Logger) http.HandlerFunc {
@@ -25,25 +27,20 @@ func home(timeout time.Duration, fetcher NewsFetcher, slog *slog.Logger) http.Ha
 
 		id, uName, acToken := getInfoFromCtx(r)
 
-		currentPage := r.URL.Query().Get("page")
-
-		page, err := strconv.Atoi(currentPage)
-		if err != nil {
-			slog.Debug("Can't convert current page to int", "err", err.Error())
-			statusCode = http.StatusBadRequest
-
-			if err = responseJSONError(w, statusCode, id, acToken, "Bad request"); err != nil {
-				slog.Error("Can't make response", "err", err.Error())
-			}
-			return
-		}
-
 		ctx, cancel := context.WithTimeout(context.Background(), timeout)
 		defer cancel()
 
-		arts, err := fetcher.FetchArticlesOnPage(ctx, int64(page))
+		arts, next, err := fetcher.FetchArticles(ctx, r.URL.Query().Get("cursor"))
 		if err != nil {
 			switch {
+			case errors.Is(err, services.ErrInvalidCursor):
+				slog.Debug("Can't fetch articles", "err", err.Error())
+				statusCode = http.StatusBadRequest
+
+				if err = responseJSONError(w, statusCode, id, acToken, "Bad request"); err != nil {
+					slog.Error("Can't make response", "err", err.Error())
+				}
+				return
 			case errors.Is(err, services.ErrNoPublishedArticles):
 				statusCode = http.StatusNoContent
 
@@ -77,6 +74,7 @@ func home(timeout time.Duration, fetcher NewsFetcher, slog *slog.Logger) http.Ha
 			UserName: uName,
 			AcToken:  acToken,
 			Articles: arts,
+			Cursor:   next,
 		}
 
 		if err = responseJSONOk(w, statusCode, respBody); err != nil {
@@ -85,6 +83,94 @@ func home(timeout time.Duration, fetcher NewsFetcher, slog *slog.Logger) http.Ha
 	}
 }
 
+func article(timeout time.Duration, news UserNewsService, slog *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, uName, acToken := getInfoFromCtx(r)
+
+		artID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+		if err != nil || artID <= 0 {
+			slog.Debug("Can't parse article id", "id", chi.URLParam(r, "id"))
+
+			err = responseJSONError(w, http.StatusBadRequest, id, acToken, "Bad request")
+			if err != nil {
+				slog.Error("Can't make response", "err", err.Error())
+			}
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+
+		art, err := news.GetArticle(ctx, artID)
+		if err != nil {
+			switch {
+			case errors.Is(err, services.ErrArticleNotFound):
+				err = responseJSONError(w, http.StatusNotFound, id, acToken, "Article not found")
+			default:
+				slog.Error("Can't get article", "err", err.Error())
+
+				err = responseJSONError(w, http.StatusInternalServerError, id, acToken, "Internal server error")
+			}
+			if err != nil {
+				slog.Error("Can't make response", "err", err.Error())
+			}
+			return
+		}
+
+		respBody := respBody{
+			UserID:   id,
+			UserName: uName,
+			AcToken:  acToken,
+			Article:  art,
+		}
+
+		if err = responseJSONOk(w, http.StatusOK, respBody); err != nil {
+			slog.Error("Can't make response", "err", err.Error())
+		}
+	}
+}
+
+func search(timeout time.Duration, news UserNewsService, slog *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, uName, acToken := getInfoFromCtx(r)
+
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+
+		results, next, err := news.SearchArticles(ctx, r.URL.Query().Get("q"), r.URL.Query().Get("cursor"))
+		if err != nil {
+			switch {
+			case errors.Is(err, services.ErrEmptyQuery), errors.Is(err, services.ErrInvalidCursor):
+				slog.Debug("Can't search articles", "err", err.Error())
+
+				err = responseJSONError(w, http.StatusBadRequest, id, acToken, "Bad request")
+			case errors.Is(err, services.ErrNothingFound):
+				err = responseJSONError(w, http.StatusNoContent, id, acToken, "Nothing found")
+			default:
+				slog.Error("Can't search articles", "err", err.Error())
+
+				err = responseJSONError(w, http.StatusInternalServerError, id, acToken, "Internal server error")
+			}
+			if err != nil {
+				slog.Error("Can't make response", "err", err.Error())
+			}
+			return
+		}
+
+		respBody := respBody{
+			UserID:   id,
+			UserName: uName,
+			AcToken:  acToken,
+			Results:  results,
+			Cursor:   next,
+		}
+
+		if err = responseJSONOk(w, http.StatusOK, respBody); err != nil {
+			slog.Error("Can't make response", "err", err.Error())
+		}
+	}
+}
+
 func userArticles(timeout time.Duration, news UserNewsService, slog *slog.Logger) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		id, uName, acToken := getInfoFromCtx(r)
@@ -213,6 +299,14 @@ func addArticle(timeout time.Duration, news UserNewsService, slog *slog.Logger)
 					slog.Error("Can't make response", "err", err.Error())
 				}
 				return
+			case errors.Is(err, services.ErrForbiddenUrl):
+				respBody.Articles = arts
+
+				err = responseJSONOk(w, http.StatusForbidden, respBody)
+				if err != nil {
+					slog.Error("Can't make response", "err", err.Error())
+				}
+				return
 			default:
 				slog.Error("Can't save article", "err", err.Error())
 
@@ -327,6 +421,14 @@ func updateArticle(timeout time.Duration, news UserNewsService, slog *slog.Logge
 					slog.Error("Can't make response", "err", err.Error())
 				}
 				return
+			case errors.Is(err, services.ErrForbiddenUrl):
+				respBody.Articles = arts
+
+				err = responseJSONOk(w, http.StatusForbidden, respBody)
+				if err != nil {
+					slog.Error("Can't make response", "err", err.Error())
+				}
+				return
 			default:
 				slog.Error("Can't update article", "err", err.Error())
 
@@ -445,3 +547,66 @@ func deleteArticle(timeout time.Duration, news UserNewsService, slog *slog.Logge
 		}
 	}
 }
+
+type previewRequest struct {
+	Link       string   `json:"link"`
+	Title      string   `json:"title"`
+	Categories []string `json:"categories"`
+	SourceName string   `json:"source_name"`
+}
+
+func previewArticle(timeout time.Duration, news UserNewsService, slog *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		defer r.Body.Close()
+		req := previewRequest{}
+
+		id, uName, acToken := getInfoFromCtx(r)
+
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			if !strings.Contains(err.Error(), "EOF") {
+				slog.Debug("Can't decode body from preview-article request", "err", err.Error())
+
+				err = responseJSONError(w, http.StatusBadRequest, id, acToken, "Bad request")
+				if err != nil {
+					slog.Error("Can't make response", "err", err.Error())
+				}
+				return
+			}
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+
+		preview, err := news.ExplainFilter(ctx, req.Link, req.Title, req.Categories, req.SourceName)
+		if err != nil {
+			switch {
+			case errors.Is(err, services.ErrNothingToExplain):
+				err = responseJSONError(w, http.StatusBadRequest, id, acToken, "Link or title is required")
+			case errors.Is(err, services.ErrInvalidUrl):
+				err = responseJSONError(w, http.StatusMethodNotAllowed, id, acToken, "Url is invalid")
+			case errors.Is(err, services.ErrForbiddenUrl):
+				err = responseJSONError(w, http.StatusForbidden, id, acToken, "Url is forbidden")
+			default:
+				slog.Error("Can't preview article", "err", err.Error())
+
+				err = responseJSONError(w, http.StatusInternalServerError, id, acToken, "Internal error")
+			}
+
+			if err != nil {
+				slog.Error("Can't make response", "err", err.Error())
+			}
+			return
+		}
+
+		respBody := respBody{
+			UserID:   id,
+			UserName: uName,
+			AcToken:  acToken,
+			Preview:  preview,
+		}
+
+		if err = responseJSONOk(w, http.StatusOK, respBody); err != nil {
+			slog.Error("Can't make response", "err", err.Error())
+		}
+	}
+}
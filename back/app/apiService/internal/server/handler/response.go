@@ -13,12 +13,23 @@ type response struct {
 }
 
 type respBody struct {
-	UserID   int64            `json:"uid,omitempty"`
-	UserName string           `json:"user_name,omitempty"`
-	AcToken  string           `json:"access_token,omitempty"`
-	Articles []models.Article `json:"articles,omitempty"`
-	Error    string           `json:"error,omitempty"`
-	Exists   bool             `json:"exists,omitempty"`
+	UserID      int64                      `json:"uid,omitempty"`
+	UserName    string                     `json:"user_name,omitempty"`
+	AcToken     string                     `json:"access_token,omitempty"`
+	Articles    []models.Article           `json:"articles,omitempty"`
+	Article     *models.ReaderArticle      `json:"article,omitempty"`
+	Results     []models.SearchResult      `json:"results,omitempty"`
+	Cursor      string                     `json:"next_cursor,omitempty"`
+	Sources     []models.Source            `json:"sources,omitempty"`
+	Import      *models.OPMLReport         `json:"import,omitempty"`
+	Reload      *models.ReloadReport       `json:"reload,omitempty"`
+	Preview     *models.FilterPreview      `json:"preview,omitempty"`
+	Webhooks    []models.Webhook           `json:"webhooks,omitempty"`
+	Deliveries  []models.WebhookDelivery   `json:"deliveries,omitempty"`
+	Digest      *models.DigestSubscription `json:"digest,omitempty"`
+	Submissions []models.Submission        `json:"submissions,omitempty"`
+	Error       string                     `json:"error,omitempty"`
+	Exists      bool                       `json:"exists,omitempty"`
 }
 
 func responseJSONOk(w http.ResponseWriter, status int, body respBody) error {
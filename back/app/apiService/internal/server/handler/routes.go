@@ -7,6 +7,8 @@ import (
 	"time"
 
 	"newsWebApp/app/apiService/internal/models"
+	"newsWebApp/app/apiService/internal/services/activitypub"
+	"newsWebApp/app/apiService/internal/services/feed"
 
 	chiprometheus "github.com/766b/chi-prometheus"
 	"github.com/go-chi/chi"
@@ -28,32 +30,113 @@ type UserNewsService interface {
 	SaveArticle(ctx context.Context, userID int64, link string) ([]models.Article, error)
 	UpdateArticle(ctx context.Context, userID int64, artID int64, link string) ([]models.Article, error)
 	DeleteArticle(ctx context.Context, userID int64, artID int64) ([]models.Article, error)
+	ExplainFilter(ctx context.Context, link string, title string, categories []string, source string) (*models.FilterPreview, error)
+	GetArticle(ctx context.Context, artID int64) (*models.ReaderArticle, error)
+	SearchArticles(ctx context.Context, query string, cursor string) ([]models.SearchResult, string, error)
+}
+
+type SourceService interface {
+	ListSources(ctx context.Context) ([]models.Source, error)
+	ListSourceHealth(ctx context.Context) ([]models.Source, error)
+	AddSource(ctx context.Context, name string, feedURL string) (*models.Source, error)
+	UpdateSource(ctx context.Context, id int64, name string, feedURL string) (*models.Source, error)
+	DeleteSource(ctx context.Context, id int64) error
+	SetSourceEnabled(ctx context.Context, id int64, enabled bool) (*models.Source, error)
+	ImportOPML(ctx context.Context, document []byte) (*models.OPMLReport, error)
+	ExportOPML(ctx context.Context) ([]byte, error)
+}
+
+type ConfigService interface {
+	ReloadConfig(ctx context.Context) (*models.ReloadReport, error)
+}
+
+type WebhookService interface {
+	ListWebhooks(ctx context.Context) ([]models.Webhook, error)
+	AddWebhook(ctx context.Context, url string, secret string, events []string) (*models.Webhook, error)
+	DeleteWebhook(ctx context.Context, id int64) error
+	ListWebhookDeliveries(ctx context.Context, webhookID int64, status string, limit int64) ([]models.WebhookDelivery, error)
+}
+
+type DigestService interface {
+	GetDigestSubscription(ctx context.Context, userID int64) (*models.DigestSubscription, error)
+	SubscribeDigest(ctx context.Context, userID int64, frequency string) (*models.DigestSubscription, error)
+	UnsubscribeDigest(ctx context.Context, userID int64) error
+	UnsubscribeDigestByToken(ctx context.Context, token string) error
+}
+
+type ModerationService interface {
+	ListPendingSubmissions(ctx context.Context, limit int64) ([]models.Submission, error)
+	ApproveSubmission(ctx context.Context, artID int64, moderator string, note string) (*models.Submission, error)
+	RejectSubmission(ctx context.Context, artID int64, moderator string, reason string) (*models.Submission, error)
+}
+
+type EventService interface {
+	Subscribe(user string) (<-chan models.Event, func())
+	Publish(ctx context.Context, event models.Event) error
+}
+
+type ArticleCache interface {
+	ArticlesAfter(ctx context.Context, id int64) ([]models.Article, error)
+	GetLatestArticles(ctx context.Context, n int) ([]models.Article, error)
+}
+
+// FediverseActor is the ActivityPub actor of the news stream, nil when it's turned off.
+type FediverseActor interface {
+	WebFinger(resource string) (*activitypub.JRD, error)
+	Document() any
+	Outbox(ctx context.Context) (any, error)
+	Followers(ctx context.Context) (any, error)
+	HandleInbox(ctx context.Context, r *http.Request, body []byte) error
 }
 
 type NewsFetcher interface {
-	FetchArticlesOnPage(ctx context.Context, page int64) ([]models.Article, error)
+	FetchArticles(ctx context.Context, cursor string) ([]models.Article, string, error)
 }
 
 func New(auth AuthService,
 	news UserNewsService,
+	sources SourceService,
+	configs ConfigService,
+	webhooks WebhookService,
+	digests DigestService,
+	moderation ModerationService,
 	fetcher NewsFetcher,
+	hub EventService,
+	articles ArticleCache,
+	actor FediverseActor,
 
+	admins []string,
+	publicURL string,
 	refTokTTL time.Duration,
-	refreshInterval time.Duration,
 	timeout time.Duration,
+	heartbeat time.Duration,
 	slog *slog.Logger,
 ) (http.Handler, error) {
 	r := chi.NewRouter()
 
 	r.Use(middleware.RequestID)
 	r.Use(middleware.Recoverer)
-	r.Use(middleware.URLFormat)
 	r.Use(chiprometheus.NewMiddleware("apisrv"))
 	r.Use(loggerMw(slog))
 	r.Use(corsSettings())
 	r.Use(refresh(timeout, refTokTTL, auth, slog))
 
 	r.Get("/home", home(timeout, fetcher, slog))
+	r.Get("/articles/{id}", article(timeout, news, slog))
+	r.Get("/search", search(timeout, news, slog))
+	r.Get("/events", streamEvents(timeout, heartbeat, auth, hub, articles, slog))
+	r.Get("/feed.rss", publicFeed(feed.FormatRSS, timeout, publicURL, articles, slog))
+	r.Get("/feed.atom", publicFeed(feed.FormatAtom, timeout, publicURL, articles, slog))
+	r.Get("/feed.json", publicFeed(feed.FormatJSON, timeout, publicURL, articles, slog))
+
+	if actor != nil {
+		r.Get("/.well-known/webfinger", webFinger(actor, slog))
+		r.Get("/ap/actor", actorDocument(actor, slog))
+		r.Get("/ap/outbox", actorOutbox(timeout, actor, slog))
+		r.Get("/ap/followers", actorFollowers(timeout, actor, slog))
+		r.Post("/ap/inbox", actorInbox(timeout, actor, slog))
+	}
+
 	r.Post("/signup", signup(timeout, auth, slog))
 	r.Post("/login", login(timeout, refTokTTL, auth, slog))
 
@@ -66,6 +149,52 @@ func New(auth AuthService,
 		r.Post("/", addArticle(timeout, news, slog))
 		r.Put("/", updateArticle(timeout, news, slog))
 		r.Delete("/", deleteArticle(timeout, news, slog))
+		r.Post("/preview", previewArticle(timeout, news, slog))
+	})
+
+	r.Route("/user/digest", func(r chi.Router) {
+		// Links from digests work without logging in.
+		r.Get("/unsubscribe", confirmUnsubscribe(slog))
+		r.Post("/unsubscribe", unsubscribeByToken(timeout, digests, slog))
+
+		r.Group(func(r chi.Router) {
+			r.Use(authenticate(timeout, refTokTTL, auth, slog))
+			r.Get("/", getDigest(timeout, digests, slog))
+			r.Put("/", subscribeDigest(timeout, digests, slog))
+			r.Delete("/", unsubscribeDigest(timeout, digests, slog))
+		})
+	})
+
+	r.Route("/admin/sources", func(r chi.Router) {
+		r.Use(authorizeAdmin(timeout, admins, auth, slog))
+		r.Get("/", listSources(timeout, sources, slog))
+		r.Get("/health", sourcesHealth(timeout, sources, slog))
+		r.Post("/", addSource(timeout, sources, slog))
+		r.Put("/", updateSource(timeout, sources, slog))
+		r.Put("/enabled", setSourceEnabled(timeout, sources, slog))
+		r.Delete("/", deleteSource(timeout, sources, slog))
+		r.Get("/opml", exportOPML(timeout, sources, slog))
+		r.Post("/opml", importOPML(timeout, sources, slog))
+	})
+
+	r.Route("/admin/webhooks", func(r chi.Router) {
+		r.Use(authorizeAdmin(timeout, admins, auth, slog))
+		r.Get("/", listWebhooks(timeout, webhooks, slog))
+		r.Post("/", addWebhook(timeout, webhooks, slog))
+		r.Delete("/", deleteWebhook(timeout, webhooks, slog))
+		r.Get("/deliveries", webhookDeliveries(timeout, webhooks, slog))
+	})
+
+	r.Route("/admin/submissions", func(r chi.Router) {
+		r.Use(authorizeAdmin(timeout, admins, auth, slog))
+		r.Get("/", pendingSubmissions(timeout, moderation, slog))
+		r.Post("/approve", approveSubmission(timeout, moderation, hub, slog))
+		r.Post("/reject", rejectSubmission(timeout, moderation, hub, slog))
+	})
+
+	r.Route("/admin/config", func(r chi.Router) {
+		r.Use(authorizeAdmin(timeout, admins, auth, slog))
+		r.Post("/reload", reloadConfig(timeout, configs, slog))
 	})
 
 	r.Handle("/metrics", promhttp.Handler())
@@ -0,0 +1,190 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"newsWebApp/app/apiService/internal/models"
+	"newsWebApp/app/apiService/internal/services"
+)
+
+type submissionRequest struct {
+	ArticleID int64  `json:"article_id"`
+	Note      string `json:"note"`
+	Reason    string `json:"reason"`
+}
+
+// pendingSubmissions returns articles offered by users which wait for a moderator,
+// the oldest first. Their number is set by limit query parameter.
+func pendingSubmissions(timeout time.Duration, moderation ModerationService, slog *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, uName, acToken := getInfoFromCtx(r)
+
+		var limit int64
+
+		if v := r.URL.Query().Get("limit"); v != "" {
+			var err error
+
+			limit, err = strconv.ParseInt(v, 10, 64)
+			if err != nil {
+				slog.Debug("Can't parse pending submissions query", "err", err.Error())
+
+				if err = responseJSONError(w, http.StatusBadRequest, id, acToken, "Bad request"); err != nil {
+					slog.Error("Can't make response", "err", err.Error())
+				}
+				return
+			}
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+
+		submissions, err := moderation.ListPendingSubmissions(ctx, limit)
+		if err != nil {
+			responseSubmissionError(w, err, id, acToken, slog)
+			return
+		}
+
+		respBody := respBody{
+			UserID:      id,
+			UserName:    uName,
+			AcToken:     acToken,
+			Submissions: submissions,
+		}
+
+		if err = responseJSONOk(w, http.StatusOK, respBody); err != nil {
+			slog.Error("Can't make response", "err", err.Error())
+		}
+	}
+}
+
+// approveSubmission lets the article be posted. Note is optional and isn't shown to the user.
+func approveSubmission(timeout time.Duration, moderation ModerationService, hub EventService, slog *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		defer r.Body.Close()
+		req := submissionRequest{}
+
+		id, uName, acToken := getInfoFromCtx(r)
+
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			if !strings.Contains(err.Error(), "EOF") {
+				slog.Debug("Can't decode body from approve-submission request", "err", err.Error())
+
+				err = responseJSONError(w, http.StatusBadRequest, id, acToken, "Bad request")
+				if err != nil {
+					slog.Error("Can't make response", "err", err.Error())
+				}
+				return
+			}
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+
+		sub, err := moderation.ApproveSubmission(ctx, req.ArticleID, uName, req.Note)
+		if err != nil {
+			responseSubmissionError(w, err, id, acToken, slog)
+			return
+		}
+
+		notifySubmitter(ctx, hub, sub, slog)
+
+		respBody := respBody{
+			UserID:      id,
+			UserName:    uName,
+			AcToken:     acToken,
+			Submissions: []models.Submission{*sub},
+		}
+
+		if err = responseJSONOk(w, http.StatusOK, respBody); err != nil {
+			slog.Error("Can't make response", "err", err.Error())
+		}
+	}
+}
+
+// rejectSubmission keeps the article from posting. Reason is required, it's shown to the user.
+func rejectSubmission(timeout time.Duration, moderation ModerationService, hub EventService, slog *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		defer r.Body.Close()
+		req := submissionRequest{}
+
+		id, uName, acToken := getInfoFromCtx(r)
+
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			if !strings.Contains(err.Error(), "EOF") {
+				slog.Debug("Can't decode body from reject-submission request", "err", err.Error())
+
+				err = responseJSONError(w, http.StatusBadRequest, id, acToken, "Bad request")
+				if err != nil {
+					slog.Error("Can't make response", "err", err.Error())
+				}
+				return
+			}
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+
+		sub, err := moderation.RejectSubmission(ctx, req.ArticleID, uName, req.Reason)
+		if err != nil {
+			responseSubmissionError(w, err, id, acToken, slog)
+			return
+		}
+
+		notifySubmitter(ctx, hub, sub, slog)
+
+		respBody := respBody{
+			UserID:      id,
+			UserName:    uName,
+			AcToken:     acToken,
+			Submissions: []models.Submission{*sub},
+		}
+
+		if err = responseJSONOk(w, http.StatusOK, respBody); err != nil {
+			slog.Error("Can't make response", "err", err.Error())
+		}
+	}
+}
+
+// notifySubmitter sends new status of the submission to browsers of its user.
+// Decision is made already, so failure is only logged.
+func notifySubmitter(ctx context.Context, hub EventService, sub *models.Submission, slog *slog.Logger) {
+	status, err := json.Marshal(models.SubmissionStatus{
+		ArticleID: sub.ArticleID,
+		Status:    sub.Status,
+		Reason:    sub.Reason,
+	})
+	if err != nil {
+		slog.Error("Can't marshal submission status", "err", err.Error())
+		return
+	}
+
+	if err := hub.Publish(ctx, models.Event{Type: models.EventSubmission, User: sub.UserName, Data: status}); err != nil {
+		slog.Warn("Can't notify user about submission", "article id", sub.ArticleID, "err", err.Error())
+	}
+}
+
+func responseSubmissionError(w http.ResponseWriter, err error, id int64, acToken string, slog *slog.Logger) {
+	switch {
+	case errors.Is(err, services.ErrReasonRequired):
+		err = responseJSONError(w, http.StatusBadRequest, id, acToken, "Reason is required")
+	case errors.Is(err, services.ErrSubmissionNotFound):
+		err = responseJSONError(w, http.StatusNotFound, id, acToken, "Submission not found")
+	case errors.Is(err, services.ErrNotPending):
+		err = responseJSONError(w, http.StatusConflict, id, acToken, "Submission isn't pending")
+	default:
+		slog.Error("Can't moderate submissions", "err", err.Error())
+
+		err = responseJSONError(w, http.StatusInternalServerError, id, acToken, "Internal error")
+	}
+
+	if err != nil {
+		slog.Error("Can't make response", "err", err.Error())
+	}
+}
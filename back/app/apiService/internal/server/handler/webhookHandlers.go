@@ -0,0 +1,201 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"newsWebApp/app/apiService/internal/models"
+	"newsWebApp/app/apiService/internal/services"
+)
+
+type webhookRequest struct {
+	WebhookID int64    `json:"webhook_id"`
+	URL       string   `json:"url"`
+	Secret    string   `json:"secret"`
+	Events    []string `json:"events"`
+}
+
+func listWebhooks(timeout time.Duration, webhooks WebhookService, slog *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, uName, acToken := getInfoFromCtx(r)
+
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+
+		hooks, err := webhooks.ListWebhooks(ctx)
+		if err != nil {
+			responseWebhookError(w, err, id, acToken, slog)
+			return
+		}
+
+		respBody := respBody{
+			UserID:   id,
+			UserName: uName,
+			AcToken:  acToken,
+			Webhooks: hooks,
+		}
+
+		if err = responseJSONOk(w, http.StatusOK, respBody); err != nil {
+			slog.Error("Can't make response", "err", err.Error())
+		}
+	}
+}
+
+// addWebhook subscribes the url to events. Secret is generated when it's not given,
+// it's returned in this response only.
+func addWebhook(timeout time.Duration, webhooks WebhookService, slog *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		defer r.Body.Close()
+		req := webhookRequest{}
+
+		id, uName, acToken := getInfoFromCtx(r)
+
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			if !strings.Contains(err.Error(), "EOF") {
+				slog.Debug("Can't decode body from add-webhook request", "err", err.Error())
+
+				err = responseJSONError(w, http.StatusBadRequest, id, acToken, "Bad request")
+				if err != nil {
+					slog.Error("Can't make response", "err", err.Error())
+				}
+				return
+			}
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+
+		hook, err := webhooks.AddWebhook(ctx, req.URL, req.Secret, req.Events)
+		if err != nil {
+			responseWebhookError(w, err, id, acToken, slog)
+			return
+		}
+
+		respBody := respBody{
+			UserID:   id,
+			UserName: uName,
+			AcToken:  acToken,
+			Webhooks: []models.Webhook{*hook},
+		}
+
+		if err = responseJSONOk(w, http.StatusCreated, respBody); err != nil {
+			slog.Error("Can't make response", "err", err.Error())
+		}
+	}
+}
+
+func deleteWebhook(timeout time.Duration, webhooks WebhookService, slog *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		defer r.Body.Close()
+		req := webhookRequest{}
+
+		id, uName, acToken := getInfoFromCtx(r)
+
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			if !strings.Contains(err.Error(), "EOF") {
+				slog.Debug("Can't decode body from delete-webhook request", "err", err.Error())
+
+				err = responseJSONError(w, http.StatusBadRequest, id, acToken, "Bad request")
+				if err != nil {
+					slog.Error("Can't make response", "err", err.Error())
+				}
+				return
+			}
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+
+		if err := webhooks.DeleteWebhook(ctx, req.WebhookID); err != nil {
+			responseWebhookError(w, err, id, acToken, slog)
+			return
+		}
+
+		respBody := respBody{
+			UserID:   id,
+			UserName: uName,
+			AcToken:  acToken,
+		}
+
+		if err := responseJSONOk(w, http.StatusOK, respBody); err != nil {
+			slog.Error("Can't make response", "err", err.Error())
+		}
+	}
+}
+
+// webhookDeliveries returns delivery log, newest first. It's filtered by webhook_id
+// and status (pending, delivered or dead) query parameters.
+func webhookDeliveries(timeout time.Duration, webhooks WebhookService, slog *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, uName, acToken := getInfoFromCtx(r)
+
+		query := r.URL.Query()
+
+		var hookID, limit int64
+		var err error
+
+		if v := query.Get("webhook_id"); v != "" {
+			hookID, err = strconv.ParseInt(v, 10, 64)
+		}
+
+		if v := query.Get("limit"); v != "" && err == nil {
+			limit, err = strconv.ParseInt(v, 10, 64)
+		}
+
+		if err != nil {
+			slog.Debug("Can't parse webhook deliveries query", "err", err.Error())
+
+			if err = responseJSONError(w, http.StatusBadRequest, id, acToken, "Bad request"); err != nil {
+				slog.Error("Can't make response", "err", err.Error())
+			}
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+
+		deliveries, err := webhooks.ListWebhookDeliveries(ctx, hookID, query.Get("status"), limit)
+		if err != nil {
+			responseWebhookError(w, err, id, acToken, slog)
+			return
+		}
+
+		respBody := respBody{
+			UserID:     id,
+			UserName:   uName,
+			AcToken:    acToken,
+			Deliveries: deliveries,
+		}
+
+		if err = responseJSONOk(w, http.StatusOK, respBody); err != nil {
+			slog.Error("Can't make response", "err", err.Error())
+		}
+	}
+}
+
+func responseWebhookError(w http.ResponseWriter, err error, id int64, acToken string, slog *slog.Logger) {
+	switch {
+	case errors.Is(err, services.ErrNoWebhooks):
+		err = responseJSONError(w, http.StatusNoContent, id, acToken, "There are no webhooks")
+	case errors.Is(err, services.ErrInvalidWebhook):
+		err = responseJSONError(w, http.StatusBadRequest, id, acToken, "Invalid webhook")
+	case errors.Is(err, services.ErrInvalidStatus):
+		err = responseJSONError(w, http.StatusBadRequest, id, acToken, "Invalid status")
+	case errors.Is(err, services.ErrWebhookNotFound):
+		err = responseJSONError(w, http.StatusNotFound, id, acToken, "Webhook not found")
+	default:
+		slog.Error("Can't manage webhooks", "err", err.Error())
+
+		err = responseJSONError(w, http.StatusInternalServerError, id, acToken, "Internal error")
+	}
+
+	if err != nil {
+		slog.Error("Can't make response", "err", err.Error())
+	}
+}
@@ -0,0 +1,599 @@
+// Package activitypub lets fediverse users follow the news stream. Published articles
+// are sent to followers as Create{Note} activities signed with HTTP signatures.
+package activitypub
+
+import (
+	"bytes"
+	"context"
+	"crypto/rsa"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"newsWebApp/app/apiService/internal/models"
+	"newsWebApp/app/apiService/internal/services"
+	"newsWebApp/app/apiService/internal/storage"
+	"newsWebApp/pkg/netguard"
+)
+
+const (
+	ContentType = "application/activity+json"
+
+	publicAudience = "https://www.w3.org/ns/activitystreams#Public"
+	outboxItems    = 20
+	maxDocument    = 1 << 20
+	// deliveryWorkers limits inboxes receiving activities at a time.
+	deliveryWorkers  = 8
+	deliveryAttempts = 5
+	// deliveryBackoff is the delay before the first retry, it's doubled on every attempt.
+	deliveryBackoff = 30 * time.Second
+)
+
+var activityContext = []string{"https://www.w3.org/ns/activitystreams", "https://w3id.org/security/v1"}
+
+type FollowerService interface {
+	AddFollower(ctx context.Context, follower models.Follower) error
+	RemoveFollower(ctx context.Context, actorID string) error
+	ListFollowers(ctx context.Context) ([]models.Follower, error)
+}
+
+type ArticleSource interface {
+	GetLatestArticles(ctx context.Context, n int) ([]models.Article, error)
+}
+
+// Actor is the single ActivityPub actor of the news stream.
+type Actor struct {
+	baseURL string
+	host    string
+	name    string
+
+	key       *rsa.PrivateKey
+	publicPEM string
+
+	followers FollowerService
+	articles  ArticleSource
+	client    *http.Client
+	insecure  bool
+	timeout   time.Duration
+	queue     *queue
+	backoff   time.Duration
+
+	log *slog.Logger
+}
+
+// New returns actor named name served from baseURL. Insecure actor talks to plain
+// http and private addresses, it's meant for tests against local servers. Activities
+// are delivered while Start runs.
+func New(baseURL string,
+	name string,
+	key *rsa.PrivateKey,
+	followers FollowerService,
+	articles ArticleSource,
+	timeout time.Duration,
+	insecure bool,
+	log *slog.Logger,
+) (*Actor, error) {
+	const op = "services.activitypub.new"
+
+	base, err := url.Parse(strings.TrimRight(baseURL, "/"))
+	if err != nil || base.Host == "" {
+		return nil, fmt.Errorf("%s: invalid base url %q", op, baseURL)
+	}
+
+	publicPEM, err := publicKeyPEM(&key.PublicKey)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &Actor{
+		baseURL:   base.String(),
+		host:      base.Host,
+		name:      name,
+		key:       key,
+		publicPEM: publicPEM,
+		followers: followers,
+		articles:  articles,
+		client:    newClient(timeout, insecure),
+		insecure:  insecure,
+		timeout:   timeout,
+		queue:     newQueue(),
+		backoff:   deliveryBackoff,
+		log:       log,
+	}, nil
+}
+
+func (a *Actor) ID() string {
+	return a.baseURL + "/ap/actor"
+}
+
+func (a *Actor) keyID() string {
+	return a.ID() + "#main-key"
+}
+
+func (a *Actor) followersID() string {
+	return a.baseURL + "/ap/followers"
+}
+
+type Link struct {
+	Rel  string `json:"rel"`
+	Type string `json:"type,omitempty"`
+	Href string `json:"href"`
+}
+
+// JRD is a WebFinger document.
+type JRD struct {
+	Subject string   `json:"subject"`
+	Aliases []string `json:"aliases,omitempty"`
+	Links   []Link   `json:"links"`
+}
+
+// WebFinger resolves acct:name@host or id of the actor to its document.
+func (a *Actor) WebFinger(resource string) (*JRD, error) {
+	if resource != "acct:"+a.name+"@"+a.host && resource != a.ID() {
+		return nil, services.ErrUnknownResource
+	}
+
+	return &JRD{
+		Subject: "acct:" + a.name + "@" + a.host,
+		Aliases: []string{a.ID()},
+		Links: []Link{
+			{Rel: "self", Type: ContentType, Href: a.ID()},
+			{Rel: "http://webfinger.net/rel/profile-page", Type: "text/html", Href: a.baseURL},
+		},
+	}, nil
+}
+
+type publicKey struct {
+	ID           string `json:"id"`
+	Owner        string `json:"owner"`
+	PublicKeyPem string `json:"publicKeyPem"`
+}
+
+type endpoints struct {
+	SharedInbox string `json:"sharedInbox,omitempty"`
+}
+
+type actorDocument struct {
+	Context           []string  `json:"@context"`
+	ID                string    `json:"id"`
+	Type              string    `json:"type"`
+	PreferredUsername string    `json:"preferredUsername"`
+	Name              string    `json:"name"`
+	Summary           string    `json:"summary"`
+	URL               string    `json:"url"`
+	Inbox             string    `json:"inbox"`
+	Outbox            string    `json:"outbox"`
+	Followers         string    `json:"followers"`
+	ManuallyApproves  bool      `json:"manuallyApprovesFollowers"`
+	Discoverable      bool      `json:"discoverable"`
+	PublicKey         publicKey `json:"publicKey"`
+	Endpoints         endpoints `json:"endpoints"`
+}
+
+// Document returns the actor document with its public key.
+func (a *Actor) Document() any {
+	return actorDocument{
+		Context:           activityContext,
+		ID:                a.ID(),
+		Type:              "Service",
+		PreferredUsername: a.name,
+		Name:              "News",
+		Summary:           "Curated news stream",
+		URL:               a.baseURL,
+		Inbox:             a.baseURL + "/ap/inbox",
+		Outbox:            a.baseURL + "/ap/outbox",
+		Followers:         a.followersID(),
+		Discoverable:      true,
+		PublicKey: publicKey{
+			ID:           a.keyID(),
+			Owner:        a.ID(),
+			PublicKeyPem: a.publicPEM,
+		},
+		Endpoints: endpoints{SharedInbox: a.baseURL + "/ap/inbox"},
+	}
+}
+
+type orderedCollection struct {
+	Context      string     `json:"@context"`
+	ID           string     `json:"id"`
+	Type         string     `json:"type"`
+	TotalItems   int        `json:"totalItems"`
+	OrderedItems []activity `json:"orderedItems,omitempty"`
+}
+
+// Outbox returns Create activities of the newest published articles.
+func (a *Actor) Outbox(ctx context.Context) (any, error) {
+	const op = "services.activitypub.outbox"
+
+	articles, err := a.articles.GetLatestArticles(ctx, outboxItems)
+	if err != nil && !errors.Is(err, storage.ErrCacheEmpty) {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	outbox := orderedCollection{
+		Context:      activityContext[0],
+		ID:           a.baseURL + "/ap/outbox",
+		Type:         "OrderedCollection",
+		OrderedItems: make([]activity, 0, len(articles)),
+	}
+
+	for _, article := range articles {
+		outbox.OrderedItems = append(outbox.OrderedItems, a.create(article))
+	}
+
+	outbox.TotalItems = len(outbox.OrderedItems)
+
+	return outbox, nil
+}
+
+// Followers returns collection of followers with their number only.
+func (a *Actor) Followers(ctx context.Context) (any, error) {
+	const op = "services.activitypub.followers"
+
+	followers, err := a.followers.ListFollowers(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return orderedCollection{
+		Context:    activityContext[0],
+		ID:         a.followersID(),
+		Type:       "OrderedCollection",
+		TotalItems: len(followers),
+	}, nil
+}
+
+type note struct {
+	ID           string   `json:"id"`
+	Type         string   `json:"type"`
+	AttributedTo string   `json:"attributedTo"`
+	Content      string   `json:"content"`
+	URL          string   `json:"url"`
+	Published    string   `json:"published"`
+	To           []string `json:"to"`
+	CC           []string `json:"cc"`
+}
+
+type activity struct {
+	Context   any             `json:"@context,omitempty"`
+	ID        string          `json:"id"`
+	Type      string          `json:"type"`
+	Actor     string          `json:"actor"`
+	Published string          `json:"published,omitempty"`
+	To        []string        `json:"to,omitempty"`
+	CC        []string        `json:"cc,omitempty"`
+	Object    json.RawMessage `json:"object"`
+}
+
+// create returns Create{Note} of the article.
+func (a *Actor) create(article models.Article) activity {
+	noteID := a.baseURL + "/ap/notes/" + strconv.FormatInt(article.ArticleID, 10)
+	published := article.PostedAt.UTC().Format(time.RFC3339)
+
+	content := `<p><a href="` + html.EscapeString(article.Link) + `">` + html.EscapeString(article.Title) + "</a></p>"
+	if article.Excerpt != "" {
+		content += "<p>" + html.EscapeString(article.Excerpt) + "</p>"
+	}
+	if article.SourceName != "" {
+		content += "<p>" + html.EscapeString(article.SourceName) + "</p>"
+	}
+
+	object, _ := json.Marshal(note{
+		ID:           noteID,
+		Type:         "Note",
+		AttributedTo: a.ID(),
+		Content:      content,
+		URL:          article.Link,
+		Published:    published,
+		To:           []string{publicAudience},
+		CC:           []string{a.followersID()},
+	})
+
+	return activity{
+		ID:        noteID + "/activity",
+		Type:      "Create",
+		Actor:     a.ID(),
+		Published: published,
+		To:        []string{publicAudience},
+		CC:        []string{a.followersID()},
+		Object:    object,
+	}
+}
+
+// remoteActor is an actor document or, for key ids without fragment, a key document
+// with Owner and PublicKeyPem at the top level.
+type remoteActor struct {
+	ID           string    `json:"id"`
+	Inbox        string    `json:"inbox"`
+	Endpoints    endpoints `json:"endpoints"`
+	PublicKey    publicKey `json:"publicKey"`
+	Owner        string    `json:"owner"`
+	PublicKeyPem string    `json:"publicKeyPem"`
+}
+
+// HandleInbox verifies signature of the activity posted to the inbox and applies
+// Follow, Undo{Follow} and Delete of the follower. Other activities are ignored.
+func (a *Actor) HandleInbox(ctx context.Context, req *http.Request, body []byte) error {
+	incoming := activity{}
+
+	if err := json.Unmarshal(body, &incoming); err != nil || incoming.Actor == "" {
+		return services.ErrInvalidActivity
+	}
+
+	sig, err := parseSignature(req)
+	if err != nil {
+		a.log.Debug("Can't verify inbox activity", "err", err.Error())
+		return services.ErrInvalidSignature
+	}
+
+	senderKey, err := a.fetchKey(ctx, sig.keyID)
+	if err != nil {
+		a.log.Debug("Can't get key of inbox activity", "key id", sig.keyID, "err", err.Error())
+		return services.ErrInvalidSignature
+	}
+
+	key, err := parsePublicKey(senderKey.PublicKeyPem)
+	if err != nil || senderKey.Owner != incoming.Actor {
+		a.log.Debug("Key doesn't belong to the actor", "key id", sig.keyID, "actor", incoming.Actor)
+		return services.ErrInvalidSignature
+	}
+
+	if err := sig.verify(req, body, key); err != nil {
+		a.log.Debug("Can't verify inbox activity", "actor", incoming.Actor, "err", err.Error())
+		return services.ErrInvalidSignature
+	}
+
+	switch incoming.Type {
+	case "Follow":
+		if objectID(incoming.Object) != a.ID() {
+			return nil
+		}
+		return a.follow(ctx, incoming, body)
+	case "Undo":
+		undone := activity{}
+		if err := json.Unmarshal(incoming.Object, &undone); err != nil || undone.Type != "Follow" || undone.Actor != incoming.Actor {
+			return nil
+		}
+		return a.unfollow(ctx, incoming.Actor)
+	case "Delete":
+		if objectID(incoming.Object) != incoming.Actor {
+			return nil
+		}
+		return a.unfollow(ctx, incoming.Actor)
+	default:
+		a.log.Debug("Inbox activity ignored", "type", incoming.Type, "actor", incoming.Actor)
+		return nil
+	}
+}
+
+func (a *Actor) follow(ctx context.Context, follow activity, body []byte) error {
+	const op = "services.activitypub.follow"
+
+	follower, err := a.fetchActor(ctx, follow.Actor)
+	if err != nil || follower.Inbox == "" {
+		a.log.Debug("Can't get follower", "actor", follow.Actor, "err", err)
+		return services.ErrInvalidActivity
+	}
+
+	if err := a.followers.AddFollower(ctx, models.Follower{
+		ActorID:     follower.ID,
+		Inbox:       follower.Inbox,
+		SharedInbox: follower.Endpoints.SharedInbox,
+	}); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	a.log.Info("New follower", "actor", follower.ID)
+
+	accept, err := json.Marshal(activity{
+		Context: activityContext[0],
+		ID:      a.baseURL + "/ap/accepts/" + strconv.FormatInt(time.Now().UnixNano(), 36),
+		Type:    "Accept",
+		Actor:   a.ID(),
+		Object:  body,
+	})
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	// Follower waits for Accept after the inbox has answered.
+	a.queue.push(queued{inbox: follower.Inbox, payload: accept, due: time.Now()})
+
+	return nil
+}
+
+func (a *Actor) unfollow(ctx context.Context, actorID string) error {
+	const op = "services.activitypub.unfollow"
+
+	if err := a.followers.RemoveFollower(ctx, actorID); err != nil {
+		if errors.Is(err, services.ErrFollowerNotFound) {
+			return nil
+		}
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	a.log.Info("Follower left", "actor", actorID)
+
+	return nil
+}
+
+// Announce queues Create{Note} of the article for inboxes of all followers.
+func (a *Actor) Announce(article models.Article) {
+	create := a.create(article)
+	create.Context = activityContext[0]
+
+	payload, err := json.Marshal(create)
+	if err != nil {
+		a.log.Error("Can't marshal activity", "article id", article.ArticleID, "err", err.Error())
+		return
+	}
+
+	a.queue.push(queued{payload: payload, due: time.Now()})
+}
+
+// post sends payload to the inbox and tells whether a failed request may be retried.
+func (a *Actor) post(ctx context.Context, inbox string, payload []byte) (bool, error) {
+	if err := a.checkURL(inbox); err != nil {
+		return false, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, a.timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, inbox, bytes.NewReader(payload))
+	if err != nil {
+		return false, err
+	}
+
+	req.Header.Set("Content-Type", ContentType)
+	req.Header.Set("Accept", ContentType)
+
+	if err := signRequest(req, payload, a.keyID(), a.key); err != nil {
+		return false, err
+	}
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	defer resp.Body.Close()
+
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, maxDocument))
+
+	if resp.StatusCode >= 200 && resp.StatusCode <= 299 {
+		return false, nil
+	}
+
+	retry := resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests
+
+	return retry, fmt.Errorf("inbox responded %s", resp.Status)
+}
+
+// fetchKey gets public key by its id. Key id is either id of the actor with a fragment,
+// then the key is in the actor document, or url of a separate key document. Key which
+// isn't served by the document of its owner must be listed by the owner as well.
+func (a *Actor) fetchKey(ctx context.Context, keyID string) (*publicKey, error) {
+	docID, _, _ := strings.Cut(keyID, "#")
+
+	doc, err := a.fetchDocument(ctx, docID)
+	if err != nil {
+		return nil, err
+	}
+
+	var key publicKey
+
+	switch {
+	case doc.PublicKey.ID == keyID:
+		key = doc.PublicKey
+	case doc.ID == keyID && doc.PublicKeyPem != "":
+		key = publicKey{ID: doc.ID, Owner: doc.Owner, PublicKeyPem: doc.PublicKeyPem}
+	default:
+		return nil, fmt.Errorf("document %q has no key %q", docID, keyID)
+	}
+
+	if key.Owner == "" {
+		return nil, fmt.Errorf("key %q has no owner", keyID)
+	}
+
+	if doc.ID == docID && key.Owner == doc.ID {
+		return &key, nil
+	}
+
+	owner, err := a.fetchActor(ctx, key.Owner)
+	if err != nil {
+		return nil, fmt.Errorf("can't get owner of key %q: %w", keyID, err)
+	}
+
+	if owner.PublicKey.ID != keyID || owner.PublicKey.Owner != owner.ID {
+		return nil, fmt.Errorf("actor %q doesn't own key %q", owner.ID, keyID)
+	}
+
+	return &owner.PublicKey, nil
+}
+
+// fetchActor gets actor document by its id.
+func (a *Actor) fetchActor(ctx context.Context, id string) (*remoteActor, error) {
+	actor, err := a.fetchDocument(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if actor.ID != id {
+		return nil, fmt.Errorf("actor document %q has id %q", id, actor.ID)
+	}
+
+	return actor, nil
+}
+
+// fetchDocument gets ActivityPub document by its url. Request is signed, servers in
+// secure mode answer only signed requests.
+func (a *Actor) fetchDocument(ctx context.Context, id string) (*remoteActor, error) {
+	if err := a.checkURL(id); err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, id, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Accept", ContentType)
+
+	if err := signRequest(req, nil, a.keyID(), a.key); err != nil {
+		return nil, err
+	}
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("document responded %s", resp.Status)
+	}
+
+	doc := remoteActor{}
+
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxDocument)).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("can't decode document: %w", err)
+	}
+
+	return &doc, nil
+}
+
+func (a *Actor) checkURL(value string) error {
+	u, err := url.Parse(value)
+	if err != nil || u.Host == "" {
+		return fmt.Errorf("invalid url %q", value)
+	}
+
+	if u.Scheme != "https" && !(a.insecure && u.Scheme == "http") {
+		return fmt.Errorf("%w: %s scheme", netguard.ErrForbiddenAddress, u.Scheme)
+	}
+
+	return nil
+}
+
+// objectID returns id of the object given as a link or as an embedded object.
+func objectID(object json.RawMessage) string {
+	var id string
+	if err := json.Unmarshal(object, &id); err == nil {
+		return id
+	}
+
+	embedded := struct {
+		ID string `json:"id"`
+	}{}
+	_ = json.Unmarshal(object, &embedded)
+
+	return embedded.ID
+}
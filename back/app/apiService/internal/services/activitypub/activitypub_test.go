@@ -0,0 +1,535 @@
+package activitypub
+
+import (
+	"bytes"
+	"context"
+	"crypto/rsa"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"newsWebApp/app/apiService/internal/models"
+	"newsWebApp/app/apiService/internal/services"
+)
+
+const testBaseURL = "https://news.example"
+
+// fakeFollowers keeps followers in memory, first listFailures lists of them fail.
+type fakeFollowers struct {
+	mu           sync.Mutex
+	followers    map[string]models.Follower
+	listFailures int
+}
+
+func newFakeFollowers(followers ...models.Follower) *fakeFollowers {
+	f := &fakeFollowers{followers: make(map[string]models.Follower)}
+
+	for _, follower := range followers {
+		f.followers[follower.ActorID] = follower
+	}
+
+	return f
+}
+
+func (f *fakeFollowers) AddFollower(ctx context.Context, follower models.Follower) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.followers[follower.ActorID] = follower
+	return nil
+}
+
+func (f *fakeFollowers) RemoveFollower(ctx context.Context, actorID string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if _, ok := f.followers[actorID]; !ok {
+		return services.ErrFollowerNotFound
+	}
+
+	delete(f.followers, actorID)
+	return nil
+}
+
+func (f *fakeFollowers) ListFollowers(ctx context.Context) ([]models.Follower, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.listFailures > 0 {
+		f.listFailures--
+		return nil, errors.New("news service is unavailable")
+	}
+
+	followers := make([]models.Follower, 0, len(f.followers))
+	for _, follower := range f.followers {
+		followers = append(followers, follower)
+	}
+
+	return followers, nil
+}
+
+func (f *fakeFollowers) get(actorID string) (models.Follower, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	follower, ok := f.followers[actorID]
+	return follower, ok
+}
+
+// delivery is an activity posted to an inbox of the fake server.
+type delivery struct {
+	path     string
+	activity activity
+	err      error
+}
+
+// fakeServer is a remote fediverse server with users alice and dave. Key of dave is
+// a separate document with path-style id. Its inboxes check signatures of posted
+// activities with the key of the local actor.
+type fakeServer struct {
+	*httptest.Server
+
+	key        *rsa.PrivateKey
+	deliveries chan delivery
+}
+
+func newFakeServer(t *testing.T, actorKey *rsa.PublicKey) *fakeServer {
+	t.Helper()
+
+	s := &fakeServer{key: newKey(t), deliveries: make(chan delivery, 16)}
+
+	remotePEM, err := publicKeyPEM(&s.key.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/users/alice", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", ContentType)
+		_ = json.NewEncoder(w).Encode(remoteActor{
+			ID:        s.alice(),
+			Inbox:     s.alice() + "/inbox",
+			Endpoints: endpoints{SharedInbox: s.URL + "/inbox"},
+			PublicKey: publicKey{ID: s.aliceKeyID(), Owner: s.alice(), PublicKeyPem: remotePEM},
+		})
+	})
+
+	mux.HandleFunc("/users/dave", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", ContentType)
+		_ = json.NewEncoder(w).Encode(remoteActor{
+			ID:        s.dave(),
+			Inbox:     s.dave() + "/inbox",
+			PublicKey: publicKey{ID: s.daveKeyID(), Owner: s.dave(), PublicKeyPem: remotePEM},
+		})
+	})
+
+	mux.HandleFunc("/users/dave/main-key", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", ContentType)
+		_ = json.NewEncoder(w).Encode(remoteActor{ID: s.daveKeyID(), Owner: s.dave(), PublicKeyPem: remotePEM})
+	})
+
+	// Key document which claims alice as its owner, alice doesn't list it.
+	mux.HandleFunc("/keys/forged", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", ContentType)
+		_ = json.NewEncoder(w).Encode(remoteActor{ID: s.URL + "/keys/forged", Owner: s.alice(), PublicKeyPem: remotePEM})
+	})
+
+	inbox := func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		d := delivery{path: r.URL.Path}
+
+		sig, err := parseSignature(r)
+		if err == nil {
+			err = sig.verify(r, body, actorKey)
+		}
+		d.err = err
+
+		if err := json.Unmarshal(body, &d.activity); err != nil && d.err == nil {
+			d.err = err
+		}
+
+		s.deliveries <- d
+		w.WriteHeader(http.StatusAccepted)
+	}
+
+	mux.HandleFunc("/inbox", inbox)
+	mux.HandleFunc("/users/alice/inbox", inbox)
+	mux.HandleFunc("/users/carol/inbox", inbox)
+	mux.HandleFunc("/users/dave/inbox", inbox)
+
+	s.Server = httptest.NewServer(mux)
+	t.Cleanup(s.Close)
+
+	return s
+}
+
+func (s *fakeServer) alice() string {
+	return s.URL + "/users/alice"
+}
+
+func (s *fakeServer) aliceKeyID() string {
+	return s.alice() + "#main-key"
+}
+
+func (s *fakeServer) dave() string {
+	return s.URL + "/users/dave"
+}
+
+func (s *fakeServer) daveKeyID() string {
+	return s.dave() + "/main-key"
+}
+
+// next returns the next delivery or fails after a while.
+func (s *fakeServer) next(t *testing.T) delivery {
+	t.Helper()
+
+	select {
+	case d := <-s.deliveries:
+		if d.err != nil {
+			t.Errorf("delivery to %s: %v", d.path, d.err)
+		}
+		return d
+	case <-time.After(5 * time.Second):
+		t.Fatal("no delivery to the fake inbox")
+		return delivery{}
+	}
+}
+
+// none checks nothing more is delivered for a short while.
+func (s *fakeServer) none(t *testing.T) {
+	t.Helper()
+
+	select {
+	case d := <-s.deliveries:
+		t.Errorf("unexpected delivery to %s of %s", d.path, d.activity.Type)
+	case <-time.After(200 * time.Millisecond):
+	}
+}
+
+func newTestActor(t *testing.T, followers FollowerService) *Actor {
+	t.Helper()
+
+	a, err := New(testBaseURL, "news", newKey(t), followers, nil, 5*time.Second, true, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	t.Cleanup(startActor(a))
+
+	return a
+}
+
+// startActor runs delivery of the actor and returns func stopping it.
+func startActor(a *Actor) func() {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+		a.Start(ctx)
+	}()
+
+	return func() {
+		cancel()
+		<-done
+	}
+}
+
+// inboxRequest returns activity posted to the inbox of the actor signed with key.
+func inboxRequest(t *testing.T, keyID string, key *rsa.PrivateKey, body []byte) *http.Request {
+	t.Helper()
+
+	req, err := http.NewRequest(http.MethodPost, testBaseURL+"/ap/inbox", bytes.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req.Header.Set("Content-Type", ContentType)
+
+	if err := signRequest(req, body, keyID, key); err != nil {
+		t.Fatal(err)
+	}
+
+	return req
+}
+
+func marshal(t *testing.T, v any) []byte {
+	t.Helper()
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return data
+}
+
+func TestHandleInboxFollow(t *testing.T) {
+	followers := newFakeFollowers()
+	a := newTestActor(t, followers)
+	srv := newFakeServer(t, &a.key.PublicKey)
+
+	body := marshal(t, map[string]string{
+		"@context": activityContext[0],
+		"id":       srv.URL + "/follows/1",
+		"type":     "Follow",
+		"actor":    srv.alice(),
+		"object":   a.ID(),
+	})
+
+	if err := a.HandleInbox(context.Background(), inboxRequest(t, srv.aliceKeyID(), srv.key, body), body); err != nil {
+		t.Fatalf("HandleInbox() error = %v", err)
+	}
+
+	follower, ok := followers.get(srv.alice())
+	if !ok {
+		t.Fatal("follower isn't added")
+	}
+
+	if want := (models.Follower{ActorID: srv.alice(), Inbox: srv.alice() + "/inbox", SharedInbox: srv.URL + "/inbox"}); follower != want {
+		t.Errorf("follower = %+v, want %+v", follower, want)
+	}
+
+	accept := srv.next(t)
+
+	if accept.path != "/users/alice/inbox" {
+		t.Errorf("Accept is delivered to %s, want personal inbox", accept.path)
+	}
+
+	if accept.activity.Type != "Accept" || accept.activity.Actor != a.ID() {
+		t.Errorf("delivered %s by %s, want Accept by %s", accept.activity.Type, accept.activity.Actor, a.ID())
+	}
+
+	if got := objectID(accept.activity.Object); got != srv.URL+"/follows/1" {
+		t.Errorf("Accept object = %q, want the Follow", got)
+	}
+}
+
+func TestHandleInboxPathKeyID(t *testing.T) {
+	followers := newFakeFollowers()
+	a := newTestActor(t, followers)
+	srv := newFakeServer(t, &a.key.PublicKey)
+
+	body := marshal(t, map[string]string{
+		"id":     srv.URL + "/follows/2",
+		"type":   "Follow",
+		"actor":  srv.dave(),
+		"object": a.ID(),
+	})
+
+	if err := a.HandleInbox(context.Background(), inboxRequest(t, srv.daveKeyID(), srv.key, body), body); err != nil {
+		t.Fatalf("HandleInbox() error = %v", err)
+	}
+
+	if _, ok := followers.get(srv.dave()); !ok {
+		t.Fatal("follower isn't added")
+	}
+
+	if accept := srv.next(t); accept.path != "/users/dave/inbox" || accept.activity.Type != "Accept" {
+		t.Errorf("delivered %s to %s, want Accept to personal inbox", accept.activity.Type, accept.path)
+	}
+}
+
+func TestHandleInboxUndoFollow(t *testing.T) {
+	followers := newFakeFollowers()
+	a := newTestActor(t, followers)
+	srv := newFakeServer(t, &a.key.PublicKey)
+
+	followers.followers[srv.alice()] = models.Follower{ActorID: srv.alice(), Inbox: srv.alice() + "/inbox"}
+
+	body := marshal(t, map[string]any{
+		"@context": activityContext[0],
+		"id":       srv.URL + "/undo/1",
+		"type":     "Undo",
+		"actor":    srv.alice(),
+		"object": map[string]string{
+			"id":     srv.URL + "/follows/1",
+			"type":   "Follow",
+			"actor":  srv.alice(),
+			"object": a.ID(),
+		},
+	})
+
+	if err := a.HandleInbox(context.Background(), inboxRequest(t, srv.aliceKeyID(), srv.key, body), body); err != nil {
+		t.Fatalf("HandleInbox() error = %v", err)
+	}
+
+	if _, ok := followers.get(srv.alice()); ok {
+		t.Error("follower isn't removed")
+	}
+
+	// Undo of unknown follower is fine.
+	if err := a.HandleInbox(context.Background(), inboxRequest(t, srv.aliceKeyID(), srv.key, body), body); err != nil {
+		t.Errorf("second HandleInbox() error = %v", err)
+	}
+
+	srv.none(t)
+}
+
+func TestHandleInboxRejected(t *testing.T) {
+	followers := newFakeFollowers()
+	a := newTestActor(t, followers)
+	srv := newFakeServer(t, &a.key.PublicKey)
+	otherKey := newKey(t)
+
+	follow := func(actor string) []byte {
+		return marshal(t, map[string]string{
+			"id":     srv.URL + "/follows/1",
+			"type":   "Follow",
+			"actor":  actor,
+			"object": a.ID(),
+		})
+	}
+
+	tests := []struct {
+		name    string
+		request func() (*http.Request, []byte)
+		wantErr error
+	}{
+		{
+			name: "not signed",
+			request: func() (*http.Request, []byte) {
+				body := follow(srv.alice())
+				req := inboxRequest(t, srv.aliceKeyID(), srv.key, body)
+				req.Header.Del("Signature")
+				return req, body
+			},
+			wantErr: services.ErrInvalidSignature,
+		},
+		{
+			name: "other key",
+			request: func() (*http.Request, []byte) {
+				body := follow(srv.alice())
+				return inboxRequest(t, srv.aliceKeyID(), otherKey, body), body
+			},
+			wantErr: services.ErrInvalidSignature,
+		},
+		{
+			name: "body doesn't match digest",
+			request: func() (*http.Request, []byte) {
+				body := follow(srv.alice())
+				req := inboxRequest(t, srv.aliceKeyID(), srv.key, body)
+				return req, bytes.Replace(body, []byte("follows/1"), []byte("follows/2"), 1)
+			},
+			wantErr: services.ErrInvalidSignature,
+		},
+		{
+			name: "digest changed",
+			request: func() (*http.Request, []byte) {
+				body := follow(srv.alice())
+				req := inboxRequest(t, srv.aliceKeyID(), srv.key, body)
+				changed := bytes.Replace(body, []byte("follows/1"), []byte("follows/2"), 1)
+				req.Header.Set("Digest", digest(changed))
+				return req, changed
+			},
+			wantErr: services.ErrInvalidSignature,
+		},
+		{
+			name: "key of other actor",
+			request: func() (*http.Request, []byte) {
+				body := follow(srv.URL + "/users/bob")
+				return inboxRequest(t, srv.aliceKeyID(), srv.key, body), body
+			},
+			wantErr: services.ErrInvalidSignature,
+		},
+		{
+			name: "path-style key of other actor",
+			request: func() (*http.Request, []byte) {
+				body := follow(srv.alice())
+				return inboxRequest(t, srv.daveKeyID(), srv.key, body), body
+			},
+			wantErr: services.ErrInvalidSignature,
+		},
+		{
+			name: "key not listed by its owner",
+			request: func() (*http.Request, []byte) {
+				body := follow(srv.alice())
+				return inboxRequest(t, srv.URL+"/keys/forged", srv.key, body), body
+			},
+			wantErr: services.ErrInvalidSignature,
+		},
+		{
+			name: "not activity",
+			request: func() (*http.Request, []byte) {
+				body := []byte(`{"type":"Follow"}`)
+				return inboxRequest(t, srv.aliceKeyID(), srv.key, body), body
+			},
+			wantErr: services.ErrInvalidActivity,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, body := tt.request()
+
+			if err := a.HandleInbox(context.Background(), req, body); !errors.Is(err, tt.wantErr) {
+				t.Errorf("HandleInbox() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	if list, _ := followers.ListFollowers(context.Background()); len(list) != 0 {
+		t.Errorf("followers = %+v, want none", list)
+	}
+
+	srv.none(t)
+}
+
+func TestAnnounce(t *testing.T) {
+	followers := newFakeFollowers()
+	a := newTestActor(t, followers)
+	srv := newFakeServer(t, &a.key.PublicKey)
+
+	for _, f := range []models.Follower{
+		{ActorID: srv.alice(), Inbox: srv.alice() + "/inbox", SharedInbox: srv.URL + "/inbox"},
+		{ActorID: srv.URL + "/users/bob", Inbox: srv.URL + "/users/bob/inbox", SharedInbox: srv.URL + "/inbox"},
+		{ActorID: srv.URL + "/users/carol", Inbox: srv.URL + "/users/carol/inbox"},
+	} {
+		followers.followers[f.ActorID] = f
+	}
+
+	a.Announce(models.Article{
+		ArticleID:  7,
+		SourceName: "Example <News>",
+		Title:      "Title & more",
+		Link:       "https://example.com/a",
+		PostedAt:   time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC),
+	})
+
+	deliveries := []delivery{srv.next(t), srv.next(t)}
+	srv.none(t)
+
+	paths := []string{deliveries[0].path, deliveries[1].path}
+	sort.Strings(paths)
+
+	if paths[0] != "/inbox" || paths[1] != "/users/carol/inbox" {
+		t.Errorf("delivered to %q, want shared inbox and inbox of carol once", paths)
+	}
+
+	for _, d := range deliveries {
+		if d.activity.Type != "Create" || d.activity.Actor != a.ID() {
+			t.Errorf("delivered %s by %s, want Create by %s", d.activity.Type, d.activity.Actor, a.ID())
+		}
+
+		n := note{}
+		if err := json.Unmarshal(d.activity.Object, &n); err != nil {
+			t.Fatalf("can't decode note: %v", err)
+		}
+
+		if n.Type != "Note" || n.URL != "https://example.com/a" || n.ID != testBaseURL+"/ap/notes/7" {
+			t.Errorf("note = %+v", n)
+		}
+
+		if want := `<p><a href="https://example.com/a">Title &amp; more</a></p><p>Example &lt;News&gt;</p>`; n.Content != want {
+			t.Errorf("note content = %q, want %q", n.Content, want)
+		}
+	}
+}
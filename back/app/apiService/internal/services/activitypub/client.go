@@ -0,0 +1,33 @@
+package activitypub
+
+import (
+	"net"
+	"net/http"
+	"time"
+
+	"newsWebApp/pkg/netguard"
+)
+
+// newClient returns client for addresses taken from other servers' documents. Unless
+// insecure, it connects only to public addresses and doesn't follow redirects.
+func newClient(timeout time.Duration, insecure bool) *http.Client {
+	dialer := &net.Dialer{
+		Timeout:   timeout,
+		KeepAlive: 30 * time.Second,
+	}
+
+	if !insecure {
+		dialer.Control = netguard.Control
+	}
+
+	return &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			DialContext:         dialer.DialContext,
+			MaxIdleConnsPerHost: 4,
+			IdleConnTimeout:     60 * time.Second,
+			TLSHandshakeTimeout: 5 * time.Second,
+		},
+		CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse },
+	}
+}
@@ -0,0 +1,201 @@
+package activitypub
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// queued is an activity waiting for delivery. Activity without inbox goes to all
+// followers, it's split into deliveries to their inboxes when it's due.
+type queued struct {
+	inbox    string
+	payload  []byte
+	attempts int
+	due      time.Time
+}
+
+// queue keeps deliveries till their time comes. wake tells the worker about new ones.
+type queue struct {
+	mu      sync.Mutex
+	pending []queued
+	wake    chan struct{}
+}
+
+func newQueue() *queue {
+	return &queue{wake: make(chan struct{}, 1)}
+}
+
+func (q *queue) push(d queued) {
+	q.mu.Lock()
+	q.pending = append(q.pending, d)
+	q.mu.Unlock()
+
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+}
+
+// takeDue removes deliveries due by now and returns them with time of the next one.
+func (q *queue) takeDue(now time.Time) ([]queued, time.Time) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	due := []queued{}
+	var next time.Time
+	kept := q.pending[:0]
+
+	for _, d := range q.pending {
+		if !d.due.After(now) {
+			due = append(due, d)
+			continue
+		}
+
+		if next.IsZero() || d.due.Before(next) {
+			next = d.due
+		}
+		kept = append(kept, d)
+	}
+
+	q.pending = kept
+
+	return due, next
+}
+
+func (q *queue) len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	return len(q.pending)
+}
+
+// Start sends queued activities till ctx is done. Failed delivery is retried after
+// backoff doubled on every attempt, deliveryAttempts in total. On stop it waits for
+// deliveries in flight, the ones still waiting are reported and dropped.
+func (a *Actor) Start(ctx context.Context) error {
+	slots := make(chan struct{}, deliveryWorkers)
+	var wg sync.WaitGroup
+
+	defer func() {
+		wg.Wait()
+
+		if n := a.queue.len(); n > 0 {
+			a.log.Warn("Activities aren't delivered before stop", "pending", n)
+		}
+	}()
+
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-a.queue.wake:
+		case <-timer.C:
+		}
+
+		due, next := a.queue.takeDue(time.Now())
+
+		for i, d := range due {
+			select {
+			case slots <- struct{}{}:
+			case <-ctx.Done():
+				for _, d := range due[i:] {
+					a.queue.push(d)
+				}
+				return ctx.Err()
+			}
+
+			wg.Add(1)
+
+			go func(d queued) {
+				defer func() {
+					<-slots
+					wg.Done()
+				}()
+
+				a.deliver(ctx, d)
+			}(d)
+		}
+
+		if !timer.Stop() {
+			select {
+			case <-timer.C:
+			default:
+			}
+		}
+
+		if !next.IsZero() {
+			timer.Reset(time.Until(next))
+		}
+	}
+}
+
+// deliver sends activity to the inbox or splits it among followers and schedules
+// a retry when it fails.
+func (a *Actor) deliver(ctx context.Context, d queued) {
+	// Delivery in flight is finished on stop, it's limited by timeout.
+	ctx = context.WithoutCancel(ctx)
+
+	if d.inbox == "" {
+		a.fanOut(ctx, d)
+		return
+	}
+
+	retry, err := a.post(ctx, d.inbox, d.payload)
+	if err == nil {
+		return
+	}
+
+	d.attempts++
+
+	if !retry || d.attempts >= deliveryAttempts {
+		a.log.Warn("Can't deliver activity", "inbox", d.inbox, "attempts", d.attempts, "err", err.Error())
+		return
+	}
+
+	a.log.Debug("Activity delivery failed, retrying", "inbox", d.inbox, "attempts", d.attempts, "err", err.Error())
+	a.retry(d)
+}
+
+// fanOut queues activity for inboxes of all followers, shared inbox of a server gets
+// it once for all its users.
+func (a *Actor) fanOut(ctx context.Context, d queued) {
+	ctx, cancel := context.WithTimeout(ctx, a.timeout)
+	followers, err := a.followers.ListFollowers(ctx)
+	cancel()
+
+	if err != nil {
+		d.attempts++
+
+		if d.attempts >= deliveryAttempts {
+			a.log.Error("Can't get followers, activity isn't delivered", "attempts", d.attempts, "err", err.Error())
+			return
+		}
+
+		a.log.Warn("Can't get followers", "attempts", d.attempts, "err", err.Error())
+		a.retry(d)
+		return
+	}
+
+	seen := make(map[string]bool, len(followers))
+
+	for _, f := range followers {
+		inbox := f.SharedInbox
+		if inbox == "" {
+			inbox = f.Inbox
+		}
+
+		if !seen[inbox] {
+			seen[inbox] = true
+			a.queue.push(queued{inbox: inbox, payload: d.payload, due: time.Now()})
+		}
+	}
+}
+
+func (a *Actor) retry(d queued) {
+	d.due = time.Now().Add(a.backoff << (d.attempts - 1))
+	a.queue.push(d)
+}
@@ -0,0 +1,163 @@
+package activitypub
+
+import (
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"newsWebApp/app/apiService/internal/models"
+)
+
+// statusInbox answers with statuses in turn, the last one is repeated.
+func statusInbox(t *testing.T, statuses ...int) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+
+	var calls atomic.Int32
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := int(calls.Add(1))
+		w.WriteHeader(statuses[min(n, len(statuses))-1])
+	}))
+	t.Cleanup(srv.Close)
+
+	return srv, &calls
+}
+
+// waitCalls waits for want requests and checks no more come for a while.
+func waitCalls(t *testing.T, calls *atomic.Int32, want int) {
+	t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for int(calls.Load()) < want && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	time.Sleep(100 * time.Millisecond)
+
+	if got := int(calls.Load()); got != want {
+		t.Errorf("inbox got %d requests, want %d", got, want)
+	}
+}
+
+func TestDeliveryRetries(t *testing.T) {
+	tests := []struct {
+		name     string
+		statuses []int
+		want     int
+	}{
+		{name: "delivered", statuses: []int{http.StatusAccepted}, want: 1},
+		{name: "retried after server error", statuses: []int{http.StatusServiceUnavailable, http.StatusTooManyRequests, http.StatusAccepted}, want: 3},
+		{name: "client error isn't retried", statuses: []int{http.StatusBadRequest}, want: 1},
+		{name: "given up", statuses: []int{http.StatusBadGateway}, want: deliveryAttempts},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv, calls := statusInbox(t, tt.statuses...)
+
+			a := newTestActor(t, newFakeFollowers())
+			a.backoff = 10 * time.Millisecond
+
+			a.queue.push(queued{inbox: srv.URL + "/inbox", payload: []byte(`{}`), due: time.Now()})
+
+			waitCalls(t, calls, tt.want)
+
+			if n := a.queue.len(); n != 0 {
+				t.Errorf("queue has %d deliveries, want none", n)
+			}
+		})
+	}
+}
+
+func TestDeliveryRetrySchedule(t *testing.T) {
+	srv, calls := statusInbox(t, http.StatusServiceUnavailable, http.StatusAccepted)
+
+	a := newTestActor(t, newFakeFollowers())
+	a.backoff = time.Hour
+
+	a.queue.push(queued{inbox: srv.URL + "/inbox", payload: []byte(`{}`), due: time.Now()})
+
+	waitCalls(t, calls, 1)
+
+	// Failed delivery waits for its time instead of holding the worker.
+	a.queue.mu.Lock()
+	pending := append([]queued{}, a.queue.pending...)
+	a.queue.mu.Unlock()
+
+	if len(pending) != 1 || pending[0].attempts != 1 || time.Until(pending[0].due) < 59*time.Minute {
+		t.Fatalf("pending deliveries = %+v, want one retry in an hour", pending)
+	}
+
+	// Other deliveries aren't held up by the scheduled retry.
+	other, otherCalls := statusInbox(t, http.StatusAccepted)
+	a.queue.push(queued{inbox: other.URL + "/inbox", payload: []byte(`{}`), due: time.Now()})
+
+	waitCalls(t, otherCalls, 1)
+}
+
+func TestAnnounceRetriesFollowers(t *testing.T) {
+	srv, calls := statusInbox(t, http.StatusAccepted)
+
+	followers := newFakeFollowers(models.Follower{ActorID: srv.URL + "/users/alice", Inbox: srv.URL + "/inbox"})
+	followers.listFailures = 2
+
+	a := newTestActor(t, followers)
+	a.backoff = 10 * time.Millisecond
+
+	a.Announce(models.Article{ArticleID: 7, Title: "Title", Link: "https://example.com/a"})
+
+	waitCalls(t, calls, 1)
+}
+
+func TestStartFinishesDeliveriesInFlight(t *testing.T) {
+	received := make(chan struct{})
+	release := make(chan struct{})
+	var delivered atomic.Bool
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(received)
+		<-release
+
+		if r.Context().Err() == nil {
+			delivered.Store(true)
+		}
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer srv.Close()
+
+	a, err := New(testBaseURL, "news", newKey(t), newFakeFollowers(), nil, 5*time.Second, true, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	stop := startActor(a)
+
+	a.queue.push(queued{inbox: srv.URL + "/inbox", payload: []byte(`{}`), due: time.Now()})
+	// Retry isn't due before stop, it's dropped.
+	a.queue.push(queued{inbox: srv.URL + "/other", payload: []byte(`{}`), attempts: 1, due: time.Now().Add(time.Hour)})
+
+	<-received
+
+	stopped := make(chan struct{})
+	go func() {
+		stop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+		t.Fatal("Start() returned before delivery in flight is finished")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	close(release)
+	<-stopped
+
+	if !delivered.Load() {
+		t.Error("delivery in flight is canceled on stop")
+	}
+}
@@ -0,0 +1,158 @@
+package activitypub
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+)
+
+// maxClockSkew is how far Date of a signed request may be from now.
+const maxClockSkew = 12 * time.Hour
+
+var (
+	errNoSignature     = errors.New("request isn't signed")
+	errMissingHeader   = errors.New("required header isn't signed")
+	errStaleSignature  = errors.New("signed date is too far from now")
+	errDigestMismatch  = errors.New("digest doesn't match body")
+	errWrongSignature  = errors.New("signature doesn't match")
+	errUnsupportedAlgo = errors.New("unsupported signature algorithm")
+)
+
+// signRequest adds Date, Digest for bodies and Signature headers in format of
+// draft-cavage-http-signatures used by Mastodon and other servers.
+func signRequest(req *http.Request, body []byte, keyID string, key *rsa.PrivateKey) error {
+	req.Header.Set("Date", time.Now().UTC().Format(http.TimeFormat))
+
+	headers := []string{"(request-target)", "host", "date"}
+
+	if body != nil {
+		req.Header.Set("Digest", digest(body))
+		headers = append(headers, "digest")
+	}
+
+	hashed := sha256.Sum256([]byte(signingString(req, req.URL.Host, headers)))
+
+	signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, hashed[:])
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Signature", fmt.Sprintf(`keyId="%s",algorithm="rsa-sha256",headers="%s",signature="%s"`,
+		keyID,
+		strings.Join(headers, " "),
+		base64.StdEncoding.EncodeToString(signature),
+	))
+
+	return nil
+}
+
+type signature struct {
+	keyID     string
+	algorithm string
+	headers   []string
+	value     []byte
+}
+
+// parseSignature returns parameters of Signature header of the request.
+func parseSignature(req *http.Request) (*signature, error) {
+	header := req.Header.Get("Signature")
+	if header == "" {
+		return nil, errNoSignature
+	}
+
+	sig := &signature{headers: []string{"date"}}
+
+	for _, param := range strings.Split(header, ",") {
+		name, value, ok := strings.Cut(strings.TrimSpace(param), "=")
+		if !ok {
+			continue
+		}
+
+		value = strings.Trim(value, `"`)
+
+		switch name {
+		case "keyId":
+			sig.keyID = value
+		case "algorithm":
+			sig.algorithm = value
+		case "headers":
+			sig.headers = strings.Fields(strings.ToLower(value))
+		case "signature":
+			decoded, err := base64.StdEncoding.DecodeString(value)
+			if err != nil {
+				return nil, fmt.Errorf("can't decode signature: %w", err)
+			}
+			sig.value = decoded
+		}
+	}
+
+	if sig.keyID == "" || len(sig.value) == 0 {
+		return nil, errNoSignature
+	}
+
+	return sig, nil
+}
+
+// verify checks the signature of the request with body by the key. Request target,
+// host, date and digest of the body must be signed.
+func (sig *signature) verify(req *http.Request, body []byte, key *rsa.PublicKey) error {
+	switch sig.algorithm {
+	case "", "rsa-sha256", "hs2019":
+	default:
+		return fmt.Errorf("%w: %s", errUnsupportedAlgo, sig.algorithm)
+	}
+
+	for _, required := range []string{"(request-target)", "host", "date", "digest"} {
+		if !slices.Contains(sig.headers, required) {
+			return fmt.Errorf("%w: %s", errMissingHeader, required)
+		}
+	}
+
+	date, err := http.ParseTime(req.Header.Get("Date"))
+	if err != nil || time.Since(date).Abs() > maxClockSkew {
+		return errStaleSignature
+	}
+
+	if req.Header.Get("Digest") != digest(body) {
+		return errDigestMismatch
+	}
+
+	hashed := sha256.Sum256([]byte(signingString(req, req.Host, sig.headers)))
+
+	if err := rsa.VerifyPKCS1v15(key, crypto.SHA256, hashed[:], sig.value); err != nil {
+		return errWrongSignature
+	}
+
+	return nil
+}
+
+func signingString(req *http.Request, host string, headers []string) string {
+	lines := make([]string, len(headers))
+
+	for i, name := range headers {
+		switch name {
+		case "(request-target)":
+			lines[i] = name + ": " + strings.ToLower(req.Method) + " " + req.URL.RequestURI()
+		case "host":
+			lines[i] = name + ": " + host
+		default:
+			lines[i] = name + ": " + strings.Join(req.Header.Values(name), ", ")
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func digest(body []byte) string {
+	sum := sha256.Sum256(body)
+
+	return "SHA-256=" + base64.StdEncoding.EncodeToString(sum[:])
+}
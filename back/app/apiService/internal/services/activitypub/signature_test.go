@@ -0,0 +1,252 @@
+package activitypub
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/rsa"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+const (
+	testKeyID = "https://news.example/actor#main-key"
+	testInbox = "https://remote.example/users/alice/inbox"
+)
+
+func newKey(t *testing.T) *rsa.PrivateKey {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return key
+}
+
+// signedInbound signs outgoing request to the inbox and returns it as the remote
+// server receives it.
+func signedInbound(t *testing.T, key *rsa.PrivateKey, body []byte) *http.Request {
+	t.Helper()
+
+	out, err := http.NewRequest(http.MethodPost, testInbox, bytes.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := signRequest(out, body, testKeyID, key); err != nil {
+		t.Fatalf("signRequest() error = %v", err)
+	}
+
+	in := httptest.NewRequest(http.MethodPost, "/users/alice/inbox", bytes.NewReader(body))
+	in.Host = "remote.example"
+	in.Header = out.Header.Clone()
+
+	return in
+}
+
+func TestSignRequest(t *testing.T) {
+	key := newKey(t)
+	body := []byte(`{"type":"Create"}`)
+
+	req := signedInbound(t, key, body)
+
+	sig, err := parseSignature(req)
+	if err != nil {
+		t.Fatalf("parseSignature() error = %v", err)
+	}
+
+	if sig.keyID != testKeyID || sig.algorithm != "rsa-sha256" {
+		t.Errorf("parseSignature() = keyId %q, algorithm %q", sig.keyID, sig.algorithm)
+	}
+
+	if got := strings.Join(sig.headers, " "); got != "(request-target) host date digest" {
+		t.Errorf("signed headers = %q", got)
+	}
+
+	if err := sig.verify(req, body, &key.PublicKey); err != nil {
+		t.Errorf("verify() error = %v", err)
+	}
+}
+
+func TestVerify(t *testing.T) {
+	key := newKey(t)
+	other := newKey(t)
+	body := []byte(`{"type":"Follow"}`)
+
+	tests := []struct {
+		name    string
+		modify  func(req *http.Request) (*http.Request, []byte, *rsa.PublicKey)
+		wantErr error
+	}{
+		{
+			name: "other key",
+			modify: func(req *http.Request) (*http.Request, []byte, *rsa.PublicKey) {
+				return req, body, &other.PublicKey
+			},
+			wantErr: errWrongSignature,
+		},
+		{
+			name: "changed body",
+			modify: func(req *http.Request) (*http.Request, []byte, *rsa.PublicKey) {
+				return req, []byte(`{"type":"Undo"}`), &key.PublicKey
+			},
+			wantErr: errDigestMismatch,
+		},
+		{
+			name: "changed digest",
+			modify: func(req *http.Request) (*http.Request, []byte, *rsa.PublicKey) {
+				changed := []byte(`{"type":"Undo"}`)
+				req.Header.Set("Digest", digest(changed))
+				return req, changed, &key.PublicKey
+			},
+			wantErr: errWrongSignature,
+		},
+		{
+			name: "other path",
+			modify: func(req *http.Request) (*http.Request, []byte, *rsa.PublicKey) {
+				req.URL.Path = "/users/bob/inbox"
+				return req, body, &key.PublicKey
+			},
+			wantErr: errWrongSignature,
+		},
+		{
+			name: "other host",
+			modify: func(req *http.Request) (*http.Request, []byte, *rsa.PublicKey) {
+				req.Host = "evil.example"
+				return req, body, &key.PublicKey
+			},
+			wantErr: errWrongSignature,
+		},
+		{
+			name: "stale date",
+			modify: func(req *http.Request) (*http.Request, []byte, *rsa.PublicKey) {
+				req.Header.Set("Date", time.Now().Add(-maxClockSkew-time.Hour).UTC().Format(http.TimeFormat))
+				return req, body, &key.PublicKey
+			},
+			wantErr: errStaleSignature,
+		},
+		{
+			name: "bad date",
+			modify: func(req *http.Request) (*http.Request, []byte, *rsa.PublicKey) {
+				req.Header.Set("Date", "yesterday")
+				return req, body, &key.PublicKey
+			},
+			wantErr: errStaleSignature,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, reqBody, pub := tt.modify(signedInbound(t, key, body))
+
+			sig, err := parseSignature(req)
+			if err != nil {
+				t.Fatalf("parseSignature() error = %v", err)
+			}
+
+			if err := sig.verify(req, reqBody, pub); !errors.Is(err, tt.wantErr) {
+				t.Errorf("verify() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestVerifyRequiresDigest(t *testing.T) {
+	key := newKey(t)
+
+	req := signedInbound(t, key, nil)
+
+	sig, err := parseSignature(req)
+	if err != nil {
+		t.Fatalf("parseSignature() error = %v", err)
+	}
+
+	if err := sig.verify(req, nil, &key.PublicKey); !errors.Is(err, errMissingHeader) {
+		t.Errorf("verify() error = %v, want %v", err, errMissingHeader)
+	}
+}
+
+func TestParseSignature(t *testing.T) {
+	tests := []struct {
+		name        string
+		header      string
+		wantErr     error
+		wantAlgo    string
+		wantHeaders string
+	}{
+		{
+			name:    "no header",
+			wantErr: errNoSignature,
+		},
+		{
+			name:    "no key id",
+			header:  `algorithm="rsa-sha256",signature="c2ln"`,
+			wantErr: errNoSignature,
+		},
+		{
+			name:    "no signature",
+			header:  `keyId="k",algorithm="rsa-sha256"`,
+			wantErr: errNoSignature,
+		},
+		{
+			name:        "default headers",
+			header:      `keyId="k", signature="c2ln", junk`,
+			wantHeaders: "date",
+		},
+		{
+			name:        "hs2019",
+			header:      `keyId="k",algorithm="hs2019",headers="(request-target) Host Date Digest",signature="c2ln"`,
+			wantAlgo:    "hs2019",
+			wantHeaders: "(request-target) host date digest",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/inbox", nil)
+			if tt.header != "" {
+				req.Header.Set("Signature", tt.header)
+			}
+
+			sig, err := parseSignature(req)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("parseSignature() error = %v, want %v", err, tt.wantErr)
+			}
+
+			if err != nil {
+				return
+			}
+
+			if sig.algorithm != tt.wantAlgo || strings.Join(sig.headers, " ") != tt.wantHeaders || string(sig.value) != "sig" {
+				t.Errorf("parseSignature() = %+v", sig)
+			}
+		})
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/inbox", nil)
+	req.Header.Set("Signature", `keyId="k",signature="not base64!"`)
+
+	if _, err := parseSignature(req); err == nil {
+		t.Error("parseSignature() accepts signature which isn't base64")
+	}
+}
+
+func TestVerifyUnsupportedAlgorithm(t *testing.T) {
+	key := newKey(t)
+	req := signedInbound(t, key, []byte("{}"))
+
+	sig, err := parseSignature(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig.algorithm = "hmac-sha256"
+
+	if err := sig.verify(req, []byte("{}"), &key.PublicKey); !errors.Is(err, errUnsupportedAlgo) {
+		t.Errorf("verify() error = %v, want %v", err, errUnsupportedAlgo)
+	}
+}
@@ -3,17 +3,43 @@ package services
 import "errors"
 
 var (
-	ErrNoPublishedArticles = errors.New("there are no published articles")
-	ErrInvalidValue        = errors.New("invalid credentials")
-	ErrInvalidToken        = errors.New("invalid token")
-	ErrUserDoesntExists    = errors.New("user doesn't exist")
-	ErrUserExists          = errors.New("user already exists")
-	ErrTokenExpired        = errors.New("token expired")
-	ErrSessionNotFound     = errors.New("session not found")
-	ErrNoNewArticle        = errors.New("there is no new article")
-	ErrArticleExists       = errors.New("article already exists")
-	ErrArticleSkipped      = errors.New("invalid article")
-	ErrNoOfferedArticles   = errors.New("there are no offered articles")
-	ErrArticleNotAvailable = errors.New("article not available")
-	ErrInvalidUrl          = errors.New("url is invalid")
+	ErrNoPublishedArticles  = errors.New("there are no published articles")
+	ErrInvalidValue         = errors.New("invalid credentials")
+	ErrInvalidToken         = errors.New("invalid token")
+	ErrUserDoesntExists     = errors.New("user doesn't exist")
+	ErrUserExists           = errors.New("user already exists")
+	ErrTokenExpired         = errors.New("token expired")
+	ErrSessionNotFound      = errors.New("session not found")
+	ErrNoNewArticle         = errors.New("there is no new article")
+	ErrArticleExists        = errors.New("article already exists")
+	ErrArticleSkipped       = errors.New("invalid article")
+	ErrNoOfferedArticles    = errors.New("there are no offered articles")
+	ErrArticleNotAvailable  = errors.New("article not available")
+	ErrArticleNotFound      = errors.New("article not found")
+	ErrInvalidUrl           = errors.New("url is invalid")
+	ErrForbiddenUrl         = errors.New("url is forbidden")
+	ErrNoSources            = errors.New("there are no sources")
+	ErrSourceNotFound       = errors.New("source not found")
+	ErrSourceExists         = errors.New("source already exists")
+	ErrInvalidSource        = errors.New("invalid source")
+	ErrInvalidOPML          = errors.New("invalid opml document")
+	ErrInvalidConfig        = errors.New("invalid config")
+	ErrNothingToExplain     = errors.New("link or title is required")
+	ErrEmptyQuery           = errors.New("query is required")
+	ErrInvalidCursor        = errors.New("invalid cursor")
+	ErrNothingFound         = errors.New("nothing found")
+	ErrNoWebhooks           = errors.New("there are no webhooks")
+	ErrInvalidWebhook       = errors.New("invalid webhook")
+	ErrWebhookNotFound      = errors.New("webhook not found")
+	ErrInvalidStatus        = errors.New("invalid status")
+	ErrUnknownResource      = errors.New("unknown resource")
+	ErrInvalidSignature     = errors.New("invalid signature")
+	ErrInvalidActivity      = errors.New("invalid activity")
+	ErrInvalidFollower      = errors.New("invalid follower")
+	ErrFollowerNotFound     = errors.New("follower not found")
+	ErrInvalidFrequency     = errors.New("invalid frequency")
+	ErrSubscriptionNotFound = errors.New("subscription not found")
+	ErrSubmissionNotFound   = errors.New("submission not found")
+	ErrNotPending           = errors.New("submission isn't pending")
+	ErrReasonRequired       = errors.New("reason is required")
 )
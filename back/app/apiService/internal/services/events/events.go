@@ -0,0 +1,142 @@
+package events
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"newsWebApp/app/apiService/internal/models"
+)
+
+// clientBuffer is how many events a browser may lag behind before it's dropped
+// and has to reconnect.
+const clientBuffer = 32
+
+// Delays between attempts to resubscribe to events of other replicas.
+const (
+	subscribeRetry    = time.Second
+	subscribeRetryMax = 30 * time.Second
+)
+
+type EventSource interface {
+	SubscribeEvents(ctx context.Context, handle func(models.Event)) error
+	PublishEvent(ctx context.Context, event models.Event) error
+}
+
+type client struct {
+	user   string
+	events chan models.Event
+}
+
+// Hub receives events published by any replica and fans them out to browsers
+// connected to this one.
+type Hub struct {
+	source EventSource
+
+	mu      sync.Mutex
+	clients map[*client]struct{}
+	closed  bool
+
+	log *slog.Logger
+}
+
+func New(source EventSource, log *slog.Logger) *Hub {
+	return &Hub{
+		source:  source,
+		clients: make(map[*client]struct{}),
+		log:     log,
+	}
+}
+
+// Start receives events until ctx is done, resubscribing when subscription breaks.
+// Streams of all clients end with it, so they don't hold server shutdown.
+func (h *Hub) Start(ctx context.Context) error {
+	defer h.closeAll()
+
+	delay := subscribeRetry
+
+	for {
+		err := h.source.SubscribeEvents(ctx, func(event models.Event) {
+			delay = subscribeRetry
+			h.broadcast(event)
+		})
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		h.log.Warn("Events subscription stopped", "err", err)
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay):
+		}
+
+		delay = min(2*delay, subscribeRetryMax)
+	}
+}
+
+// Subscribe returns events for everyone and for the user, empty user gets only
+// public ones. Channel is closed when the client is too slow.
+func (h *Hub) Subscribe(user string) (<-chan models.Event, func()) {
+	c := &client{
+		user:   user,
+		events: make(chan models.Event, clientBuffer),
+	}
+
+	h.mu.Lock()
+	if h.closed {
+		close(c.events)
+	} else {
+		h.clients[c] = struct{}{}
+	}
+	h.mu.Unlock()
+
+	unsubscribe := func() {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+
+		if _, ok := h.clients[c]; ok {
+			delete(h.clients, c)
+			close(c.events)
+		}
+	}
+
+	return c.events, unsubscribe
+}
+
+// Publish sends the event to browsers connected to any replica.
+func (h *Hub) Publish(ctx context.Context, event models.Event) error {
+	return h.source.PublishEvent(ctx, event)
+}
+
+func (h *Hub) broadcast(event models.Event) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for c := range h.clients {
+		if event.User != "" && event.User != c.user {
+			continue
+		}
+
+		select {
+		case c.events <- event:
+		default:
+			delete(h.clients, c)
+			close(c.events)
+		}
+	}
+}
+
+func (h *Hub) closeAll() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.closed = true
+
+	for c := range h.clients {
+		delete(h.clients, c)
+		close(c.events)
+	}
+}
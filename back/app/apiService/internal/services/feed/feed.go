@@ -0,0 +1,273 @@
+// Package feed renders published articles as RSS 2.0, Atom and JSON Feed 1.1.
+package feed
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"strconv"
+	"time"
+
+	"newsWebApp/app/apiService/internal/models"
+)
+
+// Formats of feeds by extension of their path.
+const (
+	FormatRSS  = "rss"
+	FormatAtom = "atom"
+	FormatJSON = "json"
+)
+
+var ErrUnknownFormat = errors.New("unknown feed format")
+
+// Meta describes the feed itself. SelfURL is the address the feed is served from.
+type Meta struct {
+	Title       string
+	Description string
+	HomeURL     string
+	SelfURL     string
+}
+
+// ContentType returns media type of the format.
+func ContentType(format string) string {
+	switch format {
+	case FormatRSS:
+		return "application/rss+xml; charset=utf-8"
+	case FormatAtom:
+		return "application/atom+xml; charset=utf-8"
+	case FormatJSON:
+		return "application/feed+json; charset=utf-8"
+	default:
+		return ""
+	}
+}
+
+// Render returns the feed of articles, newest first, in the format.
+func Render(format string, meta Meta, articles []models.Article) ([]byte, error) {
+	switch format {
+	case FormatRSS:
+		return renderRSS(meta, articles)
+	case FormatAtom:
+		return renderAtom(meta, articles)
+	case FormatJSON:
+		return renderJSON(meta, articles)
+	default:
+		return nil, ErrUnknownFormat
+	}
+}
+
+// Updated returns time of the newest article, zero for empty feed.
+func Updated(articles []models.Article) time.Time {
+	var updated time.Time
+
+	for _, art := range articles {
+		if art.PostedAt.After(updated) {
+			updated = art.PostedAt
+		}
+	}
+
+	return updated
+}
+
+type rss struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	Atom    string     `xml:"xmlns:atom,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	Self          atomLink  `xml:"atom:link"`
+	LastBuildDate string    `xml:"lastBuildDate,omitempty"`
+	Items         []rssItem `xml:"item"`
+}
+
+type rssItem struct {
+	Title       string  `xml:"title"`
+	Link        string  `xml:"link"`
+	GUID        rssGUID `xml:"guid"`
+	Description string  `xml:"description,omitempty"`
+	Category    string  `xml:"category,omitempty"`
+	PubDate     string  `xml:"pubDate"`
+}
+
+type rssGUID struct {
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+}
+
+func renderRSS(meta Meta, articles []models.Article) ([]byte, error) {
+	doc := rss{
+		Version: "2.0",
+		Atom:    "http://www.w3.org/2005/Atom",
+		Channel: rssChannel{
+			Title:       meta.Title,
+			Link:        meta.HomeURL,
+			Description: meta.Description,
+			Self:        atomLink{Href: meta.SelfURL, Rel: "self", Type: "application/rss+xml"},
+		},
+	}
+
+	if updated := Updated(articles); !updated.IsZero() {
+		doc.Channel.LastBuildDate = updated.UTC().Format(time.RFC1123Z)
+	}
+
+	for _, art := range articles {
+		doc.Channel.Items = append(doc.Channel.Items, rssItem{
+			Title:       art.Title,
+			Link:        art.Link,
+			GUID:        rssGUID{IsPermaLink: true, Value: art.Link},
+			Description: art.Excerpt,
+			Category:    art.SourceName,
+			PubDate:     art.PostedAt.UTC().Format(time.RFC1123Z),
+		})
+	}
+
+	return marshalXML(doc)
+}
+
+type atom struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	Title   string      `xml:"title"`
+	ID      string      `xml:"id"`
+	Updated string      `xml:"updated"`
+	Links   []atomLink  `xml:"link"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomEntry struct {
+	Title     string        `xml:"title"`
+	ID        string        `xml:"id"`
+	Link      atomLink      `xml:"link"`
+	Published string        `xml:"published"`
+	Updated   string        `xml:"updated"`
+	Summary   string        `xml:"summary,omitempty"`
+	Author    *atomAuthor   `xml:"author,omitempty"`
+	Category  *atomCategory `xml:"category,omitempty"`
+}
+
+type atomAuthor struct {
+	Name string `xml:"name"`
+}
+
+type atomCategory struct {
+	Term string `xml:"term,attr"`
+}
+
+func renderAtom(meta Meta, articles []models.Article) ([]byte, error) {
+	updated := Updated(articles)
+	if updated.IsZero() {
+		updated = time.Unix(0, 0)
+	}
+
+	doc := atom{
+		Title:   meta.Title,
+		ID:      meta.SelfURL,
+		Updated: updated.UTC().Format(time.RFC3339),
+		Links: []atomLink{
+			{Href: meta.SelfURL, Rel: "self"},
+			{Href: meta.HomeURL, Rel: "alternate"},
+		},
+	}
+
+	for _, art := range articles {
+		entry := atomEntry{
+			Title:     art.Title,
+			ID:        art.Link,
+			Link:      atomLink{Href: art.Link, Rel: "alternate"},
+			Published: art.PostedAt.UTC().Format(time.RFC3339),
+			Updated:   art.PostedAt.UTC().Format(time.RFC3339),
+			Summary:   art.Excerpt,
+		}
+
+		if art.UserName != "" {
+			entry.Author = &atomAuthor{Name: art.UserName}
+		}
+
+		if art.SourceName != "" {
+			entry.Category = &atomCategory{Term: art.SourceName}
+		}
+
+		doc.Entries = append(doc.Entries, entry)
+	}
+
+	return marshalXML(doc)
+}
+
+func marshalXML(doc any) ([]byte, error) {
+	body, err := xml.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]byte(xml.Header), body...), nil
+}
+
+type jsonFeed struct {
+	Version     string     `json:"version"`
+	Title       string     `json:"title"`
+	HomePageURL string     `json:"home_page_url"`
+	FeedURL     string     `json:"feed_url"`
+	Description string     `json:"description,omitempty"`
+	Items       []jsonItem `json:"items"`
+}
+
+type jsonItem struct {
+	ID            string       `json:"id"`
+	URL           string       `json:"url"`
+	Title         string       `json:"title"`
+	Summary       string       `json:"summary,omitempty"`
+	ContentText   string       `json:"content_text"`
+	Image         string       `json:"image,omitempty"`
+	DatePublished string       `json:"date_published"`
+	Authors       []jsonAuthor `json:"authors,omitempty"`
+	Tags          []string     `json:"tags,omitempty"`
+}
+
+type jsonAuthor struct {
+	Name string `json:"name"`
+}
+
+func renderJSON(meta Meta, articles []models.Article) ([]byte, error) {
+	doc := jsonFeed{
+		Version:     "https://jsonfeed.org/version/1.1",
+		Title:       meta.Title,
+		HomePageURL: meta.HomeURL,
+		FeedURL:     meta.SelfURL,
+		Description: meta.Description,
+		Items:       make([]jsonItem, 0, len(articles)),
+	}
+
+	for _, art := range articles {
+		item := jsonItem{
+			ID:            strconv.FormatInt(art.ArticleID, 10),
+			URL:           art.Link,
+			Title:         art.Title,
+			Summary:       art.Excerpt,
+			ContentText:   art.Excerpt,
+			Image:         art.ImageURL,
+			DatePublished: art.PostedAt.UTC().Format(time.RFC3339),
+		}
+
+		if art.UserName != "" {
+			item.Authors = []jsonAuthor{{Name: art.UserName}}
+		}
+
+		if art.SourceName != "" {
+			item.Tags = []string{art.SourceName}
+		}
+
+		doc.Items = append(doc.Items, item)
+	}
+
+	return json.MarshalIndent(doc, "", "  ")
+}
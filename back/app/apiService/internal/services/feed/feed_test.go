@@ -0,0 +1,125 @@
+package feed
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"strings"
+	"testing"
+	"time"
+
+	"newsWebApp/app/apiService/internal/models"
+)
+
+var testMeta = Meta{
+	Title:       "News",
+	Description: "Latest published articles",
+	HomeURL:     "https://news.example.com",
+	SelfURL:     "https://news.example.com/feed.rss",
+}
+
+var testArticles = []models.Article{
+	{
+		ArticleID:  2,
+		UserName:   "alice",
+		SourceName: "go.dev",
+		Title:      "Go 1.22 <released>",
+		Link:       "https://go.dev/blog/go1.22",
+		Excerpt:    "Loop variables & more",
+		PostedAt:   time.Date(2024, 2, 7, 10, 0, 0, 0, time.UTC),
+	},
+	{
+		ArticleID: 1,
+		Title:     "Older",
+		Link:      "https://example.com/older",
+		PostedAt:  time.Date(2024, 2, 6, 10, 0, 0, 0, time.UTC),
+	},
+}
+
+func TestRenderRSS(t *testing.T) {
+	body, err := Render(FormatRSS, testMeta, testArticles)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	var doc rss
+	if err := xml.Unmarshal(body, &doc); err != nil {
+		t.Fatalf("rss isn't valid xml: %v", err)
+	}
+
+	if got := doc.Channel.LastBuildDate; got != "Wed, 07 Feb 2024 10:00:00 +0000" {
+		t.Errorf("lastBuildDate = %q", got)
+	}
+
+	if len(doc.Channel.Items) != 2 {
+		t.Fatalf("items = %d, want 2", len(doc.Channel.Items))
+	}
+
+	item := doc.Channel.Items[0]
+	if item.Title != "Go 1.22 <released>" || item.GUID.Value != "https://go.dev/blog/go1.22" || item.Category != "go.dev" {
+		t.Errorf("first item = %+v", item)
+	}
+
+	if !strings.Contains(string(body), "Go 1.22 &lt;released&gt;") {
+		t.Errorf("title isn't escaped: %s", body)
+	}
+}
+
+func TestRenderAtom(t *testing.T) {
+	body, err := Render(FormatAtom, testMeta, testArticles)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	var doc atom
+	if err := xml.Unmarshal(body, &doc); err != nil {
+		t.Fatalf("atom isn't valid xml: %v", err)
+	}
+
+	if doc.Updated != "2024-02-07T10:00:00Z" || doc.ID != testMeta.SelfURL {
+		t.Errorf("feed updated = %q, id = %q", doc.Updated, doc.ID)
+	}
+
+	if len(doc.Entries) != 2 {
+		t.Fatalf("entries = %d, want 2", len(doc.Entries))
+	}
+
+	if e := doc.Entries[0]; e.Author == nil || e.Author.Name != "alice" || e.Category == nil || e.Category.Term != "go.dev" {
+		t.Errorf("first entry = %+v", e)
+	}
+
+	if e := doc.Entries[1]; e.Author != nil || e.Category != nil {
+		t.Errorf("entry of bot has author or category: %+v", e)
+	}
+}
+
+func TestRenderJSON(t *testing.T) {
+	body, err := Render(FormatJSON, testMeta, testArticles)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	var doc jsonFeed
+	if err := json.Unmarshal(body, &doc); err != nil {
+		t.Fatalf("json feed isn't valid: %v", err)
+	}
+
+	if doc.Version != "https://jsonfeed.org/version/1.1" || doc.FeedURL != testMeta.SelfURL {
+		t.Errorf("feed = %+v", doc)
+	}
+
+	if len(doc.Items) != 2 || doc.Items[0].ID != "2" || doc.Items[0].DatePublished != "2024-02-07T10:00:00Z" {
+		t.Errorf("items = %+v", doc.Items)
+	}
+}
+
+func TestRenderEmpty(t *testing.T) {
+	for _, format := range []string{FormatRSS, FormatAtom, FormatJSON} {
+		if _, err := Render(format, testMeta, nil); err != nil {
+			t.Errorf("Render(%q) of empty feed error = %v", format, err)
+		}
+	}
+
+	if _, err := Render("xml", testMeta, nil); err != ErrUnknownFormat {
+		t.Errorf("Render() of unknown format error = %v, want ErrUnknownFormat", err)
+	}
+}
@@ -10,44 +10,58 @@ import (
 	"newsWebApp/app/apiService/internal/models"
 	"newsWebApp/app/apiService/internal/services"
 	"newsWebApp/app/apiService/internal/storage"
+	"newsWebApp/pkg/cursor"
 )
 
 type NewsService interface {
-	GetNewestArticle(ctx context.Context) (*models.Article, error)
+	WatchPublished(ctx context.Context, lastID int64, handle func(models.Article) error) error
 	GetArticles(ctx context.Context) ([]models.Article, error)
-	GetArticlesByPage(ctx context.Context, page int64) ([]models.Article, error)
+	GetArticlesByPage(ctx context.Context, cursor string) ([]models.Article, string, error)
 }
 
 type ArticlesCache interface {
 	AddArticles(ctx context.Context, articles []models.Article) error
-	AddArticle(ctx context.Context, article *models.Article) error
-	GetArticlesOnPage(ctx context.Context, page int64) ([]models.Article, error)
+	AddArticle(ctx context.Context, article *models.Article) (bool, error)
+	GetLatestArticles(ctx context.Context, n int) ([]models.Article, error)
 }
 
+// Announcer is told about every newly published article once across all replicas.
+type Announcer interface {
+	Announce(article models.Article)
+}
+
+// Delays between attempts to resume watching published articles.
+const (
+	watchRetry    = time.Second
+	watchRetryMax = 30 * time.Second
+)
+
 type NewsFetcher struct {
 	newsService NewsService
 	newsCache   ArticlesCache
+	announcers  []Announcer
 
-	refreshInterval time.Duration
-	log             *slog.Logger
+	pageLimit int
+	log       *slog.Logger
 }
 
-func New(newsService NewsService, newsCache ArticlesCache, refreshInterval time.Duration, log *slog.Logger) *NewsFetcher {
+func New(newsService NewsService, newsCache ArticlesCache, announcers []Announcer, pageLimit int, log *slog.Logger) *NewsFetcher {
 	return &NewsFetcher{
-		newsService:     newsService,
-		newsCache:       newsCache,
-		refreshInterval: refreshInterval,
-		log:             log,
+		newsService: newsService,
+		newsCache:   newsCache,
+		announcers:  announcers,
+		pageLimit:   pageLimit,
+		log:         log,
 	}
 }
 
+// Start warms up cache and keeps it updated with articles published by news service,
+// resuming after the last received one when the stream breaks.
 func (f *NewsFetcher) Start(ctx context.Context) error {
 	const op = "services.fetcher.start"
 
-	ticker := time.NewTicker(f.refreshInterval)
-	defer ticker.Stop()
-
-	if err := f.warmUp(ctx); err != nil {
+	lastID, err := f.warmUp(ctx)
+	if err != nil {
 		if errors.Is(err, services.ErrNoPublishedArticles) {
 			f.log.Debug("Can't warm up web service cache", "err", err.Error())
 		} else {
@@ -56,28 +70,63 @@ func (f *NewsFetcher) Start(ctx context.Context) error {
 		}
 	}
 
+	delay := watchRetry
+
 	for {
+		err := f.newsService.WatchPublished(ctx, lastID, func(article models.Article) error {
+			added, err := f.newsCache.AddArticle(ctx, &article)
+			if err != nil {
+				f.log.Error("Can't save new article in cache", "err", err.Error())
+			}
+
+			if added {
+				for _, announcer := range f.announcers {
+					announcer.Announce(article)
+				}
+			}
+
+			lastID = article.ArticleID
+			delay = watchRetry
+
+			return nil
+		})
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		if errors.Is(err, services.ErrArticleNotFound) {
+			// Resume point is gone, cache is reloaded and watched from its newest article.
+			f.log.Warn("Can't resume watching published articles", "last id", lastID)
+
+			if lastID, err = f.warmUp(ctx); err != nil && !errors.Is(err, services.ErrNoPublishedArticles) {
+				f.log.Error("Can't warm up web service cache", "err", err.Error())
+			}
+		} else {
+			f.log.Warn("Watching published articles stopped", "err", err)
+		}
+
 		select {
 		case <-ctx.Done():
 			return ctx.Err()
-		case <-ticker.C:
-			if err := f.intervalFetch(ctx); err != nil {
-				if errors.Is(err, services.ErrNoNewArticle) {
-					f.log.Debug("Can't do interval fetch", "err", err.Error())
-				} else {
-					f.log.Error("Can't do interval fetch", "err", err.Error())
-				}
-			}
+		case <-time.After(delay):
 		}
+
+		delay = min(2*delay, watchRetryMax)
 	}
 }
 
-func (f *NewsFetcher) FetchArticlesOnPage(ctx context.Context, page int64) ([]models.Article, error) {
-	articles, err := f.newsCache.GetArticlesOnPage(ctx, page)
-	if err != nil {
+// FetchArticles returns a page of articles after the cursor and cursor of the next page.
+// First page is served from cache, the following ones from news service.
+func (f *NewsFetcher) FetchArticles(ctx context.Context, cursor string) ([]models.Article, string, error) {
+	if cursor == "" {
+		articles, next, err := f.firstPage(ctx)
+		if err == nil {
+			return articles, next, nil
+		}
+
 		if errors.Is(err, storage.ErrCacheEmpty) {
 			go func() {
-				if err := f.warmUp(ctx); err != nil {
+				if _, err := f.warmUp(ctx); err != nil {
 					if !errors.Is(err, services.ErrNoPublishedArticles) {
 						f.log.Error("Can't warm up cache", "err", err.Error())
 					}
@@ -86,29 +135,46 @@ func (f *NewsFetcher) FetchArticlesOnPage(ctx context.Context, page int64) ([]mo
 		} else {
 			f.log.Error("Can't get articles form cache", "err", err.Error())
 		}
+	}
 
-		articles, err := f.newsService.GetArticlesByPage(ctx, page)
-		if err != nil {
-			switch {
-			case errors.Is(err, services.ErrNoPublishedArticles):
-				f.log.Debug("Can't get articles form news service", "err", err.Error())
-				return nil, err
-			case errors.Is(err, context.DeadlineExceeded):
-				f.log.Debug("Can't get articles form news service", "err", err.Error())
-				return nil, err
-			default:
-				f.log.Error("Can't get articles form news service", "err", err.Error())
-				return nil, err
-			}
+	articles, next, err := f.newsService.GetArticlesByPage(ctx, cursor)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrNoPublishedArticles), errors.Is(err, services.ErrInvalidCursor):
+			f.log.Debug("Can't get articles form news service", "err", err.Error())
+			return nil, "", err
+		case errors.Is(err, context.DeadlineExceeded):
+			f.log.Debug("Can't get articles form news service", "err", err.Error())
+			return nil, "", err
+		default:
+			f.log.Error("Can't get articles form news service", "err", err.Error())
+			return nil, "", err
 		}
+	}
 
-		return articles, nil
+	return articles, next, nil
+}
+
+// firstPage returns newest cached articles, one extra article tells whether there is a next page.
+func (f *NewsFetcher) firstPage(ctx context.Context) ([]models.Article, string, error) {
+	articles, err := f.newsCache.GetLatestArticles(ctx, f.pageLimit+1)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var next string
+
+	if len(articles) > f.pageLimit {
+		articles = articles[:f.pageLimit]
+		last := articles[len(articles)-1]
+		next = cursor.EncodePage(cursor.Page{PostedAt: last.PostedAt, ID: last.ArticleID})
 	}
 
-	return articles, nil
+	return articles, next, nil
 }
 
-func (f *NewsFetcher) warmUp(ctx context.Context) error {
+// warmUp loads posted articles into cache and returns id of the newest one.
+func (f *NewsFetcher) warmUp(ctx context.Context) (int64, error) {
 	var err error
 	var articles []models.Article
 
@@ -117,7 +183,7 @@ Loop:
 		articles, err = f.newsService.GetArticles(ctx)
 		if err != nil {
 			if errors.Is(err, services.ErrNoPublishedArticles) {
-				return err
+				return 0, err
 			} else {
 				time.Sleep(time.Duration(i) * time.Second)
 			}
@@ -127,25 +193,16 @@ Loop:
 	}
 
 	if err != nil {
-		return err
+		return 0, err
 	}
 
 	if err := f.newsCache.AddArticles(ctx, articles); err != nil {
 		f.log.Error("Can't warm up cache", "err", err.Error())
 	}
 
-	return nil
-}
-
-func (f *NewsFetcher) intervalFetch(ctx context.Context) error {
-	article, err := f.newsService.GetNewestArticle(ctx)
-	if err != nil {
-		return err
-	}
-
-	if err := f.newsCache.AddArticle(ctx, article); err != nil {
-		f.log.Error("Can't save new article in cache", "err", err.Error())
+	if len(articles) == 0 {
+		return 0, nil
 	}
 
-	return nil
+	return articles[len(articles)-1].ArticleID, nil
 }
@@ -93,6 +93,8 @@ func (c *Client) GetArticlesByUid(ctx context.Context, userID int64) ([]models.A
 			Link:       art.Link,
 			Excerpt:    art.Excerpt,
 			ImageURL:   art.ImageUrl,
+			Status:     art.Status,
+			Reason:     rejectionReason(art),
 		}
 	}
 
@@ -113,6 +115,8 @@ func (c *Client) SaveArticle(ctx context.Context, userID int64, link string) ([]
 			return nil, services.ErrNoOfferedArticles
 		case errors.Is(err, status.Error(codes.Unknown, "url is invalid")):
 			return nil, services.ErrInvalidUrl
+		case errors.Is(err, status.Error(codes.PermissionDenied, "url is forbidden")):
+			return nil, services.ErrForbiddenUrl
 		default:
 			return nil, fmt.Errorf("%s: %w", op, err)
 		}
@@ -129,6 +133,8 @@ func (c *Client) SaveArticle(ctx context.Context, userID int64, link string) ([]
 			Link:       art.Link,
 			Excerpt:    art.Excerpt,
 			ImageURL:   art.ImageUrl,
+			Status:     art.Status,
+			Reason:     rejectionReason(art),
 		}
 	}
 
@@ -151,6 +157,8 @@ func (c *Client) UpdateArticle(ctx context.Context, userID int64, artID int64, l
 			return nil, services.ErrArticleNotAvailable
 		case errors.Is(err, status.Error(codes.Unknown, "url is invalid")):
 			return nil, services.ErrInvalidUrl
+		case errors.Is(err, status.Error(codes.PermissionDenied, "url is forbidden")):
+			return nil, services.ErrForbiddenUrl
 		default:
 			return nil, fmt.Errorf("%s: %w", op, err)
 		}
@@ -167,6 +175,8 @@ func (c *Client) UpdateArticle(ctx context.Context, userID int64, artID int64, l
 			Link:       art.Link,
 			Excerpt:    art.Excerpt,
 			ImageURL:   art.ImageUrl,
+			Status:     art.Status,
+			Reason:     rejectionReason(art),
 		}
 	}
 
@@ -199,43 +209,51 @@ func (c *Client) DeleteArticle(ctx context.Context, userID int64, artID int64) (
 			Link:       art.Link,
 			Excerpt:    art.Excerpt,
 			ImageURL:   art.ImageUrl,
+			Status:     art.Status,
+			Reason:     rejectionReason(art),
 		}
 	}
 
 	return articles, nil
 }
 
-func (c *Client) GetNewestArticle(ctx context.Context) (*models.Article, error) {
-	const op = "services.newsgrpc.GetNewestArticle"
+// WatchPublished calls handle for every article published after the one with lastID
+// until the stream breaks.
+func (c *Client) WatchPublished(ctx context.Context, lastID int64, handle func(models.Article) error) error {
+	const op = "services.newsgrpc.WatchPublished"
 
-	resp, err := c.api.GetNewestArticle(ctx, &newsv1.GetNewestArticleRequest{})
+	stream, err := c.api.WatchPublished(ctx, &newsv1.WatchPublishedRequest{LastArticleId: lastID})
 	if err != nil {
-		if errors.Is(err, status.Error(codes.NotFound, "there is no new article")) {
-			return nil, fmt.Errorf("%s: %w", op, services.ErrNoNewArticle)
-		} else {
-			return nil, fmt.Errorf("%s: %w", op, err)
-		}
+		return fmt.Errorf("%s: %w", op, err)
 	}
 
-	art := resp.Articl
+	for {
+		art, err := stream.Recv()
+		if err != nil {
+			if errors.Is(err, status.Error(codes.NotFound, "article not found")) {
+				return services.ErrArticleNotFound
+			}
+			return fmt.Errorf("%s: %w", op, err)
+		}
 
-	postedAt, err := time.Parse(time.DateTime, art.PostedAt)
-	if err != nil {
-		return nil, fmt.Errorf("%s: %w", op, err)
-	}
+		postedAt, err := time.Parse(time.DateTime, art.PostedAt)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
 
-	article := models.Article{
-		ArticleID:  art.ArticleId,
-		UserName:   art.UserName,
-		SourceName: art.SourceName,
-		Title:      art.Title,
-		Link:       art.Link,
-		Excerpt:    art.Excerpt,
-		ImageURL:   art.ImageUrl,
-		PostedAt:   postedAt,
+		if err := handle(models.Article{
+			ArticleID:  art.ArticleId,
+			UserName:   art.UserName,
+			SourceName: art.SourceName,
+			Title:      art.Title,
+			Link:       art.Link,
+			Excerpt:    art.Excerpt,
+			ImageURL:   art.ImageUrl,
+			PostedAt:   postedAt,
+		}); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
 	}
-
-	return &article, nil
 }
 
 func (c *Client) GetArticles(ctx context.Context) ([]models.Article, error) {
@@ -273,15 +291,18 @@ func (c *Client) GetArticles(ctx context.Context) ([]models.Article, error) {
 	return articles, nil
 }
 
-func (c *Client) GetArticlesByPage(ctx context.Context, page int64) ([]models.Article, error) {
-	const op = "services.newsgrpc.GetArticles"
+func (c *Client) GetArticlesByPage(ctx context.Context, cursor string) ([]models.Article, string, error) {
+	const op = "services.newsgrpc.GetArticlesByPage"
 
-	resp, err := c.api.GetArticlesByPage(ctx, &newsv1.GetArticlesByPageRequest{Page: page})
+	resp, err := c.api.GetArticlesByPage(ctx, &newsv1.GetArticlesByPageRequest{Cursor: cursor})
 	if err != nil {
-		if errors.Is(err, status.Error(codes.NotFound, "there are no published articles")) {
-			return nil, fmt.Errorf("%s: %w", op, services.ErrNoPublishedArticles)
-		} else {
-			return nil, fmt.Errorf("%s: %w", op, err)
+		switch {
+		case errors.Is(err, status.Error(codes.NotFound, "there are no published articles")):
+			return nil, "", fmt.Errorf("%s: %w", op, services.ErrNoPublishedArticles)
+		case errors.Is(err, status.Error(codes.InvalidArgument, "invalid cursor")):
+			return nil, "", fmt.Errorf("%s: %w", op, services.ErrInvalidCursor)
+		default:
+			return nil, "", fmt.Errorf("%s: %w", op, err)
 		}
 	}
 
@@ -290,7 +311,7 @@ func (c *Client) GetArticlesByPage(ctx context.Context, page int64) ([]models.Ar
 	for i, art := range resp.Articles {
 		postedAt, err := time.Parse(time.DateTime, art.PostedAt)
 		if err != nil {
-			return nil, fmt.Errorf("%s: %w", op, err)
+			return nil, "", fmt.Errorf("%s: %w", op, err)
 		}
 
 		articles[i] = models.Article{
@@ -305,7 +326,650 @@ func (c *Client) GetArticlesByPage(ctx context.Context, page int64) ([]models.Ar
 		}
 	}
 
-	return articles, nil
+	return articles, resp.GetNextCursor(), nil
+}
+
+func (c *Client) ExplainFilter(ctx context.Context, link string, title string, categories []string, source string) (*models.FilterPreview, error) {
+	const op = "services.newsgrpc.ExplainFilter"
+
+	resp, err := c.api.ExplainFilter(ctx, &newsv1.ExplainFilterRequest{
+		Link:       link,
+		Title:      title,
+		Categories: categories,
+		SourceName: source,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, status.Error(codes.InvalidArgument, "link or title is required")):
+			return nil, services.ErrNothingToExplain
+		case errors.Is(err, status.Error(codes.Unknown, "url is invalid")):
+			return nil, services.ErrInvalidUrl
+		case errors.Is(err, status.Error(codes.PermissionDenied, "url is forbidden")):
+			return nil, services.ErrForbiddenUrl
+		default:
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	preview := &models.FilterPreview{
+		Accepted:     resp.Accepted,
+		Rule:         resp.Rule,
+		MatchedRules: resp.MatchedRules,
+		Categories:   resp.Categories,
+	}
+
+	if art := resp.GetArticle(); art != nil {
+		preview.Article = models.Article{
+			SourceName: art.SourceName,
+			Title:      art.Title,
+			Link:       art.Link,
+			Excerpt:    art.Excerpt,
+			ImageURL:   art.ImageUrl,
+		}
+	}
+
+	return preview, nil
+}
+
+func (c *Client) GetArticle(ctx context.Context, artID int64) (*models.ReaderArticle, error) {
+	const op = "services.newsgrpc.GetArticle"
+
+	resp, err := c.api.GetArticle(ctx, &newsv1.GetArticleRequest{ArticleId: artID})
+	if err != nil {
+		if errors.Is(err, status.Error(codes.NotFound, "article not found")) {
+			return nil, services.ErrArticleNotFound
+		} else {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	art := resp.GetArticle()
+
+	postedAt, err := time.Parse(time.DateTime, art.GetPostedAt())
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	content := resp.GetContent()
+
+	return &models.ReaderArticle{
+		Article: models.Article{
+			ArticleID:  art.GetArticleId(),
+			UserName:   art.GetUserName(),
+			SourceName: art.GetSourceName(),
+			Title:      art.GetTitle(),
+			Link:       art.GetLink(),
+			Excerpt:    art.GetExcerpt(),
+			ImageURL:   art.GetImageUrl(),
+			PostedAt:   postedAt,
+		},
+		Content: models.ArticleContent{
+			HTML:           content.GetHtml(),
+			Text:           content.GetText(),
+			WordCount:      content.GetWordCount(),
+			ReadingMinutes: content.GetReadingMinutes(),
+			Byline:         content.GetByline(),
+			Language:       content.GetLanguage(),
+		},
+	}, nil
+}
+
+func (c *Client) SearchArticles(ctx context.Context, query string, cursor string) ([]models.SearchResult, string, error) {
+	const op = "services.newsgrpc.SearchArticles"
+
+	resp, err := c.api.SearchArticles(ctx, &newsv1.SearchArticlesRequest{Query: query, Cursor: cursor})
+	if err != nil {
+		switch {
+		case errors.Is(err, status.Error(codes.InvalidArgument, "query is required")):
+			return nil, "", services.ErrEmptyQuery
+		case errors.Is(err, status.Error(codes.InvalidArgument, "invalid cursor")):
+			return nil, "", services.ErrInvalidCursor
+		case errors.Is(err, status.Error(codes.NotFound, "nothing found")):
+			return nil, "", services.ErrNothingFound
+		default:
+			return nil, "", fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	results := make([]models.SearchResult, len(resp.Results))
+
+	for i, res := range resp.Results {
+		art := res.GetArticle()
+
+		postedAt, err := time.Parse(time.DateTime, art.GetPostedAt())
+		if err != nil {
+			return nil, "", fmt.Errorf("%s: %w", op, err)
+		}
+
+		results[i] = models.SearchResult{
+			Article: models.Article{
+				ArticleID:  art.GetArticleId(),
+				UserName:   art.GetUserName(),
+				SourceName: art.GetSourceName(),
+				Title:      art.GetTitle(),
+				Link:       art.GetLink(),
+				Excerpt:    art.GetExcerpt(),
+				ImageURL:   art.GetImageUrl(),
+				PostedAt:   postedAt,
+			},
+			Rank:    res.GetRank(),
+			Snippet: res.GetSnippet(),
+		}
+	}
+
+	return results, resp.GetNextCursor(), nil
+}
+
+func (c *Client) ListSources(ctx context.Context) ([]models.Source, error) {
+	const op = "services.newsgrpc.ListSources"
+
+	resp, err := c.api.ListSources(ctx, &newsv1.ListSourcesRequest{})
+	if err != nil {
+		if errors.Is(err, status.Error(codes.NotFound, "there are no sources")) {
+			return nil, services.ErrNoSources
+		} else {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	sources := make([]models.Source, len(resp.Sources))
+
+	for i, src := range resp.Sources {
+		sources[i] = sourceModel(src)
+	}
+
+	return sources, nil
+}
+
+func (c *Client) ListSourceHealth(ctx context.Context) ([]models.Source, error) {
+	const op = "services.newsgrpc.ListSourceHealth"
+
+	resp, err := c.api.ListSourceHealth(ctx, &newsv1.ListSourceHealthRequest{})
+	if err != nil {
+		if errors.Is(err, status.Error(codes.NotFound, "there are no sources")) {
+			return nil, services.ErrNoSources
+		} else {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	sources := make([]models.Source, len(resp.Sources))
+
+	for i, src := range resp.Sources {
+		sources[i] = models.Source{
+			SourceID:      src.SourceId,
+			Name:          src.Name,
+			FeedURL:       src.FeedUrl,
+			Enabled:       src.Enabled,
+			FailureCount:  src.FailureCount,
+			LastError:     src.LastError,
+			LastErrorAt:   src.LastErrorAt,
+			LastSuccessAt: src.LastSuccessAt,
+			NextFetchAt:   src.NextFetchAt,
+		}
+	}
+
+	return sources, nil
+}
+
+func (c *Client) AddSource(ctx context.Context, name string, feedURL string) (*models.Source, error) {
+	const op = "services.newsgrpc.AddSource"
+
+	resp, err := c.api.AddSource(ctx, &newsv1.AddSourceRequest{Name: name, FeedUrl: feedURL})
+	if err != nil {
+		switch {
+		case errors.Is(err, status.Error(codes.InvalidArgument, "invalid source")):
+			return nil, services.ErrInvalidSource
+		case errors.Is(err, status.Error(codes.AlreadyExists, "source already exists")):
+			return nil, services.ErrSourceExists
+		default:
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	source := sourceModel(resp.Source)
+
+	return &source, nil
+}
+
+func (c *Client) UpdateSource(ctx context.Context, id int64, name string, feedURL string) (*models.Source, error) {
+	const op = "services.newsgrpc.UpdateSource"
+
+	resp, err := c.api.UpdateSource(ctx, &newsv1.UpdateSourceRequest{SourceId: id, Name: name, FeedUrl: feedURL})
+	if err != nil {
+		switch {
+		case errors.Is(err, status.Error(codes.InvalidArgument, "invalid source")):
+			return nil, services.ErrInvalidSource
+		case errors.Is(err, status.Error(codes.AlreadyExists, "source already exists")):
+			return nil, services.ErrSourceExists
+		case errors.Is(err, status.Error(codes.NotFound, "source not found")):
+			return nil, services.ErrSourceNotFound
+		default:
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	source := sourceModel(resp.Source)
+
+	return &source, nil
+}
+
+func (c *Client) DeleteSource(ctx context.Context, id int64) error {
+	const op = "services.newsgrpc.DeleteSource"
+
+	if _, err := c.api.DeleteSource(ctx, &newsv1.DeleteSourceRequest{SourceId: id}); err != nil {
+		if errors.Is(err, status.Error(codes.NotFound, "source not found")) {
+			return services.ErrSourceNotFound
+		} else {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	return nil
+}
+
+func (c *Client) SetSourceEnabled(ctx context.Context, id int64, enabled bool) (*models.Source, error) {
+	const op = "services.newsgrpc.SetSourceEnabled"
+
+	resp, err := c.api.SetSourceEnabled(ctx, &newsv1.SetSourceEnabledRequest{SourceId: id, Enabled: enabled})
+	if err != nil {
+		if errors.Is(err, status.Error(codes.NotFound, "source not found")) {
+			return nil, services.ErrSourceNotFound
+		} else {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	source := sourceModel(resp.Source)
+
+	return &source, nil
+}
+
+func (c *Client) ImportOPML(ctx context.Context, document []byte) (*models.OPMLReport, error) {
+	const op = "services.newsgrpc.ImportOPML"
+
+	resp, err := c.api.ImportOPML(ctx, &newsv1.ImportOPMLRequest{Opml: document})
+	if err != nil {
+		if errors.Is(err, status.Error(codes.InvalidArgument, "invalid opml document")) {
+			return nil, services.ErrInvalidOPML
+		} else {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	return &models.OPMLReport{
+		Added:    opmlEntries(resp.Added),
+		Skipped:  opmlEntries(resp.Skipped),
+		Rejected: opmlEntries(resp.Rejected),
+	}, nil
+}
+
+func (c *Client) ExportOPML(ctx context.Context) ([]byte, error) {
+	const op = "services.newsgrpc.ExportOPML"
+
+	resp, err := c.api.ExportOPML(ctx, &newsv1.ExportOPMLRequest{})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return resp.Opml, nil
+}
+
+func (c *Client) ReloadConfig(ctx context.Context) (*models.ReloadReport, error) {
+	const op = "services.newsgrpc.ReloadConfig"
+
+	resp, err := c.api.ReloadConfig(ctx, &newsv1.ReloadConfigRequest{})
+	if err != nil {
+		if st, ok := status.FromError(err); ok && st.Code() == codes.InvalidArgument {
+			return nil, fmt.Errorf("%w: %s", services.ErrInvalidConfig, st.Message())
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &models.ReloadReport{
+		Rules:         resp.Rules,
+		FetchInterval: resp.FetchInterval,
+		ReloadedAt:    resp.ReloadedAt,
+	}, nil
+}
+
+func (c *Client) ListWebhooks(ctx context.Context) ([]models.Webhook, error) {
+	const op = "services.newsgrpc.ListWebhooks"
+
+	resp, err := c.api.ListWebhooks(ctx, &newsv1.ListWebhooksRequest{})
+	if err != nil {
+		if errors.Is(err, status.Error(codes.NotFound, "there are no webhooks")) {
+			return nil, services.ErrNoWebhooks
+		} else {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	hooks := make([]models.Webhook, len(resp.Webhooks))
+
+	for i, hook := range resp.Webhooks {
+		hooks[i] = webhookModel(hook)
+	}
+
+	return hooks, nil
+}
+
+func (c *Client) AddWebhook(ctx context.Context, url string, secret string, events []string) (*models.Webhook, error) {
+	const op = "services.newsgrpc.AddWebhook"
+
+	resp, err := c.api.AddWebhook(ctx, &newsv1.AddWebhookRequest{Url: url, Secret: secret, Events: events})
+	if err != nil {
+		if errors.Is(err, status.Error(codes.InvalidArgument, "invalid webhook")) {
+			return nil, services.ErrInvalidWebhook
+		} else {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	hook := webhookModel(resp.Webhook)
+
+	return &hook, nil
+}
+
+func (c *Client) DeleteWebhook(ctx context.Context, id int64) error {
+	const op = "services.newsgrpc.DeleteWebhook"
+
+	if _, err := c.api.DeleteWebhook(ctx, &newsv1.DeleteWebhookRequest{WebhookId: id}); err != nil {
+		if errors.Is(err, status.Error(codes.NotFound, "webhook not found")) {
+			return services.ErrWebhookNotFound
+		} else {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	return nil
+}
+
+func (c *Client) ListWebhookDeliveries(ctx context.Context, webhookID int64, deliveryStatus string, limit int64) ([]models.WebhookDelivery, error) {
+	const op = "services.newsgrpc.ListWebhookDeliveries"
+
+	resp, err := c.api.ListWebhookDeliveries(ctx, &newsv1.ListWebhookDeliveriesRequest{
+		WebhookId: webhookID,
+		Status:    deliveryStatus,
+		Limit:     limit,
+	})
+	if err != nil {
+		if errors.Is(err, status.Error(codes.InvalidArgument, "invalid status")) {
+			return nil, services.ErrInvalidStatus
+		} else {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	deliveries := make([]models.WebhookDelivery, len(resp.Deliveries))
+
+	for i, d := range resp.Deliveries {
+		deliveries[i] = models.WebhookDelivery{
+			DeliveryID:    d.DeliveryId,
+			WebhookID:     d.WebhookId,
+			Event:         d.Event,
+			Payload:       d.Payload,
+			Status:        d.Status,
+			Attempts:      d.Attempts,
+			ResponseCode:  d.ResponseCode,
+			LastError:     d.LastError,
+			CreatedAt:     d.CreatedAt,
+			NextAttemptAt: d.NextAttemptAt,
+			DeliveredAt:   d.DeliveredAt,
+		}
+	}
+
+	return deliveries, nil
+}
+
+func (c *Client) AddFollower(ctx context.Context, follower models.Follower) error {
+	const op = "services.newsgrpc.AddFollower"
+
+	_, err := c.api.AddFollower(ctx, &newsv1.AddFollowerRequest{Follower: &newsv1.Follower{
+		ActorId:     follower.ActorID,
+		Inbox:       follower.Inbox,
+		SharedInbox: follower.SharedInbox,
+	}})
+	if err != nil {
+		if errors.Is(err, status.Error(codes.InvalidArgument, "invalid follower")) {
+			return services.ErrInvalidFollower
+		} else {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	return nil
+}
+
+func (c *Client) RemoveFollower(ctx context.Context, actorID string) error {
+	const op = "services.newsgrpc.RemoveFollower"
+
+	if _, err := c.api.RemoveFollower(ctx, &newsv1.RemoveFollowerRequest{ActorId: actorID}); err != nil {
+		if errors.Is(err, status.Error(codes.NotFound, "follower not found")) {
+			return services.ErrFollowerNotFound
+		} else {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	return nil
+}
+
+func (c *Client) ListFollowers(ctx context.Context) ([]models.Follower, error) {
+	const op = "services.newsgrpc.ListFollowers"
+
+	resp, err := c.api.ListFollowers(ctx, &newsv1.ListFollowersRequest{})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	followers := make([]models.Follower, len(resp.Followers))
+
+	for i, f := range resp.Followers {
+		followers[i] = models.Follower{
+			ActorID:     f.GetActorId(),
+			Inbox:       f.GetInbox(),
+			SharedInbox: f.GetSharedInbox(),
+		}
+	}
+
+	return followers, nil
+}
+
+func (c *Client) GetDigestSubscription(ctx context.Context, userID int64) (*models.DigestSubscription, error) {
+	const op = "services.newsgrpc.GetDigestSubscription"
+
+	resp, err := c.api.GetDigestSubscription(ctx, &newsv1.GetDigestSubscriptionRequest{UserId: userID})
+	if err != nil {
+		if errors.Is(err, status.Error(codes.NotFound, "subscription not found")) {
+			return nil, services.ErrSubscriptionNotFound
+		} else {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	return digestSubscriptionModel(resp.Subscription), nil
+}
+
+func (c *Client) SubscribeDigest(ctx context.Context, userID int64, frequency string) (*models.DigestSubscription, error) {
+	const op = "services.newsgrpc.SubscribeDigest"
+
+	resp, err := c.api.SubscribeDigest(ctx, &newsv1.SubscribeDigestRequest{UserId: userID, Frequency: frequency})
+	if err != nil {
+		if errors.Is(err, status.Error(codes.InvalidArgument, "invalid frequency")) {
+			return nil, services.ErrInvalidFrequency
+		} else {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	return digestSubscriptionModel(resp.Subscription), nil
+}
+
+func (c *Client) UnsubscribeDigest(ctx context.Context, userID int64) error {
+	const op = "services.newsgrpc.UnsubscribeDigest"
+
+	if _, err := c.api.UnsubscribeDigest(ctx, &newsv1.UnsubscribeDigestRequest{UserId: userID}); err != nil {
+		if errors.Is(err, status.Error(codes.NotFound, "subscription not found")) {
+			return services.ErrSubscriptionNotFound
+		} else {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	return nil
+}
+
+func (c *Client) UnsubscribeDigestByToken(ctx context.Context, token string) error {
+	const op = "services.newsgrpc.UnsubscribeDigestByToken"
+
+	if _, err := c.api.UnsubscribeDigestByToken(ctx, &newsv1.UnsubscribeDigestByTokenRequest{Token: token}); err != nil {
+		switch {
+		case errors.Is(err, status.Error(codes.InvalidArgument, "invalid token")):
+			return services.ErrInvalidToken
+		case errors.Is(err, status.Error(codes.NotFound, "subscription not found")):
+			return services.ErrSubscriptionNotFound
+		default:
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	return nil
+}
+
+func (c *Client) ListPendingSubmissions(ctx context.Context, limit int64) ([]models.Submission, error) {
+	const op = "services.newsgrpc.ListPendingSubmissions"
+
+	resp, err := c.api.ListPendingSubmissions(ctx, &newsv1.ListPendingSubmissionsRequest{Limit: limit})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	submissions := make([]models.Submission, len(resp.Submissions))
+
+	for i, sub := range resp.Submissions {
+		submissions[i] = submissionModel(sub)
+	}
+
+	return submissions, nil
+}
+
+func (c *Client) ApproveSubmission(ctx context.Context, artID int64, moderator string, note string) (*models.Submission, error) {
+	const op = "services.newsgrpc.ApproveSubmission"
+
+	resp, err := c.api.ApproveSubmission(ctx, &newsv1.ApproveSubmissionRequest{ArticleId: artID, Moderator: moderator, Note: note})
+	if err != nil {
+		switch {
+		case errors.Is(err, status.Error(codes.NotFound, "submission not found")):
+			return nil, services.ErrSubmissionNotFound
+		case errors.Is(err, status.Error(codes.FailedPrecondition, "submission isn't pending")):
+			return nil, services.ErrNotPending
+		default:
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	sub := submissionModel(resp.Submission)
+
+	return &sub, nil
+}
+
+func (c *Client) RejectSubmission(ctx context.Context, artID int64, moderator string, reason string) (*models.Submission, error) {
+	const op = "services.newsgrpc.RejectSubmission"
+
+	resp, err := c.api.RejectSubmission(ctx, &newsv1.RejectSubmissionRequest{ArticleId: artID, Moderator: moderator, Reason: reason})
+	if err != nil {
+		switch {
+		case errors.Is(err, status.Error(codes.InvalidArgument, "reason is required")):
+			return nil, services.ErrReasonRequired
+		case errors.Is(err, status.Error(codes.NotFound, "submission not found")):
+			return nil, services.ErrSubmissionNotFound
+		case errors.Is(err, status.Error(codes.FailedPrecondition, "submission isn't pending")):
+			return nil, services.ErrNotPending
+		default:
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	sub := submissionModel(resp.Submission)
+
+	return &sub, nil
+}
+
+func submissionModel(sub *newsv1.Submission) models.Submission {
+	art := sub.GetArticle()
+
+	return models.Submission{
+		Article: models.Article{
+			ArticleID:  art.GetArticleId(),
+			UserName:   art.GetUserName(),
+			SourceName: art.GetSourceName(),
+			Title:      art.GetTitle(),
+			Link:       art.GetLink(),
+			Excerpt:    art.GetExcerpt(),
+			ImageURL:   art.GetImageUrl(),
+			Status:     art.GetStatus(),
+			Reason:     rejectionReason(art),
+		},
+		UserID:      sub.GetUserId(),
+		Note:        art.GetModeratorNote(),
+		CreatedAt:   sub.GetCreatedAt(),
+		ModeratedBy: sub.GetModeratedBy(),
+		ModeratedAt: sub.GetModeratedAt(),
+	}
+}
+
+// rejectionReason returns note of the moderator which is shown to the user. Notes
+// to approved articles are for moderators only.
+func rejectionReason(art *newsv1.Article) string {
+	if art.GetStatus() != models.StatusRejected {
+		return ""
+	}
+
+	return art.GetModeratorNote()
+}
+
+func digestSubscriptionModel(sub *newsv1.DigestSubscription) *models.DigestSubscription {
+	return &models.DigestSubscription{
+		Frequency:  sub.GetFrequency(),
+		CreatedAt:  sub.GetCreatedAt(),
+		LastSentAt: sub.GetLastSentAt(),
+		NextSendAt: sub.GetNextSendAt(),
+	}
+}
+
+func webhookModel(hook *newsv1.Webhook) models.Webhook {
+	return models.Webhook{
+		WebhookID: hook.GetWebhookId(),
+		URL:       hook.GetUrl(),
+		Events:    hook.GetEvents(),
+		Enabled:   hook.GetEnabled(),
+		CreatedAt: hook.GetCreatedAt(),
+		Secret:    hook.GetSecret(),
+	}
+}
+
+func opmlEntries(grpcEntries []*newsv1.OPMLEntry) []models.OPMLEntry {
+	entries := make([]models.OPMLEntry, len(grpcEntries))
+
+	for i, e := range grpcEntries {
+		entries[i] = models.OPMLEntry{
+			Title:   e.Title,
+			FeedURL: e.FeedUrl,
+			Reason:  e.Reason,
+		}
+	}
+
+	return entries
+}
+
+func sourceModel(src *newsv1.Source) models.Source {
+	return models.Source{
+		SourceID: src.GetSourceId(),
+		Name:     src.GetName(),
+		FeedURL:  src.GetFeedUrl(),
+		Enabled:  src.GetEnabled(),
+	}
 }
 
 func interceptorLogger(l *slog.Logger) grpclog.Logger {
@@ -3,8 +3,9 @@ package cache
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"sync"
+	"strconv"
 
 	"newsWebApp/app/apiService/internal/models"
 	"newsWebApp/app/apiService/internal/storage"
@@ -12,19 +13,85 @@ import (
 	"github.com/redis/go-redis/v9"
 )
 
+const (
+	// feedKey is a sorted set of article ids scored by posting time. Ids are zero
+	// padded so articles posted in the same second are ordered by id.
+	feedKey = "articles:feed"
+	// dataKey is a hash of articles in JSON by the same ids.
+	dataKey = "articles:data"
+	// eventsChannel fans out live events to every replica.
+	eventsChannel = "events"
+)
+
+// addScript adds articles given as score, id, JSON and event in ARGV after the feed
+// size and events channel, and trims the feed in one step. Event is published only
+// by the replica which added the article first, when the channel isn't empty.
+// Script returns the number of articles which weren't in the feed.
+var addScript = redis.NewScript(`
+local size = tonumber(ARGV[1])
+local channel = ARGV[2]
+local total = 0
+for i = 3, #ARGV, 4 do
+	local added = redis.call('ZADD', KEYS[1], ARGV[i], ARGV[i + 1])
+	redis.call('HSET', KEYS[2], ARGV[i + 1], ARGV[i + 2])
+	total = total + added
+	if added == 1 and channel ~= '' then
+		redis.call('PUBLISH', channel, ARGV[i + 3])
+	end
+end
+local extra = redis.call('ZCARD', KEYS[1]) - size
+if extra > 0 then
+	local old = redis.call('ZRANGE', KEYS[1], 0, extra - 1)
+	redis.call('ZREMRANGEBYRANK', KEYS[1], 0, extra - 1)
+	redis.call('HDEL', KEYS[2], unpack(old))
+end
+return total
+`)
+
+// latestScript returns JSON of ARGV[1] newest articles.
+var latestScript = redis.NewScript(`
+local ids = redis.call('ZREVRANGE', KEYS[1], 0, tonumber(ARGV[1]) - 1)
+if #ids == 0 then
+	return {}
+end
+return redis.call('HMGET', KEYS[2], unpack(ids))
+`)
+
+// afterScript returns JSON of articles posted after the one with id in ARGV[1] or
+// false when it isn't in the feed.
+var afterScript = redis.NewScript(`
+local score = redis.call('ZSCORE', KEYS[1], ARGV[1])
+if not score then
+	return false
+end
+local ids = redis.call('ZRANGEBYSCORE', KEYS[1], score, '+inf')
+local newer = {}
+local found = false
+for _, id in ipairs(ids) do
+	if found then
+		newer[#newer + 1] = id
+	elseif id == ARGV[1] then
+		found = true
+	end
+end
+if #newer == 0 then
+	return {}
+end
+return redis.call('HMGET', KEYS[2], unpack(newer))
+`)
+
+// Cache keeps newest posted articles in Redis, so every api service replica
+// serves the same feed.
 type Cache struct {
-	c      *redis.Client
-	limit  int
-	actual int
-	mu     *sync.Mutex
+	c    *redis.Client
+	size int
 }
 
-func New(ctx context.Context, host, port string, limit int) (*Cache, error) {
+func New(ctx context.Context, host, port string, size int) (*Cache, error) {
 	const op = "storage.cache.New"
 
 	c := Cache{
-		limit: limit,
-		mu:    new(sync.Mutex),
+		size: size,
 	}
 
 	addr := host + ":" + port
@@ -42,89 +109,181 @@ func New(ctx context.Context, host, port string, limit int) (*Cache, error) {
 	return &c, nil
 }
 
-func (c *Cache) AddArticle(ctx context.Context, article *models.Article) error {
+// AddArticle caches the article and tells whether it wasn't cached before, so
+// of all replicas only one reacts to a new article.
+func (c *Cache) AddArticle(ctx context.Context, article *models.Article) (bool, error) {
 	const op = "storage.cache.AddArticle"
 
-	articleJSON, err := json.Marshal(article)
+	added, err := c.add(ctx, []models.Article{*article}, eventsChannel)
 	if err != nil {
-		return fmt.Errorf("%s: %w", op, err)
+		return false, fmt.Errorf("%s: %w", op, err)
 	}
 
-	c.mu.Lock()
-	if err := c.c.Set(ctx, fmt.Sprintf("article #%d", c.actual), articleJSON, 0).Err(); err != nil {
-		return fmt.Errorf("%s: %w", op, err)
+	return added > 0, nil
+}
+
+func (c *Cache) AddArticles(ctx context.Context, articles []models.Article) error {
+	const op = "storage.cache.AddArticles"
+
+	if len(articles) == 0 {
+		return nil
 	}
 
-	c.actual++
-	c.mu.Unlock()
+	// Only the newest ones survive trimming, older are skipped to keep the script short.
+	if len(articles) > c.size {
+		articles = articles[len(articles)-c.size:]
+	}
+
+	if _, err := c.add(ctx, articles, ""); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
 
 	return nil
 }
 
-func (c *Cache) AddArticles(ctx context.Context, articles []models.Article) error {
-	const op = "storage.cache.AddArticles"
+// GetLatestArticles returns up to n newest cached articles.
+func (c *Cache) GetLatestArticles(ctx context.Context, n int) ([]models.Article, error) {
+	const op = "storage.cache.GetLatestArticles"
+
+	res, err := latestScript.Run(ctx, c.c, []string{feedKey, dataKey}, n).Slice()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
 
-	c.mu.Lock()
-	c.actual = len(articles)
-	c.mu.Unlock()
+	if len(res) == 0 {
+		return nil, fmt.Errorf("%s: %w", op, storage.ErrCacheEmpty)
+	}
 
-	values := make(map[string]interface{}, len(articles))
+	articles, err := unmarshalArticles(res)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
 
-	for i, article := range articles {
-		articleJSON, err := json.Marshal(article)
-		if err != nil {
-			return fmt.Errorf("%s: %w", op, err)
+	return articles, nil
+}
+
+// ArticlesAfter returns cached articles posted after the one with id, oldest first.
+func (c *Cache) ArticlesAfter(ctx context.Context, id int64) ([]models.Article, error) {
+	const op = "storage.cache.ArticlesAfter"
+
+	res, err := afterScript.Run(ctx, c.c, []string{feedKey, dataKey}, member(id)).Slice()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, fmt.Errorf("%s: %w", op, storage.ErrNotCached)
 		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	articles, err := unmarshalArticles(res)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return articles, nil
+}
 
-		values[fmt.Sprintf("article #%d", i)] = articleJSON
+// PublishEvent sends event to every replica.
+func (c *Cache) PublishEvent(ctx context.Context, event models.Event) error {
+	const op = "storage.cache.PublishEvent"
+
+	eventJSON, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
 	}
 
-	if err := c.c.MSet(ctx, values).Err(); err != nil {
+	if err := c.c.Publish(ctx, eventsChannel, eventJSON).Err(); err != nil {
 		return fmt.Errorf("%s: %w", op, err)
 	}
 
 	return nil
 }
 
-func (c *Cache) GetArticlesOnPage(ctx context.Context, page int64) ([]models.Article, error) {
-	const op = "storage.cache.GetArticlesOnPage"
-	limit := 0
+// SubscribeEvents calls handle for every published event until ctx is done or
+// subscription breaks.
+func (c *Cache) SubscribeEvents(ctx context.Context, handle func(models.Event)) error {
+	const op = "storage.cache.SubscribeEvents"
 
-	if c.actual == 0 {
-		return nil, fmt.Errorf("%s: %w", op, storage.ErrCacheEmpty)
-	}
+	sub := c.c.Subscribe(ctx, eventsChannel)
+	defer sub.Close()
 
-	amount := int(page) * c.limit
-	if c.actual >= amount {
-		limit = amount
-	} else {
-		limit = c.actual
+	if _, err := sub.Receive(ctx); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
 	}
 
-	articles := make([]models.Article, 0, limit)
-	keys := make([]string, 0, limit)
+	ch := sub.Channel()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case msg, ok := <-ch:
+			if !ok {
+				return fmt.Errorf("%s: %w", op, redis.ErrClosed)
+			}
 
-	for i := c.actual - 1; len(keys) < limit; i-- {
-		keys = append(keys, fmt.Sprintf("article #%d", i))
+			var event models.Event
+
+			if err := json.Unmarshal([]byte(msg.Payload), &event); err != nil {
+				continue
+			}
+
+			handle(event)
+		}
 	}
+}
 
-	res, err := c.c.MGet(ctx, keys...).Result()
-	if err != nil {
-		return nil, fmt.Errorf("%s: %w", op, err)
+func (c *Cache) add(ctx context.Context, articles []models.Article, channel string) (int64, error) {
+	args := make([]interface{}, 0, 2+4*len(articles))
+	args = append(args, c.size, channel)
+
+	for _, article := range articles {
+		articleJSON, err := json.Marshal(article)
+		if err != nil {
+			return 0, err
+		}
+
+		eventJSON, err := json.Marshal(models.Event{
+			ID:   strconv.FormatInt(article.ArticleID, 10),
+			Type: models.EventArticle,
+			Data: articleJSON,
+		})
+		if err != nil {
+			return 0, err
+		}
+
+		args = append(args,
+			strconv.FormatInt(article.PostedAt.Unix(), 10),
+			member(article.ArticleID),
+			articleJSON,
+			eventJSON,
+		)
 	}
 
+	return addScript.Run(ctx, c.c, []string{feedKey, dataKey}, args...).Int64()
+}
+
+func member(id int64) string {
+	return fmt.Sprintf("%019d", id)
+}
+
+func unmarshalArticles(res []interface{}) ([]models.Article, error) {
+	articles := make([]models.Article, 0, len(res))
+
 	for _, r := range res {
 		re, ok := r.(string)
+		if !ok {
+			continue
+		}
+
 		var article models.Article
-		if ok {
-			err = json.Unmarshal([]byte(re), &article)
-			if err != nil {
-				return nil, fmt.Errorf("%s: %w", op, err)
-			}
+
+		if err := json.Unmarshal([]byte(re), &article); err != nil {
+			return nil, err
 		}
 
 		articles = append(articles, article)
 	}
+
 	return articles, nil
 }
 
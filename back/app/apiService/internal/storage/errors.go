@@ -5,4 +5,5 @@ import "errors"
 var (
 	ErrCacheEmpty    = errors.New("cache is empty")
 	ErrCacheNotEmpty = errors.New("cache is not empty")
+	ErrNotCached     = errors.New("article is not cached")
 )
@@ -8,20 +8,33 @@ import (
 	"log/slog"
 	"os"
 	"os/signal"
+	"sync"
 	"syscall"
 	"time"
 
 	"newsWebApp/app/newsService/internal/config"
 	grpcServer "newsWebApp/app/newsService/internal/grpc/server"
 	"newsWebApp/app/newsService/internal/services/cacher"
+	"newsWebApp/app/newsService/internal/services/digest"
+	"newsWebApp/app/newsService/internal/services/downloader"
 	"newsWebApp/app/newsService/internal/services/fetcher"
+	"newsWebApp/app/newsService/internal/services/followers"
+	"newsWebApp/app/newsService/internal/services/moderator"
 	"newsWebApp/app/newsService/internal/services/processor"
+	"newsWebApp/app/newsService/internal/services/publisher"
+	"newsWebApp/app/newsService/internal/services/reloader"
+	"newsWebApp/app/newsService/internal/services/scheduler"
+	"newsWebApp/app/newsService/internal/services/sinks"
+	"newsWebApp/app/newsService/internal/services/sourcer"
+	"newsWebApp/app/newsService/internal/services/webhooks"
 	"newsWebApp/app/newsService/internal/storage/psql"
 	"newsWebApp/app/newsService/internal/storage/redis"
 	"newsWebApp/migrations/migrator"
 	"newsWebApp/pkg/logs"
 )
 
+const shutdownTimeout = 10 * time.Second
+
 type App struct {
 	cfg        *config.Config
 	log        *slog.Logger
@@ -29,6 +42,11 @@ type App struct {
 	linkCache  *redis.Storage
 	fetcher    *fetcher.Fetcher
 	processor  *processor.Processor
+	reloader   *reloader.Reloader
+	publisher  *publisher.Publisher
+	webhooks   *webhooks.Dispatcher
+	relay      *sinks.Relay
+	digester   *digest.Digester
 	gRPCServer *grpcServer.Server
 }
 
@@ -68,11 +86,49 @@ func New() *App {
 
 	linkCacher := cacher.New(a.linkCache)
 
+	a.webhooks = webhooks.New(psql.NewWebhookStorage(a.db),
+		a.cfg.Webhooks.Timeout,
+		a.cfg.Webhooks.CheckInterval,
+		a.cfg.Webhooks.Batch,
+		a.cfg.Webhooks.MaxAttempts,
+		a.cfg.Webhooks.Backoff,
+		a.cfg.Webhooks.BackoffMax,
+		a.log,
+	)
+
+	filterStor := psql.NewFilterStorage(a.db)
+
+	settings, err := reloader.Settings(ctx, a.cfg, filterStor)
+	if err != nil {
+		a.log.Error("Failed to load filter rules", "err", err.Error())
+		os.Exit(1)
+	}
+
+	loader := downloader.New(a.cfg.Extraction.Workers,
+		a.cfg.Extraction.HostRate,
+		a.cfg.Extraction.HostBurst,
+		a.cfg.Extraction.Timeout,
+	)
+
 	a.fetcher = fetcher.New(articleStor,
 		sourceStor,
 		linkCacher,
-		a.cfg.Manager.FetchInterval,
-		a.cfg.Manager.FilterKeywords,
+		loader,
+		loader.Guarded(a.cfg.Extraction.MaxRedirects),
+		filterStor,
+		a.webhooks,
+		settings,
+		fetcher.Options{
+			SourceBudget:      a.cfg.Extraction.SourceBudget,
+			MaxPageSize:       a.cfg.Extraction.MaxPageSize,
+			Backoff:           a.cfg.Manager.FetchBackoff,
+			MaxBackoff:        a.cfg.Manager.FetchBackoffMax,
+			MaxFailures:       a.cfg.Manager.MaxFetchFailures,
+			DupDistance:       a.cfg.Manager.DupDistance,
+			DupWindow:         a.cfg.Manager.DupWindow,
+			PreferredSources:  a.cfg.Manager.PreferredSources,
+			DecisionRetention: a.cfg.Filter.DecisionRetention,
+		},
 		a.log,
 	)
 
@@ -82,7 +138,61 @@ func New() *App {
 		a.log,
 	)
 
-	a.gRPCServer = grpcServer.New(a.cfg.GRPC.Port, a.log, a.processor)
+	outSinks, err := configuredSinks(a.cfg.Sinks)
+	if err != nil {
+		a.log.Error("Failed to create sinks", "err", err.Error())
+		os.Exit(1)
+	}
+
+	a.relay = sinks.New(psql.NewSinkStorage(a.db),
+		outSinks,
+		a.cfg.Sinks.CheckInterval,
+		a.cfg.Sinks.Timeout,
+		a.cfg.Sinks.Batch,
+		a.cfg.Sinks.Backoff,
+		a.cfg.Sinks.BackoffMax,
+		a.log,
+	)
+
+	publishScheduler, err := scheduler.New(articleStor,
+		[]scheduler.Notifier{a.webhooks, a.relay},
+		a.cfg.Publishing.Interval,
+		a.cfg.Publishing.QuietFrom,
+		a.cfg.Publishing.QuietTo,
+		a.cfg.Publishing.TimeZone,
+		a.cfg.Publishing.DailyCap,
+		a.log,
+	)
+	if err != nil {
+		a.log.Error("Failed to create publishing scheduler", "err", err.Error())
+		os.Exit(1)
+	}
+
+	a.publisher = publisher.New(articleStor, publishScheduler, a.cfg.Publishing.CheckInterval, a.log)
+
+	sourceManager := sourcer.New(sourceStor, a.log)
+
+	a.reloader = reloader.New(a.cfg.Path, filterStor, a.fetcher, a.log)
+
+	followerRegistry := followers.New(psql.NewFollowerStorage(a.db), a.log)
+
+	a.digester, err = newDigester(a.cfg.Digest, articleStor, psql.NewDigestStorage(a.db), a.log)
+	if err != nil {
+		a.log.Error("Failed to create digest", "err", err.Error())
+		os.Exit(1)
+	}
+
+	a.gRPCServer = grpcServer.New(a.cfg.GRPC.Port,
+		a.log,
+		a.processor,
+		sourceManager,
+		a.reloader,
+		a.publisher,
+		a.webhooks,
+		followerRegistry,
+		a.digester,
+		moderator.New(articleStor, a.log),
+	)
 
 	return &a
 }
@@ -100,7 +210,27 @@ func (a *App) MustRun() {
 	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
 	defer cancel()
 
+	// Loops use storage till they return, connections are closed after all of them.
+	var wg sync.WaitGroup
+
+	wg.Add(1)
 	go func() {
+		defer wg.Done()
+		a.reloader.Watch(ctx)
+	}()
+
+	a.runLoop(ctx, &wg, "publisher", a.publisher.Start)
+	a.runLoop(ctx, &wg, "webhooks", a.webhooks.Start)
+	a.runLoop(ctx, &wg, "sinks", a.relay.Start)
+
+	if a.cfg.Digest.Enabled {
+		a.runLoop(ctx, &wg, "digest", a.digester.Start)
+	}
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+
 		if err := a.fetcher.Start(ctx); err != nil {
 			if !errors.Is(err, context.Canceled) {
 				a.log.Error("Failed ower working fetcher in news grpc service", "err", err.Error())
@@ -113,10 +243,41 @@ func (a *App) MustRun() {
 	signal.Notify(stop, syscall.SIGTERM, syscall.SIGINT)
 	<-stop
 
-	a.mustStop()
+	cancel()
+
+	a.mustStop(&wg)
 }
 
-func (a *App) mustStop() {
+// runLoop starts the background loop under wg.
+func (a *App) runLoop(ctx context.Context, wg *sync.WaitGroup, name string, start func(context.Context) error) {
+	wg.Add(1)
+
+	go func() {
+		defer wg.Done()
+
+		if err := start(ctx); err != nil && !errors.Is(err, context.Canceled) {
+			a.log.Error("Failed ower working "+name+" in news grpc service", "err", err.Error())
+		}
+	}()
+}
+
+// mustStop waits for in-flight requests, downloads and background loops before closing
+// connections they use.
+func (a *App) mustStop(loops *sync.WaitGroup) {
+	a.gRPCServer.Stop()
+
+	done := make(chan struct{})
+	go func() {
+		loops.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(shutdownTimeout):
+		a.log.Warn("Background loops didn't stop in time")
+	}
+
 	if err := a.db.Close(); err != nil {
 		a.log.Error("Closing connection to news storage", "err", err.Error())
 	}
@@ -125,11 +286,65 @@ func (a *App) mustStop() {
 		a.log.Error("Closing connection to session storage", "err", err.Error())
 	}
 
-	a.gRPCServer.Stop()
-
 	a.log.Info("News grpc service stoped gracefully")
 }
 
+func configuredSinks(cfg config.Sinks) ([]sinks.Sink, error) {
+	configured := []sinks.Sink{}
+
+	if cfg.Telegram.Enabled {
+		if cfg.Telegram.Token == "" || cfg.Telegram.ChatID == "" {
+			return nil, fmt.Errorf("telegram sink needs chat_id and TELEGRAM_BOT_TOKEN")
+		}
+
+		configured = append(configured, sinks.NewTelegram(cfg.Telegram.BaseURL, cfg.Telegram.Token, cfg.Telegram.ChatID, cfg.Timeout))
+	}
+
+	return configured, nil
+}
+
+// newDigester returns digester managing subscriptions. Digests are sent only when
+// it's enabled, then secret for unsubscribe links is required.
+func newDigester(cfg config.Digest, articles digest.ArticleStorage, subs digest.DigestStorage, log *slog.Logger) (*digest.Digester, error) {
+	var mailer digest.Mailer
+
+	if cfg.Enabled {
+		if cfg.Secret == "" {
+			return nil, fmt.Errorf("digest needs DIGEST_SECRET")
+		}
+
+		smtpMailer, err := digest.NewSMTP(cfg.SMTP.Host,
+			cfg.SMTP.Port,
+			cfg.SMTP.Security,
+			cfg.SMTP.Username,
+			cfg.SMTP.Password,
+			cfg.SMTP.From,
+			cfg.SMTP.Timeout,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		mailer = smtpMailer
+	}
+
+	return digest.New(subs,
+		articles,
+		mailer,
+		cfg.Secret,
+		cfg.PublicURL,
+		cfg.SendAt,
+		cfg.Weekday,
+		cfg.TimeZone,
+		cfg.CheckInterval,
+		cfg.Batch,
+		cfg.MaxArticles,
+		cfg.Retry,
+		cfg.SMTP.Timeout,
+		log,
+	)
+}
+
 func connectToDB(storage config.Postgres) (*sql.DB, error) {
 	var err error
 	var db *sql.DB
@@ -15,6 +15,13 @@ type Config struct {
 	LinkStorage Redis       `yaml:"redis_storage"`
 	GRPC        GRPCConfig  `yaml:"grpc_news"`
 	Manager     NewsManager `yaml:"news_managment"`
+	Extraction  Extraction  `yaml:"extraction"`
+	Filter      Filter      `yaml:"filter"`
+	Publishing  Publishing  `yaml:"publishing"`
+	Webhooks    Webhooks    `yaml:"webhooks"`
+	Sinks       Sinks       `yaml:"sinks"`
+	Digest      Digest      `yaml:"digest"`
+	Path        string      `yaml:"-"`
 }
 
 type Postgres struct {
@@ -38,29 +45,141 @@ type GRPCConfig struct {
 }
 
 type NewsManager struct {
-	FilterKeywords []string      `yaml:"filter_keywords"`
-	FetchInterval  time.Duration `yaml:"fetch_interval"`
-	ArticlesLimit  int           `yaml:"articles_limit"`
+	FilterKeywords   []string      `yaml:"filter_keywords"`
+	FetchInterval    time.Duration `yaml:"fetch_interval"`
+	FetchBackoff     time.Duration `yaml:"fetch_backoff" env-default:"10m"`
+	FetchBackoffMax  time.Duration `yaml:"fetch_backoff_max" env-default:"24h"`
+	MaxFetchFailures int           `yaml:"max_fetch_failures" env-default:"10"`
+	DupDistance      int           `yaml:"duplicate_distance" env-default:"3"`
+	DupWindow        time.Duration `yaml:"duplicate_window" env-default:"72h"`
+	PreferredSources []string      `yaml:"preferred_sources"`
+	ArticlesLimit    int           `yaml:"articles_limit"`
 }
 
-func MustLoad() *Config {
-	cfg := new(Config)
+// Publishing is a schedule of posts shared by all replicas. Quiet hours are "HH:MM"
+// in TimeZone, DailyCap 0 is unlimited.
+type Publishing struct {
+	Interval      time.Duration `yaml:"interval" env-default:"15m"`
+	CheckInterval time.Duration `yaml:"check_interval" env-default:"30s"`
+	QuietFrom     string        `yaml:"quiet_from"`
+	QuietTo       string        `yaml:"quiet_to"`
+	TimeZone      string        `yaml:"time_zone" env-default:"UTC"`
+	DailyCap      int           `yaml:"daily_cap"`
+}
+
+// Webhooks sets delivery of events to subscribed endpoints. Failed delivery is retried
+// after Backoff doubled on every attempt up to BackoffMax, MaxAttempts in total.
+type Webhooks struct {
+	Timeout       time.Duration `yaml:"timeout" env-default:"10s"`
+	CheckInterval time.Duration `yaml:"check_interval" env-default:"5s"`
+	Batch         int           `yaml:"batch" env-default:"20"`
+	MaxAttempts   int           `yaml:"max_attempts" env-default:"8"`
+	Backoff       time.Duration `yaml:"backoff" env-default:"30s"`
+	BackoffMax    time.Duration `yaml:"backoff_max" env-default:"6h"`
+}
+
+// Sinks sets relay of posted articles to outside channels. Failed sink is retried
+// after Backoff doubled on every failure up to BackoffMax.
+type Sinks struct {
+	CheckInterval time.Duration `yaml:"check_interval" env-default:"30s"`
+	Timeout       time.Duration `yaml:"timeout" env-default:"10s"`
+	Batch         int           `yaml:"batch" env-default:"20"`
+	Backoff       time.Duration `yaml:"backoff" env-default:"30s"`
+	BackoffMax    time.Duration `yaml:"backoff_max" env-default:"30m"`
+	Telegram      Telegram      `yaml:"telegram"`
+}
+
+// Telegram is a channel posts are sent to by the bot with Token.
+type Telegram struct {
+	Enabled bool   `yaml:"enabled"`
+	BaseURL string `yaml:"base_url" env-default:"https://api.telegram.org"`
+	ChatID  string `yaml:"chat_id"`
+	Token   string `env:"TELEGRAM_BOT_TOKEN"`
+}
+
+// Digest sends email digests of posted articles at SendAt ("HH:MM" in TimeZone),
+// weekly ones on Weekday. Unsubscribe links lead to PublicURL and are signed with Secret.
+type Digest struct {
+	Enabled       bool          `yaml:"enabled"`
+	PublicURL     string        `yaml:"public_url" env-default:"http://localhost:8080"`
+	SendAt        string        `yaml:"send_at" env-default:"08:00"`
+	Weekday       string        `yaml:"weekday" env-default:"monday"`
+	TimeZone      string        `yaml:"time_zone" env-default:"UTC"`
+	CheckInterval time.Duration `yaml:"check_interval" env-default:"1m"`
+	Batch         int           `yaml:"batch" env-default:"20"`
+	MaxArticles   int           `yaml:"max_articles" env-default:"100"`
+	Retry         time.Duration `yaml:"retry" env-default:"30m"`
+	Secret        string        `env:"DIGEST_SECRET"`
+	SMTP          SMTP          `yaml:"smtp"`
+}
+
+// SMTP is a server digests are sent through. Security is none, starttls or tls.
+type SMTP struct {
+	Host     string        `yaml:"host" env-default:"localhost"`
+	Port     int           `yaml:"port" env-default:"587"`
+	Security string        `yaml:"security" env-default:"starttls"`
+	Username string        `yaml:"username"`
+	Password string        `env:"SMTP_PASSWORD"`
+	From     string        `yaml:"from" env-default:"News <news@localhost>"`
+	Timeout  time.Duration `yaml:"timeout" env-default:"10s"`
+}
+
+// Filter keeps rules in config or in filter_rules table. FilterKeywords are used
+// when there are no rules. Decisions older than DecisionRetention are deleted, 0 keeps them.
+type Filter struct {
+	Storage           string        `yaml:"storage" env-default:"config"`
+	Default           string        `yaml:"default" env-default:"exclude"`
+	Rules             []FilterRule  `yaml:"rules"`
+	DecisionRetention time.Duration `yaml:"decision_retention" env-default:"720h"`
+}
 
+type FilterRule struct {
+	Name    string   `yaml:"name"`
+	Action  string   `yaml:"action"`
+	Expr    string   `yaml:"expr"`
+	Sources []string `yaml:"sources"`
+}
+
+type Extraction struct {
+	Workers      int           `yaml:"workers" env-default:"8"`
+	HostRate     time.Duration `yaml:"host_rate" env-default:"1s"`
+	HostBurst    int           `yaml:"host_burst" env-default:"2"`
+	SourceBudget int           `yaml:"source_budget" env-default:"30"`
+	Timeout      time.Duration `yaml:"timeout" env-default:"6s"`
+	MaxRedirects int           `yaml:"max_redirects" env-default:"5"`
+	MaxPageSize  int64         `yaml:"max_page_size" env-default:"5242880"`
+}
+
+func MustLoad() *Config {
 	path, err := fetchConfigPath()
 	if err != nil {
 		panic(err)
 	}
 
+	cfg, err := Load(path)
+	if err != nil {
+		panic(err)
+	}
+
+	return cfg
+}
+
+// Load reads config from the file. It's used on start and on every reload.
+func Load(path string) (*Config, error) {
+	cfg := new(Config)
+
 	if err := cleanenv.ReadConfig(path, cfg); err != nil {
-		panic("failed to read config: " + err.Error())
+		return nil, fmt.Errorf("failed to read config: %w", err)
 	}
 
+	cfg.Path = path
+
 	cfg.Storage.Password = os.Getenv("POSTGRES_PASSWORD")
 	if cfg.Storage.Password == "" {
-		panic("postgress password is not specified in environment variables")
+		return nil, fmt.Errorf("postgress password is not specified in environment variables")
 	}
 
-	return cfg
+	return cfg, nil
 }
 
 func fetchConfigPath() (string, error) {
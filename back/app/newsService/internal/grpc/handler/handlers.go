@@ -20,18 +20,81 @@ type NewsService interface {
 	SaveArticleFromUser(ctx context.Context, userID int64, link string) ([]models.Article, error)
 	UpdateArticleByID(ctx context.Context, userID int64, artID int64, link string) ([]models.Article, error)
 	DeleteArticleByID(ctx context.Context, userID int64, artID int64) ([]models.Article, error)
-	SelectAndSendArticle(ctx context.Context) (*models.Article, error)
+	NewestPostedArticle(ctx context.Context) (*models.Article, error)
 	SelectPostedArticles(ctx context.Context) ([]models.Article, error)
-	SelectPostedArticlesWithLimit(ctx context.Context, page int64) ([]models.Article, error)
+	SelectPostedArticlesAfter(ctx context.Context, token string) ([]models.Article, string, error)
+	ExplainFilter(ctx context.Context, link string, title string, categories []string, source string) (*models.FilterExplanation, error)
+	GetArticle(ctx context.Context, artID int64) (*models.ReaderArticle, error)
+	SearchArticles(ctx context.Context, query string, token string) ([]models.SearchResult, string, error)
+}
+
+type SourceService interface {
+	SourcesHealth(ctx context.Context) ([]models.Source, error)
+	AddSource(ctx context.Context, name string, feedURL string) (*models.Source, error)
+	UpdateSource(ctx context.Context, id int64, name string, feedURL string) (*models.Source, error)
+	DeleteSource(ctx context.Context, id int64) error
+	SetSourceEnabled(ctx context.Context, id int64, enabled bool) (*models.Source, error)
+	ImportOPML(ctx context.Context, document []byte) (*models.OPMLReport, error)
+	ExportOPML(ctx context.Context) ([]byte, error)
+}
+
+type ConfigService interface {
+	ReloadConfig(ctx context.Context) (*models.ReloadReport, error)
+}
+
+type PublishService interface {
+	Watch(ctx context.Context, lastID int64, send func(models.Article) error) error
+}
+
+type WebhookService interface {
+	ListWebhooks(ctx context.Context) ([]models.Webhook, error)
+	AddWebhook(ctx context.Context, endpoint string, secret string, events []string) (*models.Webhook, error)
+	DeleteWebhook(ctx context.Context, id int64) error
+	Deliveries(ctx context.Context, webhookID int64, status string, limit int) ([]models.WebhookDelivery, error)
+}
+
+type FollowerService interface {
+	AddFollower(ctx context.Context, actorID string, inbox string, sharedInbox string) error
+	RemoveFollower(ctx context.Context, actorID string) error
+	ListFollowers(ctx context.Context) ([]models.Follower, error)
+}
+
+type DigestService interface {
+	Subscription(ctx context.Context, userID int64) (*models.DigestSubscription, error)
+	Subscribe(ctx context.Context, userID int64, frequency string) (*models.DigestSubscription, error)
+	Unsubscribe(ctx context.Context, userID int64) error
+	UnsubscribeByToken(ctx context.Context, token string) error
+}
+
+type ModerationService interface {
+	ListPendingSubmissions(ctx context.Context, limit int) ([]models.Article, error)
+	ApproveSubmission(ctx context.Context, artID int64, moderator string, note string) (*models.Article, error)
+	RejectSubmission(ctx context.Context, artID int64, moderator string, reason string) (*models.Article, error)
 }
 
 type serverAPI struct {
 	newsv1.UnimplementedNewsServer
-	newsService NewsService
+	newsService   NewsService
+	sourceService SourceService
+	configService ConfigService
+	pubService    PublishService
+	hookService   WebhookService
+	followService FollowerService
+	digestService DigestService
+	modService    ModerationService
 }
 
-func Register(grpcSrv *grpc.Server, nS NewsService) {
-	newsv1.RegisterNewsServer(grpcSrv, &serverAPI{newsService: nS})
+func Register(grpcSrv *grpc.Server, nS NewsService, sS SourceService, cS ConfigService, pS PublishService, wS WebhookService, fS FollowerService, dS DigestService, mS ModerationService) {
+	newsv1.RegisterNewsServer(grpcSrv, &serverAPI{
+		newsService:   nS,
+		sourceService: sS,
+		configService: cS,
+		pubService:    pS,
+		hookService:   wS,
+		followService: fS,
+		digestService: dS,
+		modService:    mS,
+	})
 }
 
 func (s *serverAPI) GetArticlesByUid(ctx context.Context, req *newsv1.GetArticlesByUidRequest) (*newsv1.GetArticlesByUidResponse, error) {
@@ -49,13 +112,15 @@ func (s *serverAPI) GetArticlesByUid(ctx context.Context, req *newsv1.GetArticle
 
 	for i, art := range articles {
 		grpcArticles[i] = &newsv1.Article{
-			ArticleId:  art.ID,
-			UserName:   art.UserName,
-			SourceName: art.SourceName,
-			Title:      art.Title,
-			Link:       art.Link,
-			Excerpt:    art.Excerpt,
-			ImageUrl:   art.ImageURL,
+			ArticleId:     art.ID,
+			UserName:      art.UserName,
+			SourceName:    art.SourceName,
+			Title:         art.Title,
+			Link:          art.Link,
+			Excerpt:       art.Excerpt,
+			ImageUrl:      art.ImageURL,
+			Status:        art.Status,
+			ModeratorNote: art.ModeratorNote,
 		}
 	}
 
@@ -76,6 +141,10 @@ func (s *serverAPI) SaveArticle(ctx context.Context, req *newsv1.SaveArticleRequ
 			return nil, status.Error(codes.NotFound, "there are no offered articles")
 		case errors.Is(err, services.ErrInvalidUrl):
 			return nil, status.Error(codes.Unknown, "url is invalid")
+		case errors.Is(err, services.ErrForbiddenUrl):
+			return nil, status.Error(codes.PermissionDenied, "url is forbidden")
+		case errors.Is(err, context.DeadlineExceeded):
+			return nil, status.Error(codes.DeadlineExceeded, "deadline exceeded")
 		default:
 			return nil, status.Error(codes.Internal, "internal error")
 		}
@@ -85,13 +154,15 @@ func (s *serverAPI) SaveArticle(ctx context.Context, req *newsv1.SaveArticleRequ
 
 	for i, art := range articles {
 		grpcArticles[i] = &newsv1.Article{
-			ArticleId:  art.ID,
-			UserName:   art.UserName,
-			SourceName: art.SourceName,
-			Title:      art.Title,
-			Link:       art.Link,
-			Excerpt:    art.Excerpt,
-			ImageUrl:   art.ImageURL,
+			ArticleId:     art.ID,
+			UserName:      art.UserName,
+			SourceName:    art.SourceName,
+			Title:         art.Title,
+			Link:          art.Link,
+			Excerpt:       art.Excerpt,
+			ImageUrl:      art.ImageURL,
+			Status:        art.Status,
+			ModeratorNote: art.ModeratorNote,
 		}
 	}
 
@@ -114,6 +185,10 @@ func (s *serverAPI) UpdateArticle(ctx context.Context, req *newsv1.UpdateArticle
 			return nil, status.Error(codes.Unavailable, "there are no changed articles")
 		case errors.Is(err, services.ErrInvalidUrl):
 			return nil, status.Error(codes.Unknown, "url is invalid")
+		case errors.Is(err, services.ErrForbiddenUrl):
+			return nil, status.Error(codes.PermissionDenied, "url is forbidden")
+		case errors.Is(err, context.DeadlineExceeded):
+			return nil, status.Error(codes.DeadlineExceeded, "deadline exceeded")
 		default:
 			return nil, status.Error(codes.Internal, "internal error")
 		}
@@ -123,13 +198,15 @@ func (s *serverAPI) UpdateArticle(ctx context.Context, req *newsv1.UpdateArticle
 
 	for i, art := range articles {
 		grpcArticles[i] = &newsv1.Article{
-			ArticleId:  art.ID,
-			UserName:   art.UserName,
-			SourceName: art.SourceName,
-			Title:      art.Title,
-			Link:       art.Link,
-			Excerpt:    art.Excerpt,
-			ImageUrl:   art.ImageURL,
+			ArticleId:     art.ID,
+			UserName:      art.UserName,
+			SourceName:    art.SourceName,
+			Title:         art.Title,
+			Link:          art.Link,
+			Excerpt:       art.Excerpt,
+			ImageUrl:      art.ImageURL,
+			Status:        art.Status,
+			ModeratorNote: art.ModeratorNote,
 		}
 	}
 
@@ -155,13 +232,15 @@ func (s *serverAPI) DeleteArticle(ctx context.Context, req *newsv1.DeleteArticle
 
 	for i, art := range articles {
 		grpcArticles[i] = &newsv1.Article{
-			ArticleId:  art.ID,
-			UserName:   art.UserName,
-			SourceName: art.SourceName,
-			Title:      art.Title,
-			Link:       art.Link,
-			Excerpt:    art.Excerpt,
-			ImageUrl:   art.ImageURL,
+			ArticleId:     art.ID,
+			UserName:      art.UserName,
+			SourceName:    art.SourceName,
+			Title:         art.Title,
+			Link:          art.Link,
+			Excerpt:       art.Excerpt,
+			ImageUrl:      art.ImageURL,
+			Status:        art.Status,
+			ModeratorNote: art.ModeratorNote,
 		}
 	}
 
@@ -171,7 +250,7 @@ func (s *serverAPI) DeleteArticle(ctx context.Context, req *newsv1.DeleteArticle
 }
 
 func (s *serverAPI) GetNewestArticle(ctx context.Context, req *newsv1.GetNewestArticleRequest) (*newsv1.GetNewestArticleResponse, error) {
-	art, err := s.newsService.SelectAndSendArticle(ctx)
+	art, err := s.newsService.NewestPostedArticle(ctx)
 	if err != nil {
 		if errors.Is(err, services.ErrNoNewArticle) {
 			return nil, status.Error(codes.NotFound, "there is no new article")
@@ -225,11 +304,14 @@ func (s *serverAPI) GetArticles(ctx context.Context, req *newsv1.GetArticlesRequ
 }
 
 func (s *serverAPI) GetArticlesByPage(ctx context.Context, req *newsv1.GetArticlesByPageRequest) (*newsv1.GetArticlesByPageResponse, error) {
-	articles, err := s.newsService.SelectPostedArticlesWithLimit(ctx, req.Page)
+	articles, next, err := s.newsService.SelectPostedArticlesAfter(ctx, req.GetCursor())
 	if err != nil {
-		if errors.Is(err, services.ErrNoPublishedArticles) {
+		switch {
+		case errors.Is(err, services.ErrNoPublishedArticles):
 			return nil, status.Error(codes.NotFound, "there are no published articles")
-		} else {
+		case errors.Is(err, services.ErrInvalidCursor):
+			return nil, status.Error(codes.InvalidArgument, "invalid cursor")
+		default:
 			return nil, status.Error(codes.Internal, "internal error")
 		}
 	}
@@ -250,6 +332,616 @@ func (s *serverAPI) GetArticlesByPage(ctx context.Context, req *newsv1.GetArticl
 	}
 
 	return &newsv1.GetArticlesByPageResponse{
-		Articles: grpcArticles,
+		Articles:   grpcArticles,
+		NextCursor: next,
+	}, nil
+}
+
+func (s *serverAPI) ListSourceHealth(ctx context.Context, req *newsv1.ListSourceHealthRequest) (*newsv1.ListSourceHealthResponse, error) {
+	sources, err := s.sourceService.SourcesHealth(ctx)
+	if err != nil {
+		if errors.Is(err, services.ErrNoSources) {
+			return nil, status.Error(codes.NotFound, "there are no sources")
+		} else {
+			return nil, status.Error(codes.Internal, "internal error")
+		}
+	}
+
+	grpcSources := make([]*newsv1.SourceHealth, len(sources))
+
+	for i, src := range sources {
+		grpcSources[i] = &newsv1.SourceHealth{
+			SourceId:      src.ID,
+			Name:          src.Name,
+			FeedUrl:       src.FeedURL,
+			Enabled:       src.Enabled,
+			FailureCount:  int64(src.FailureCount),
+			LastError:     src.LastError,
+			LastErrorAt:   formatTime(src.LastErrorAt),
+			LastSuccessAt: formatTime(src.LastSuccessAt),
+			NextFetchAt:   formatTime(src.NextFetchAt),
+		}
+	}
+
+	return &newsv1.ListSourceHealthResponse{
+		Sources: grpcSources,
+	}, nil
+}
+
+func (s *serverAPI) ListSources(ctx context.Context, req *newsv1.ListSourcesRequest) (*newsv1.ListSourcesResponse, error) {
+	sources, err := s.sourceService.SourcesHealth(ctx)
+	if err != nil {
+		if errors.Is(err, services.ErrNoSources) {
+			return nil, status.Error(codes.NotFound, "there are no sources")
+		} else {
+			return nil, status.Error(codes.Internal, "internal error")
+		}
+	}
+
+	grpcSources := make([]*newsv1.Source, len(sources))
+
+	for i, src := range sources {
+		grpcSources[i] = grpcSource(&src)
+	}
+
+	return &newsv1.ListSourcesResponse{
+		Sources: grpcSources,
+	}, nil
+}
+
+func (s *serverAPI) AddSource(ctx context.Context, req *newsv1.AddSourceRequest) (*newsv1.AddSourceResponse, error) {
+	src, err := s.sourceService.AddSource(ctx, req.GetName(), req.GetFeedUrl())
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrInvalidSource):
+			return nil, status.Error(codes.InvalidArgument, "invalid source")
+		case errors.Is(err, services.ErrSourceExists):
+			return nil, status.Error(codes.AlreadyExists, "source already exists")
+		default:
+			return nil, status.Error(codes.Internal, "internal error")
+		}
+	}
+
+	return &newsv1.AddSourceResponse{Source: grpcSource(src)}, nil
+}
+
+func (s *serverAPI) UpdateSource(ctx context.Context, req *newsv1.UpdateSourceRequest) (*newsv1.UpdateSourceResponse, error) {
+	src, err := s.sourceService.UpdateSource(ctx, req.GetSourceId(), req.GetName(), req.GetFeedUrl())
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrInvalidSource):
+			return nil, status.Error(codes.InvalidArgument, "invalid source")
+		case errors.Is(err, services.ErrSourceExists):
+			return nil, status.Error(codes.AlreadyExists, "source already exists")
+		case errors.Is(err, services.ErrSourceNotFound):
+			return nil, status.Error(codes.NotFound, "source not found")
+		default:
+			return nil, status.Error(codes.Internal, "internal error")
+		}
+	}
+
+	return &newsv1.UpdateSourceResponse{Source: grpcSource(src)}, nil
+}
+
+func (s *serverAPI) DeleteSource(ctx context.Context, req *newsv1.DeleteSourceRequest) (*newsv1.DeleteSourceResponse, error) {
+	if err := s.sourceService.DeleteSource(ctx, req.GetSourceId()); err != nil {
+		if errors.Is(err, services.ErrSourceNotFound) {
+			return nil, status.Error(codes.NotFound, "source not found")
+		} else {
+			return nil, status.Error(codes.Internal, "internal error")
+		}
+	}
+
+	return &newsv1.DeleteSourceResponse{}, nil
+}
+
+func (s *serverAPI) SetSourceEnabled(ctx context.Context, req *newsv1.SetSourceEnabledRequest) (*newsv1.SetSourceEnabledResponse, error) {
+	src, err := s.sourceService.SetSourceEnabled(ctx, req.GetSourceId(), req.GetEnabled())
+	if err != nil {
+		if errors.Is(err, services.ErrSourceNotFound) {
+			return nil, status.Error(codes.NotFound, "source not found")
+		} else {
+			return nil, status.Error(codes.Internal, "internal error")
+		}
+	}
+
+	return &newsv1.SetSourceEnabledResponse{Source: grpcSource(src)}, nil
+}
+
+func (s *serverAPI) ImportOPML(ctx context.Context, req *newsv1.ImportOPMLRequest) (*newsv1.ImportOPMLResponse, error) {
+	report, err := s.sourceService.ImportOPML(ctx, req.GetOpml())
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidOPML) {
+			return nil, status.Error(codes.InvalidArgument, "invalid opml document")
+		} else {
+			return nil, status.Error(codes.Internal, "internal error")
+		}
+	}
+
+	return &newsv1.ImportOPMLResponse{
+		Added:    grpcOPMLEntries(report.Added),
+		Skipped:  grpcOPMLEntries(report.Skipped),
+		Rejected: grpcOPMLEntries(report.Rejected),
+	}, nil
+}
+
+func (s *serverAPI) ExportOPML(ctx context.Context, req *newsv1.ExportOPMLRequest) (*newsv1.ExportOPMLResponse, error) {
+	document, err := s.sourceService.ExportOPML(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "internal error")
+	}
+
+	return &newsv1.ExportOPMLResponse{Opml: document}, nil
+}
+
+func grpcOPMLEntries(entries []models.OPMLEntry) []*newsv1.OPMLEntry {
+	grpcEntries := make([]*newsv1.OPMLEntry, len(entries))
+
+	for i, e := range entries {
+		grpcEntries[i] = &newsv1.OPMLEntry{
+			Title:   e.Title,
+			FeedUrl: e.FeedURL,
+			Reason:  e.Reason,
+		}
+	}
+
+	return grpcEntries
+}
+
+func grpcSource(src *models.Source) *newsv1.Source {
+	return &newsv1.Source{
+		SourceId: src.ID,
+		Name:     src.Name,
+		FeedUrl:  src.FeedURL,
+		Enabled:  src.Enabled,
+	}
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+
+	return t.Format(time.DateTime)
+}
+
+func (s *serverAPI) ReloadConfig(ctx context.Context, req *newsv1.ReloadConfigRequest) (*newsv1.ReloadConfigResponse, error) {
+	report, err := s.configService.ReloadConfig(ctx)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrInvalidConfig):
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		default:
+			return nil, status.Error(codes.Internal, "internal error")
+		}
+	}
+
+	return &newsv1.ReloadConfigResponse{
+		Rules:         int64(report.Rules),
+		FetchInterval: report.FetchInterval.String(),
+		ReloadedAt:    formatTime(report.ReloadedAt),
+	}, nil
+}
+
+func (s *serverAPI) ExplainFilter(ctx context.Context, req *newsv1.ExplainFilterRequest) (*newsv1.ExplainFilterResponse, error) {
+	explanation, err := s.newsService.ExplainFilter(ctx, req.GetLink(), req.GetTitle(), req.GetCategories(), req.GetSourceName())
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrNothingToExplain):
+			return nil, status.Error(codes.InvalidArgument, "link or title is required")
+		case errors.Is(err, services.ErrInvalidUrl):
+			return nil, status.Error(codes.Unknown, "url is invalid")
+		case errors.Is(err, services.ErrForbiddenUrl):
+			return nil, status.Error(codes.PermissionDenied, "url is forbidden")
+		case errors.Is(err, context.DeadlineExceeded):
+			return nil, status.Error(codes.DeadlineExceeded, "deadline exceeded")
+		default:
+			return nil, status.Error(codes.Internal, "internal error")
+		}
+	}
+
+	return &newsv1.ExplainFilterResponse{
+		Accepted:     explanation.Accepted,
+		Rule:         explanation.Rule,
+		MatchedRules: explanation.MatchedRules,
+		Article: &newsv1.Article{
+			SourceName: explanation.Item.SourceName,
+			Title:      explanation.Item.Title,
+			Link:       explanation.Item.Link,
+			Excerpt:    explanation.Item.Excerpt,
+			ImageUrl:   explanation.Item.ImageURL,
+		},
+		Categories: explanation.Item.Categories,
+	}, nil
+}
+
+func (s *serverAPI) GetArticle(ctx context.Context, req *newsv1.GetArticleRequest) (*newsv1.GetArticleResponse, error) {
+	art, err := s.newsService.GetArticle(ctx, req.GetArticleId())
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrArticleNotFound):
+			return nil, status.Error(codes.NotFound, "article not found")
+		case errors.Is(err, context.DeadlineExceeded):
+			return nil, status.Error(codes.DeadlineExceeded, "deadline exceeded")
+		default:
+			return nil, status.Error(codes.Internal, "internal error")
+		}
+	}
+
+	return &newsv1.GetArticleResponse{
+		Article: &newsv1.Article{
+			ArticleId:  art.Article.ID,
+			UserName:   art.Article.UserName,
+			SourceName: art.Article.SourceName,
+			Title:      art.Article.Title,
+			Link:       art.Article.Link,
+			Excerpt:    art.Article.Excerpt,
+			ImageUrl:   art.Article.ImageURL,
+			PostedAt:   art.Article.PostedAt.Format(time.DateTime),
+		},
+		Content: &newsv1.ArticleContent{
+			Html:           art.Content.HTML,
+			Text:           art.Content.Text,
+			WordCount:      int64(art.Content.WordCount),
+			ReadingMinutes: int64(art.Content.ReadingMinutes),
+			Byline:         art.Content.Byline,
+			Language:       art.Content.Language,
+		},
 	}, nil
 }
+
+func (s *serverAPI) SearchArticles(ctx context.Context, req *newsv1.SearchArticlesRequest) (*newsv1.SearchArticlesResponse, error) {
+	results, next, err := s.newsService.SearchArticles(ctx, req.GetQuery(), req.GetCursor())
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrEmptyQuery):
+			return nil, status.Error(codes.InvalidArgument, "query is required")
+		case errors.Is(err, services.ErrInvalidCursor):
+			return nil, status.Error(codes.InvalidArgument, "invalid cursor")
+		case errors.Is(err, services.ErrNothingFound):
+			return nil, status.Error(codes.NotFound, "nothing found")
+		case errors.Is(err, context.DeadlineExceeded):
+			return nil, status.Error(codes.DeadlineExceeded, "deadline exceeded")
+		default:
+			return nil, status.Error(codes.Internal, "internal error")
+		}
+	}
+
+	grpcResults := make([]*newsv1.SearchResult, len(results))
+
+	for i, res := range results {
+		grpcResults[i] = &newsv1.SearchResult{
+			Article: &newsv1.Article{
+				ArticleId:  res.Article.ID,
+				UserName:   res.Article.UserName,
+				SourceName: res.Article.SourceName,
+				Title:      res.Article.Title,
+				Link:       res.Article.Link,
+				Excerpt:    res.Article.Excerpt,
+				ImageUrl:   res.Article.ImageURL,
+				PostedAt:   res.Article.PostedAt.Format(time.DateTime),
+			},
+			Rank:    res.Rank,
+			Snippet: res.Snippet,
+		}
+	}
+
+	return &newsv1.SearchArticlesResponse{
+		Results:    grpcResults,
+		NextCursor: next,
+	}, nil
+}
+
+func (s *serverAPI) WatchPublished(req *newsv1.WatchPublishedRequest, stream newsv1.News_WatchPublishedServer) error {
+	err := s.pubService.Watch(stream.Context(), req.GetLastArticleId(), func(art models.Article) error {
+		return stream.Send(&newsv1.Article{
+			ArticleId:  art.ID,
+			UserName:   art.UserName,
+			SourceName: art.SourceName,
+			Title:      art.Title,
+			Link:       art.Link,
+			Excerpt:    art.Excerpt,
+			ImageUrl:   art.ImageURL,
+			PostedAt:   art.PostedAt.Format(time.DateTime),
+		})
+	})
+	if err == nil {
+		return nil
+	}
+
+	switch {
+	case errors.Is(err, services.ErrArticleNotFound):
+		return status.Error(codes.NotFound, "article not found")
+	case errors.Is(err, services.ErrWatcherTooSlow):
+		return status.Error(codes.ResourceExhausted, "watcher is too slow")
+	case errors.Is(err, services.ErrPublisherStopped):
+		return status.Error(codes.Unavailable, "publisher stopped")
+	case errors.Is(err, context.Canceled):
+		return status.Error(codes.Canceled, "canceled")
+	case errors.Is(err, context.DeadlineExceeded):
+		return status.Error(codes.DeadlineExceeded, "deadline exceeded")
+	default:
+		return status.Error(codes.Internal, "internal error")
+	}
+}
+
+func (s *serverAPI) ListWebhooks(ctx context.Context, req *newsv1.ListWebhooksRequest) (*newsv1.ListWebhooksResponse, error) {
+	hooks, err := s.hookService.ListWebhooks(ctx)
+	if err != nil {
+		if errors.Is(err, services.ErrNoWebhooks) {
+			return nil, status.Error(codes.NotFound, "there are no webhooks")
+		} else {
+			return nil, status.Error(codes.Internal, "internal error")
+		}
+	}
+
+	grpcHooks := make([]*newsv1.Webhook, len(hooks))
+
+	for i, hook := range hooks {
+		grpcHooks[i] = grpcWebhook(&hook)
+	}
+
+	return &newsv1.ListWebhooksResponse{
+		Webhooks: grpcHooks,
+	}, nil
+}
+
+func (s *serverAPI) AddWebhook(ctx context.Context, req *newsv1.AddWebhookRequest) (*newsv1.AddWebhookResponse, error) {
+	hook, err := s.hookService.AddWebhook(ctx, req.GetUrl(), req.GetSecret(), req.GetEvents())
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidWebhook) {
+			return nil, status.Error(codes.InvalidArgument, "invalid webhook")
+		} else {
+			return nil, status.Error(codes.Internal, "internal error")
+		}
+	}
+
+	grpcHook := grpcWebhook(hook)
+	grpcHook.Secret = hook.Secret
+
+	return &newsv1.AddWebhookResponse{Webhook: grpcHook}, nil
+}
+
+func (s *serverAPI) DeleteWebhook(ctx context.Context, req *newsv1.DeleteWebhookRequest) (*newsv1.DeleteWebhookResponse, error) {
+	if err := s.hookService.DeleteWebhook(ctx, req.GetWebhookId()); err != nil {
+		if errors.Is(err, services.ErrWebhookNotFound) {
+			return nil, status.Error(codes.NotFound, "webhook not found")
+		} else {
+			return nil, status.Error(codes.Internal, "internal error")
+		}
+	}
+
+	return &newsv1.DeleteWebhookResponse{}, nil
+}
+
+func (s *serverAPI) ListWebhookDeliveries(ctx context.Context, req *newsv1.ListWebhookDeliveriesRequest) (*newsv1.ListWebhookDeliveriesResponse, error) {
+	deliveries, err := s.hookService.Deliveries(ctx, req.GetWebhookId(), req.GetStatus(), int(req.GetLimit()))
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidStatus) {
+			return nil, status.Error(codes.InvalidArgument, "invalid status")
+		} else {
+			return nil, status.Error(codes.Internal, "internal error")
+		}
+	}
+
+	grpcDeliveries := make([]*newsv1.WebhookDelivery, len(deliveries))
+
+	for i, d := range deliveries {
+		grpcDeliveries[i] = &newsv1.WebhookDelivery{
+			DeliveryId:    d.ID,
+			WebhookId:     d.WebhookID,
+			Event:         d.Event,
+			Payload:       d.Payload,
+			Status:        d.Status,
+			Attempts:      int64(d.Attempts),
+			ResponseCode:  int64(d.ResponseCode),
+			LastError:     d.LastError,
+			CreatedAt:     formatTime(d.CreatedAt),
+			NextAttemptAt: formatTime(d.NextAttemptAt),
+			DeliveredAt:   formatTime(d.DeliveredAt),
+		}
+	}
+
+	return &newsv1.ListWebhookDeliveriesResponse{
+		Deliveries: grpcDeliveries,
+	}, nil
+}
+
+func grpcWebhook(hook *models.Webhook) *newsv1.Webhook {
+	return &newsv1.Webhook{
+		WebhookId: hook.ID,
+		Url:       hook.URL,
+		Events:    hook.Events,
+		Enabled:   hook.Enabled,
+		CreatedAt: formatTime(hook.CreatedAt),
+	}
+}
+
+func (s *serverAPI) AddFollower(ctx context.Context, req *newsv1.AddFollowerRequest) (*newsv1.AddFollowerResponse, error) {
+	follower := req.GetFollower()
+
+	if err := s.followService.AddFollower(ctx, follower.GetActorId(), follower.GetInbox(), follower.GetSharedInbox()); err != nil {
+		if errors.Is(err, services.ErrInvalidFollower) {
+			return nil, status.Error(codes.InvalidArgument, "invalid follower")
+		} else {
+			return nil, status.Error(codes.Internal, "internal error")
+		}
+	}
+
+	return &newsv1.AddFollowerResponse{}, nil
+}
+
+func (s *serverAPI) RemoveFollower(ctx context.Context, req *newsv1.RemoveFollowerRequest) (*newsv1.RemoveFollowerResponse, error) {
+	if err := s.followService.RemoveFollower(ctx, req.GetActorId()); err != nil {
+		if errors.Is(err, services.ErrFollowerNotFound) {
+			return nil, status.Error(codes.NotFound, "follower not found")
+		} else {
+			return nil, status.Error(codes.Internal, "internal error")
+		}
+	}
+
+	return &newsv1.RemoveFollowerResponse{}, nil
+}
+
+func (s *serverAPI) ListFollowers(ctx context.Context, req *newsv1.ListFollowersRequest) (*newsv1.ListFollowersResponse, error) {
+	followers, err := s.followService.ListFollowers(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "internal error")
+	}
+
+	grpcFollowers := make([]*newsv1.Follower, len(followers))
+
+	for i, f := range followers {
+		grpcFollowers[i] = &newsv1.Follower{
+			ActorId:     f.ActorID,
+			Inbox:       f.Inbox,
+			SharedInbox: f.SharedInbox,
+			CreatedAt:   formatTime(f.CreatedAt),
+		}
+	}
+
+	return &newsv1.ListFollowersResponse{
+		Followers: grpcFollowers,
+	}, nil
+}
+
+func (s *serverAPI) GetDigestSubscription(ctx context.Context, req *newsv1.GetDigestSubscriptionRequest) (*newsv1.GetDigestSubscriptionResponse, error) {
+	sub, err := s.digestService.Subscription(ctx, req.GetUserId())
+	if err != nil {
+		if errors.Is(err, services.ErrSubscriptionNotFound) {
+			return nil, status.Error(codes.NotFound, "subscription not found")
+		} else {
+			return nil, status.Error(codes.Internal, "internal error")
+		}
+	}
+
+	return &newsv1.GetDigestSubscriptionResponse{
+		Subscription: grpcDigestSubscription(sub),
+	}, nil
+}
+
+func (s *serverAPI) SubscribeDigest(ctx context.Context, req *newsv1.SubscribeDigestRequest) (*newsv1.SubscribeDigestResponse, error) {
+	sub, err := s.digestService.Subscribe(ctx, req.GetUserId(), req.GetFrequency())
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidFrequency) {
+			return nil, status.Error(codes.InvalidArgument, "invalid frequency")
+		} else {
+			return nil, status.Error(codes.Internal, "internal error")
+		}
+	}
+
+	return &newsv1.SubscribeDigestResponse{
+		Subscription: grpcDigestSubscription(sub),
+	}, nil
+}
+
+func (s *serverAPI) UnsubscribeDigest(ctx context.Context, req *newsv1.UnsubscribeDigestRequest) (*newsv1.UnsubscribeDigestResponse, error) {
+	if err := s.digestService.Unsubscribe(ctx, req.GetUserId()); err != nil {
+		if errors.Is(err, services.ErrSubscriptionNotFound) {
+			return nil, status.Error(codes.NotFound, "subscription not found")
+		} else {
+			return nil, status.Error(codes.Internal, "internal error")
+		}
+	}
+
+	return &newsv1.UnsubscribeDigestResponse{}, nil
+}
+
+func (s *serverAPI) UnsubscribeDigestByToken(ctx context.Context, req *newsv1.UnsubscribeDigestByTokenRequest) (*newsv1.UnsubscribeDigestByTokenResponse, error) {
+	if err := s.digestService.UnsubscribeByToken(ctx, req.GetToken()); err != nil {
+		switch {
+		case errors.Is(err, services.ErrInvalidToken):
+			return nil, status.Error(codes.InvalidArgument, "invalid token")
+		case errors.Is(err, services.ErrSubscriptionNotFound):
+			return nil, status.Error(codes.NotFound, "subscription not found")
+		default:
+			return nil, status.Error(codes.Internal, "internal error")
+		}
+	}
+
+	return &newsv1.UnsubscribeDigestByTokenResponse{}, nil
+}
+
+func (s *serverAPI) ListPendingSubmissions(ctx context.Context, req *newsv1.ListPendingSubmissionsRequest) (*newsv1.ListPendingSubmissionsResponse, error) {
+	articles, err := s.modService.ListPendingSubmissions(ctx, int(req.GetLimit()))
+	if err != nil {
+		return nil, status.Error(codes.Internal, "internal error")
+	}
+
+	grpcSubmissions := make([]*newsv1.Submission, len(articles))
+
+	for i := range articles {
+		grpcSubmissions[i] = grpcSubmission(&articles[i])
+	}
+
+	return &newsv1.ListPendingSubmissionsResponse{
+		Submissions: grpcSubmissions,
+	}, nil
+}
+
+func (s *serverAPI) ApproveSubmission(ctx context.Context, req *newsv1.ApproveSubmissionRequest) (*newsv1.ApproveSubmissionResponse, error) {
+	article, err := s.modService.ApproveSubmission(ctx, req.GetArticleId(), req.GetModerator(), req.GetNote())
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrSubmissionNotFound):
+			return nil, status.Error(codes.NotFound, "submission not found")
+		case errors.Is(err, services.ErrNotPending):
+			return nil, status.Error(codes.FailedPrecondition, "submission isn't pending")
+		default:
+			return nil, status.Error(codes.Internal, "internal error")
+		}
+	}
+
+	return &newsv1.ApproveSubmissionResponse{
+		Submission: grpcSubmission(article),
+	}, nil
+}
+
+func (s *serverAPI) RejectSubmission(ctx context.Context, req *newsv1.RejectSubmissionRequest) (*newsv1.RejectSubmissionResponse, error) {
+	article, err := s.modService.RejectSubmission(ctx, req.GetArticleId(), req.GetModerator(), req.GetReason())
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrReasonRequired):
+			return nil, status.Error(codes.InvalidArgument, "reason is required")
+		case errors.Is(err, services.ErrSubmissionNotFound):
+			return nil, status.Error(codes.NotFound, "submission not found")
+		case errors.Is(err, services.ErrNotPending):
+			return nil, status.Error(codes.FailedPrecondition, "submission isn't pending")
+		default:
+			return nil, status.Error(codes.Internal, "internal error")
+		}
+	}
+
+	return &newsv1.RejectSubmissionResponse{
+		Submission: grpcSubmission(article),
+	}, nil
+}
+
+func grpcSubmission(art *models.Article) *newsv1.Submission {
+	return &newsv1.Submission{
+		Article: &newsv1.Article{
+			ArticleId:     art.ID,
+			UserName:      art.UserName,
+			SourceName:    art.SourceName,
+			Title:         art.Title,
+			Link:          art.Link,
+			Excerpt:       art.Excerpt,
+			ImageUrl:      art.ImageURL,
+			Status:        art.Status,
+			ModeratorNote: art.ModeratorNote,
+		},
+		UserId:      art.UserID,
+		CreatedAt:   formatTime(art.CreatedAt),
+		ModeratedBy: art.ModeratedBy,
+		ModeratedAt: formatTime(art.ModeratedAt),
+	}
+}
+
+func grpcDigestSubscription(sub *models.DigestSubscription) *newsv1.DigestSubscription {
+	return &newsv1.DigestSubscription{
+		Frequency:  sub.Frequency,
+		CreatedAt:  formatTime(sub.CreatedAt),
+		LastSentAt: formatTime(sub.LastSentAt),
+		NextSendAt: formatTime(sub.NextSendAt),
+	}
+}
@@ -0,0 +1,86 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"newsWebApp/app/newsService/internal/models"
+	"newsWebApp/app/newsService/internal/services"
+
+	newsv1 "newsWebApp/protos/gen/go/news"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// fakePublisher fails Watch with err, ends it cleanly when done or waits for ctx.
+type fakePublisher struct {
+	err  error
+	done bool
+}
+
+func (p fakePublisher) Watch(ctx context.Context, lastID int64, send func(models.Article) error) error {
+	if p.err != nil {
+		return p.err
+	}
+
+	if p.done {
+		return nil
+	}
+
+	<-ctx.Done()
+
+	return ctx.Err()
+}
+
+type fakeWatchStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s fakeWatchStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s fakeWatchStream) Send(*newsv1.Article) error {
+	return nil
+}
+
+func TestWatchPublishedCodes(t *testing.T) {
+	tests := []struct {
+		name    string
+		err     error
+		done    bool
+		timeout time.Duration
+		want    codes.Code
+	}{
+		{name: "deadline", timeout: 10 * time.Millisecond, want: codes.DeadlineExceeded},
+		{name: "not found", err: services.ErrArticleNotFound, want: codes.NotFound},
+		{name: "too slow", err: services.ErrWatcherTooSlow, want: codes.ResourceExhausted},
+		{name: "stopped", err: services.ErrPublisherStopped, want: codes.Unavailable},
+		{name: "canceled", err: context.Canceled, want: codes.Canceled},
+		{name: "other", err: errors.New("db is down"), want: codes.Internal},
+		{name: "ended", done: true, want: codes.OK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.timeout > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, tt.timeout)
+				defer cancel()
+			}
+
+			s := &serverAPI{pubService: fakePublisher{err: tt.err, done: tt.done}}
+
+			err := s.WatchPublished(&newsv1.WatchPublishedRequest{}, fakeWatchStream{ctx: ctx})
+			if got := status.Code(err); got != tt.want {
+				t.Errorf("WatchPublished() code = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -18,9 +18,56 @@ type NewsService interface {
 	SaveArticleFromUser(ctx context.Context, userID int64, link string) ([]models.Article, error)
 	UpdateArticleByID(ctx context.Context, userID int64, artID int64, link string) ([]models.Article, error)
 	DeleteArticleByID(ctx context.Context, userID int64, artID int64) ([]models.Article, error)
-	SelectAndSendArticle(ctx context.Context) (*models.Article, error)
+	NewestPostedArticle(ctx context.Context) (*models.Article, error)
 	SelectPostedArticles(ctx context.Context) ([]models.Article, error)
-	SelectPostedArticlesWithLimit(ctx context.Context, page int64) ([]models.Article, error)
+	SelectPostedArticlesAfter(ctx context.Context, token string) ([]models.Article, string, error)
+	ExplainFilter(ctx context.Context, link string, title string, categories []string, source string) (*models.FilterExplanation, error)
+	GetArticle(ctx context.Context, artID int64) (*models.ReaderArticle, error)
+	SearchArticles(ctx context.Context, query string, token string) ([]models.SearchResult, string, error)
+}
+
+type SourceService interface {
+	SourcesHealth(ctx context.Context) ([]models.Source, error)
+	AddSource(ctx context.Context, name string, feedURL string) (*models.Source, error)
+	UpdateSource(ctx context.Context, id int64, name string, feedURL string) (*models.Source, error)
+	DeleteSource(ctx context.Context, id int64) error
+	SetSourceEnabled(ctx context.Context, id int64, enabled bool) (*models.Source, error)
+	ImportOPML(ctx context.Context, document []byte) (*models.OPMLReport, error)
+	ExportOPML(ctx context.Context) ([]byte, error)
+}
+
+type ConfigService interface {
+	ReloadConfig(ctx context.Context) (*models.ReloadReport, error)
+}
+
+type PublishService interface {
+	Watch(ctx context.Context, lastID int64, send func(models.Article) error) error
+}
+
+type WebhookService interface {
+	ListWebhooks(ctx context.Context) ([]models.Webhook, error)
+	AddWebhook(ctx context.Context, endpoint string, secret string, events []string) (*models.Webhook, error)
+	DeleteWebhook(ctx context.Context, id int64) error
+	Deliveries(ctx context.Context, webhookID int64, status string, limit int) ([]models.WebhookDelivery, error)
+}
+
+type FollowerService interface {
+	AddFollower(ctx context.Context, actorID string, inbox string, sharedInbox string) error
+	RemoveFollower(ctx context.Context, actorID string) error
+	ListFollowers(ctx context.Context) ([]models.Follower, error)
+}
+
+type DigestService interface {
+	Subscription(ctx context.Context, userID int64) (*models.DigestSubscription, error)
+	Subscribe(ctx context.Context, userID int64, frequency string) (*models.DigestSubscription, error)
+	Unsubscribe(ctx context.Context, userID int64) error
+	UnsubscribeByToken(ctx context.Context, token string) error
+}
+
+type ModerationService interface {
+	ListPendingSubmissions(ctx context.Context, limit int) ([]models.Article, error)
+	ApproveSubmission(ctx context.Context, artID int64, moderator string, note string) (*models.Article, error)
+	RejectSubmission(ctx context.Context, artID int64, moderator string, reason string) (*models.Article, error)
 }
 
 type Server struct {
@@ -29,10 +76,10 @@ type Server struct {
 	gRPCServer *grpc.Server
 }
 
-func New(port int, log *slog.Logger, newsService NewsService) *Server {
+func New(port int, log *slog.Logger, newsService NewsService, sourceService SourceService, configService ConfigService, publishService PublishService, webhookService WebhookService, followerService FollowerService, digestService DigestService, moderationService ModerationService) *Server {
 	grpcSrv := grpc.NewServer()
 
-	handler.Register(grpcSrv, newsService)
+	handler.Register(grpcSrv, newsService, sourceService, configService, publishService, webhookService, followerService, digestService, moderationService)
 
 	return &Server{
 		port:       port,
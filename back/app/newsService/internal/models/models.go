@@ -3,33 +3,237 @@ package models
 import "time"
 
 type Source struct {
-	ID      int64
-	Name    string
+	ID           int64
+	Name         string
+	FeedURL      string
+	ETag         string
+	LastModified string
+	ContentHash  string
+
+	Enabled       bool
+	FailureCount  int
+	LastError     string
+	LastErrorAt   time.Time
+	LastSuccessAt time.Time
+	NextFetchAt   time.Time
+}
+
+// OPMLEntry is a feed outline from OPML document with the reason it was skipped or rejected.
+type OPMLEntry struct {
+	Title   string
 	FeedURL string
+	Reason  string
+}
+
+type OPMLReport struct {
+	Added    []OPMLEntry
+	Skipped  []OPMLEntry
+	Rejected []OPMLEntry
 }
 
 // In RSS ...
 type Item struct {
-	Title      string
-	Categories []string
-	Link       string
-	Date       time.Time
-	Excerpt    string
-	ImageURL   string
-	SourceName string
+	Title        string
+	Categories   []string
+	Link         string
+	OriginalLink string
+	Date         time.Time
+	Excerpt      string
+	Text         string
+	HTML         string
+	Byline       string
+	Language     string
+	ImageURL     string
+	SourceName   string
 }
 
 // In Database ...
 type Article struct {
+	ID           int64
+	UserID       int64
+	UserName     string
+	SourceName   string
+	Title        string
+	Link         string
+	OriginalLink string
+	Excerpt      string
+	ImageURL     string
+	Fingerprint  uint64
+	DuplicateOf  int64
+	PublishedAt  time.Time
+	CreatedAt    time.Time
+	PostedAt     time.Time
+
+	Status        string
+	ModeratorNote string
+	ModeratedBy   string
+	ModeratedAt   time.Time
+}
+
+// Statuses of articles. Articles from users are pending until a moderator approves
+// or rejects them, only approved ones are posted. Articles from sources are approved.
+const (
+	StatusPending  = "pending"
+	StatusApproved = "approved"
+	StatusRejected = "rejected"
+)
+
+// ArticleContent is a readable body of the article extracted from its page.
+type ArticleContent struct {
+	ArticleID      int64
+	HTML           string
+	Text           string
+	WordCount      int
+	ReadingMinutes int
+	Byline         string
+	Language       string
+}
+
+// ReaderArticle is an article with its content for reader view.
+type ReaderArticle struct {
+	Article Article
+	Content ArticleContent
+}
+
+// PageCursor is a position in the feed of posted articles ordered by posting time and id.
+type PageCursor struct {
+	PostedAt time.Time
+	ID       int64
+}
+
+// SearchResult is a posted article matching the search query.
+type SearchResult struct {
+	Article Article
+	Rank    float32
+	Snippet string
+}
+
+// SearchCursor is a position in search results ordered by rank and id.
+type SearchCursor struct {
+	Rank float32
+	ID   int64
+}
+
+// ArticleFingerprint is a recent article compared with new ones to find near duplicates.
+type ArticleFingerprint struct {
 	ID          int64
 	UserID      int64
-	UserName    string
 	SourceName  string
-	Title       string
-	Link        string
-	Excerpt     string
-	ImageURL    string
-	PublishedAt time.Time
+	Fingerprint uint64
+	DuplicateOf int64
+	Posted      bool
+}
+
+// FilterRule accepts or rejects items matching expression. Rule with sources
+// is applied to items of these sources only and overrides global rules.
+type FilterRule struct {
+	Name    string
+	Action  string
+	Expr    string
+	Sources []string
+}
+
+// FilterDecision is a result of filtering of one item.
+type FilterDecision struct {
+	Link      string
+	Source    string
+	Title     string
+	Accepted  bool
+	Rule      string
+	DecidedAt time.Time
+}
+
+// FilterExplanation is a dry run of filter rules against an item.
+type FilterExplanation struct {
+	Item         Item
+	Accepted     bool
+	Rule         string
+	MatchedRules []string
+}
+
+// ReloadReport describes settings applied after config reload.
+type ReloadReport struct {
+	Rules         int
+	FetchInterval time.Duration
+	ReloadedAt    time.Time
+}
+
+// Events sent to webhooks.
+const (
+	EventArticlePublished = "article.published"
+	EventArticleSubmitted = "article.submitted"
+	EventSourceFailed     = "source.failed"
+)
+
+// Statuses of webhook deliveries. Delivery is dead when it has run out of attempts.
+const (
+	DeliveryPending   = "pending"
+	DeliveryDelivered = "delivered"
+	DeliveryDead      = "dead"
+)
+
+// Webhook is an endpoint subscribed to events. Requests to it are signed with Secret.
+type Webhook struct {
+	ID        int64
+	URL       string
+	Secret    string
+	Events    []string
+	Enabled   bool
+	CreatedAt time.Time
+}
+
+// WebhookDelivery is one event sent to one webhook. URL and Secret of the webhook
+// are filled for deliveries taken to be sent.
+type WebhookDelivery struct {
+	ID            int64
+	WebhookID     int64
+	URL           string
+	Secret        string
+	Event         string
+	Payload       []byte
+	Status        string
+	Attempts      int
+	ResponseCode  int
+	LastError     string
+	CreatedAt     time.Time
+	NextAttemptAt time.Time
+	DeliveredAt   time.Time
+}
+
+// SinkState is a position of the sink in the feed of posted articles. Articles after
+// Cursor aren't sent yet, NextAttemptAt holds the sink back after failures.
+type SinkState struct {
+	Sink          string
+	Cursor        PageCursor
+	FailureCount  int
+	LastError     string
+	LastErrorAt   time.Time
+	LastSentAt    time.Time
+	NextAttemptAt time.Time
+}
+
+// Follower is an ActivityPub actor following the news stream. Posts go to SharedInbox
+// of its server when there is one.
+type Follower struct {
+	ActorID     string
+	Inbox       string
+	SharedInbox string
 	CreatedAt   time.Time
-	PostedAt    time.Time
+}
+
+// Frequencies of email digests.
+const (
+	DigestDaily  = "daily"
+	DigestWeekly = "weekly"
+)
+
+// DigestSubscription is a user getting email digest of posted articles.
+type DigestSubscription struct {
+	UserID     int64
+	UserName   string
+	Email      string
+	Frequency  string
+	CreatedAt  time.Time
+	LastSentAt time.Time
+	NextSendAt time.Time
 }
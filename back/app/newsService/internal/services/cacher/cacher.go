@@ -5,11 +5,15 @@ import (
 	"crypto/sha1"
 	"errors"
 	"fmt"
+	"time"
 
 	"newsWebApp/app/newsService/internal/services"
+	"newsWebApp/app/newsService/internal/services/canonical"
 	"newsWebApp/app/newsService/internal/storage"
 )
 
+const cleanupTimeout = 2 * time.Second
+
 type Cache interface {
 	SetLink(context.Context, string) error
 	DeleteLink(context.Context, string) error
@@ -66,12 +70,16 @@ func (c *Cacher) UpdateLink(ctx context.Context, newLink, oldLink string) error
 	return nil
 }
 
+// DeleteLink is a cleanup after failed load, so it's done even when ctx is already cancelled.
 func (c *Cacher) DeleteLink(ctx context.Context, link string) error {
 	linkHash, err := c.hashl(link)
 	if err != nil {
 		return err
 	}
 
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), cleanupTimeout)
+	defer cancel()
+
 	if err := c.cache.DeleteLink(ctx, linkHash); err != nil {
 		return err
 	}
@@ -79,10 +87,11 @@ func (c *Cacher) DeleteLink(ctx context.Context, link string) error {
 	return nil
 }
 
+// hashl returns hash of normal form of the link, so copies of the same page share it.
 func (c *Cacher) hashl(link string) (string, error) {
 	hash := sha1.New()
 
-	_, err := hash.Write([]byte(link))
+	_, err := hash.Write([]byte(canonical.Key(link)))
 	if err != nil {
 		return "", err
 	}
@@ -0,0 +1,219 @@
+package canonical
+
+import (
+	"bytes"
+	"errors"
+	"net/url"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+	"golang.org/x/net/publicsuffix"
+)
+
+var ErrNotHTTP = errors.New("link is not http url")
+
+// trackingParams are query parameters which don't change the page.
+var trackingParams = map[string]struct{}{
+	"fbclid":  {},
+	"gclid":   {},
+	"dclid":   {},
+	"yclid":   {},
+	"msclkid": {},
+	"igshid":  {},
+	"mc_cid":  {},
+	"mc_eid":  {},
+	"_ga":     {},
+	"_gl":     {},
+	"_hsenc":  {},
+	"_hsmi":   {},
+	"ref_src": {},
+	"ref_url": {},
+}
+
+// trackingPrefixes are prefixes of tracking parameter families.
+var trackingPrefixes = []string{"utm_", "mtm_", "pk_", "oly_"}
+
+// mobilePrefixes are subdomains of mobile versions of sites.
+var mobilePrefixes = []string{"m.", "mobile."}
+
+// Normalize returns canonical form of the link: https scheme, lower case host
+// without mobile subdomain and default port, path without trailing slash,
+// sorted query without tracking parameters and no fragment. It's a key copies
+// of the same page share, such url may not resolve, so it's never shown.
+func Normalize(link string) (string, error) {
+	u, err := url.Parse(strings.TrimSpace(link))
+	if err != nil {
+		return "", err
+	}
+
+	u.Scheme = strings.ToLower(u.Scheme)
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return "", ErrNotHTTP
+	}
+
+	u.Scheme = "https"
+	u.User = nil
+	u.Fragment = ""
+	u.RawFragment = ""
+
+	host := strings.TrimSuffix(strings.ToLower(u.Hostname()), ".")
+	for _, prefix := range mobilePrefixes {
+		if strings.HasPrefix(host, prefix) && strings.Count(host, ".") > 1 {
+			host = strings.TrimPrefix(host, prefix)
+			break
+		}
+	}
+
+	if port := u.Port(); port != "" && port != "80" && port != "443" {
+		host = host + ":" + port
+	}
+	u.Host = host
+
+	if len(u.Path) > 1 {
+		u.Path = strings.TrimRight(u.Path, "/")
+		if u.Path == "" {
+			u.Path = "/"
+		}
+		u.RawPath = ""
+	}
+
+	if u.Path == "" {
+		u.Path = "/"
+	}
+
+	query := u.Query()
+	for key := range query {
+		if isTracking(key) {
+			query.Del(key)
+		}
+	}
+	u.RawQuery = query.Encode()
+
+	return u.String(), nil
+}
+
+// Key returns normal form of the link or the link itself when it isn't http url.
+func Key(link string) string {
+	if key, err := Normalize(link); err == nil {
+		return key
+	}
+
+	return link
+}
+
+// Clean returns the link without tracking parameters, fragment and user info.
+// Unlike Normalize, it keeps the url resolvable.
+func Clean(link string) (string, error) {
+	u, err := url.Parse(strings.TrimSpace(link))
+	if err != nil {
+		return "", err
+	}
+
+	u.Scheme = strings.ToLower(u.Scheme)
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return "", ErrNotHTTP
+	}
+
+	u.Host = strings.ToLower(u.Host)
+	u.User = nil
+	u.Fragment = ""
+	u.RawFragment = ""
+
+	query := u.Query()
+	tracked := false
+
+	for key := range query {
+		if isTracking(key) {
+			query.Del(key)
+			tracked = true
+		}
+	}
+
+	// Query is encoded again only when it's changed, so order of parameters is kept.
+	if tracked {
+		u.RawQuery = query.Encode()
+	}
+
+	return u.String(), nil
+}
+
+// SameSite reports whether both urls are on the same registrable domain,
+// e.g. blog.example.com and www.example.com.
+func SameSite(a *url.URL, b *url.URL) bool {
+	siteA, err := publicsuffix.EffectiveTLDPlusOne(strings.TrimSuffix(strings.ToLower(a.Hostname()), "."))
+	if err != nil {
+		return false
+	}
+
+	siteB, err := publicsuffix.EffectiveTLDPlusOne(strings.TrimSuffix(strings.ToLower(b.Hostname()), "."))
+	if err != nil {
+		return false
+	}
+
+	return siteA == siteB
+}
+
+// FromPage returns absolute url from <link rel="canonical"> of html page or empty string.
+func FromPage(base *url.URL, page []byte) string {
+	tokenizer := html.NewTokenizer(bytes.NewReader(page))
+
+	for {
+		switch tokenizer.Next() {
+		case html.ErrorToken:
+			return ""
+		case html.StartTagToken, html.SelfClosingTagToken:
+			token := tokenizer.Token()
+
+			switch token.DataAtom {
+			case atom.Body:
+				return ""
+			case atom.Link:
+				if href := canonicalHref(token); href != "" {
+					ref, err := url.Parse(href)
+					if err != nil {
+						return ""
+					}
+					return base.ResolveReference(ref).String()
+				}
+			}
+		}
+	}
+}
+
+func canonicalHref(token html.Token) string {
+	var rel, href string
+
+	for _, attr := range token.Attr {
+		switch attr.Key {
+		case "rel":
+			rel = attr.Val
+		case "href":
+			href = attr.Val
+		}
+	}
+
+	for _, r := range strings.Fields(rel) {
+		if strings.EqualFold(r, "canonical") {
+			return strings.TrimSpace(href)
+		}
+	}
+
+	return ""
+}
+
+func isTracking(key string) bool {
+	key = strings.ToLower(key)
+
+	if _, ok := trackingParams[key]; ok {
+		return true
+	}
+
+	for _, prefix := range trackingPrefixes {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,333 @@
+// Package digest emails daily and weekly digests of posted articles to subscribed users.
+package digest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/textproto"
+	"strings"
+	"sync"
+	"time"
+
+	"newsWebApp/app/newsService/internal/models"
+	"newsWebApp/app/newsService/internal/services"
+	"newsWebApp/app/newsService/internal/storage"
+)
+
+type DigestStorage interface {
+	SaveSubscription(ctx context.Context, sub models.DigestSubscription) (*models.DigestSubscription, error)
+	Subscription(ctx context.Context, userID int64) (*models.DigestSubscription, error)
+	DeleteSubscription(ctx context.Context, userID int64) error
+	ClaimDueDigests(ctx context.Context, limit int, lease time.Duration) ([]models.DigestSubscription, error)
+	SaveDigestSchedule(ctx context.Context, sub models.DigestSubscription) error
+}
+
+type ArticleStorage interface {
+	PostedBetween(ctx context.Context, from time.Time, to time.Time, limit int) ([]models.Article, error)
+}
+
+type Mailer interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+var weekdays = map[string]time.Weekday{
+	"sunday":    time.Sunday,
+	"monday":    time.Monday,
+	"tuesday":   time.Tuesday,
+	"wednesday": time.Wednesday,
+	"thursday":  time.Thursday,
+	"friday":    time.Friday,
+	"saturday":  time.Saturday,
+}
+
+// Digester keeps digest subscriptions and sends digests which are due. Subscriptions
+// are claimed in the database, so a digest is sent by one replica.
+type Digester struct {
+	subs     DigestStorage
+	articles ArticleStorage
+	mailer   Mailer
+
+	secret  []byte
+	siteURL string
+
+	loc        *time.Location
+	sendHour   int
+	sendMinute int
+	weekday    time.Weekday
+
+	interval    time.Duration
+	batch       int
+	maxArticles int
+	retry       time.Duration
+	timeout     time.Duration
+
+	log *slog.Logger
+}
+
+// New returns digester sending digests at sendAt ("HH:MM" in timeZone), weekly ones
+// on weekday. Due digests are checked every interval, batch at a time, and have at
+// most maxArticles articles. Failed digest is sent again after retry. Unsubscribe
+// links lead to siteURL and are signed with secret.
+func New(subs DigestStorage,
+	articles ArticleStorage,
+	mailer Mailer,
+	secret string,
+	siteURL string,
+	sendAt string,
+	weekday string,
+	timeZone string,
+	interval time.Duration,
+	batch int,
+	maxArticles int,
+	retry time.Duration,
+	timeout time.Duration,
+	log *slog.Logger,
+) (*Digester, error) {
+	const op = "services.digest.new"
+
+	loc, err := time.LoadLocation(timeZone)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	at, err := time.Parse("15:04", sendAt)
+	if err != nil {
+		return nil, fmt.Errorf("%s: send_at: %w", op, err)
+	}
+
+	day, ok := weekdays[strings.ToLower(weekday)]
+	if !ok {
+		return nil, fmt.Errorf("%s: unknown weekday %q", op, weekday)
+	}
+
+	return &Digester{
+		subs:        subs,
+		articles:    articles,
+		mailer:      mailer,
+		secret:      []byte(secret),
+		siteURL:     strings.TrimRight(siteURL, "/"),
+		loc:         loc,
+		sendHour:    at.Hour(),
+		sendMinute:  at.Minute(),
+		weekday:     day,
+		interval:    interval,
+		batch:       batch,
+		maxArticles: maxArticles,
+		retry:       retry,
+		timeout:     timeout,
+		log:         log,
+	}, nil
+}
+
+func (d *Digester) Subscribe(ctx context.Context, userID int64, frequency string) (*models.DigestSubscription, error) {
+	const op = "services.digest.subscribe"
+
+	if frequency != models.DigestDaily && frequency != models.DigestWeekly {
+		d.log.Debug("Can't subscribe to digest", "frequency", frequency)
+		return nil, services.ErrInvalidFrequency
+	}
+
+	now := time.Now()
+
+	sub, err := d.subs.SaveSubscription(ctx, models.DigestSubscription{
+		UserID:     userID,
+		Frequency:  frequency,
+		CreatedAt:  now,
+		NextSendAt: d.nextSend(now, frequency),
+	})
+	if err != nil {
+		d.log.Error("Can't subscribe to digest", "user id", userID, "err", err.Error())
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return sub, nil
+}
+
+func (d *Digester) Subscription(ctx context.Context, userID int64) (*models.DigestSubscription, error) {
+	const op = "services.digest.subscription"
+
+	sub, err := d.subs.Subscription(ctx, userID)
+	if err != nil {
+		if errors.Is(err, storage.ErrSubscriptionNotFound) {
+			return nil, services.ErrSubscriptionNotFound
+		}
+		d.log.Error("Can't get digest subscription", "user id", userID, "err", err.Error())
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return sub, nil
+}
+
+func (d *Digester) Unsubscribe(ctx context.Context, userID int64) error {
+	const op = "services.digest.unsubscribe"
+
+	if err := d.subs.DeleteSubscription(ctx, userID); err != nil {
+		if errors.Is(err, storage.ErrSubscriptionNotFound) {
+			return services.ErrSubscriptionNotFound
+		}
+		d.log.Error("Can't unsubscribe from digest", "user id", userID, "err", err.Error())
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// UnsubscribeByToken unsubscribes user of the token from unsubscribe link.
+func (d *Digester) UnsubscribeByToken(ctx context.Context, token string) error {
+	userID, err := ParseUnsubscribeToken(d.secret, token)
+	if err != nil {
+		d.log.Debug("Can't unsubscribe from digest", "err", err.Error())
+		return services.ErrInvalidToken
+	}
+
+	return d.Unsubscribe(ctx, userID)
+}
+
+// Start sends due digests till ctx is done.
+func (d *Digester) Start(ctx context.Context) error {
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+
+	for {
+		d.sendDue(ctx)
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+func (d *Digester) sendDue(ctx context.Context) {
+	// Claimed subscriptions are hidden from other replicas while digests are sent.
+	subs, err := d.subs.ClaimDueDigests(ctx, d.batch, 2*d.timeout)
+	if err != nil {
+		if ctx.Err() == nil {
+			d.log.Error("Can't get due digests", "err", err.Error())
+		}
+		return
+	}
+
+	var wg sync.WaitGroup
+
+	for _, sub := range subs {
+		wg.Add(1)
+
+		go func(sub models.DigestSubscription) {
+			defer wg.Done()
+			d.deliver(ctx, sub)
+		}(sub)
+	}
+
+	wg.Wait()
+}
+
+// deliver sends digest of articles posted since the last one, but not earlier than
+// a period ago. Nothing is sent when there are no articles. Started digest isn't
+// canceled on shutdown, so a sent one always gets its schedule saved.
+func (d *Digester) deliver(ctx context.Context, sub models.DigestSubscription) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), d.timeout)
+	defer cancel()
+
+	now := time.Now()
+
+	from := now.Add(-period(sub.Frequency))
+	if sub.LastSentAt.After(from) {
+		from = sub.LastSentAt
+	}
+
+	err := d.send(ctx, sub, from, now)
+	if ctx.Err() != nil && errors.Is(err, ctx.Err()) {
+		// Digest is sent again after its lease.
+		return
+	}
+
+	switch {
+	case err == nil:
+		sub.NextSendAt = d.nextSend(now, sub.Frequency)
+	case isPermanent(err):
+		// Address is rejected, the next digest may get through.
+		d.log.Warn("Digest is rejected", "user id", sub.UserID, "err", err.Error())
+		sub.NextSendAt = d.nextSend(now, sub.Frequency)
+	default:
+		d.log.Warn("Can't send digest", "user id", sub.UserID, "err", err.Error())
+		sub.NextSendAt = now.Add(d.retry)
+		if next := d.nextSend(now, sub.Frequency); next.Before(sub.NextSendAt) {
+			sub.NextSendAt = next
+		}
+	}
+
+	if err == nil {
+		sub.LastSentAt = now
+	}
+
+	if err := d.subs.SaveDigestSchedule(context.Background(), sub); err != nil {
+		d.log.Error("Can't save digest schedule", "user id", sub.UserID, "err", err.Error())
+	}
+}
+
+func (d *Digester) send(ctx context.Context, sub models.DigestSubscription, from time.Time, to time.Time) error {
+	articles, err := d.articles.PostedBetween(ctx, from, to, d.maxArticles)
+	if err != nil {
+		return fmt.Errorf("can't get articles: %w", err)
+	}
+
+	if len(articles) == 0 {
+		d.log.Debug("No articles for digest", "user id", sub.UserID)
+		return nil
+	}
+
+	msg, err := Render(Digest{
+		UserName:       sub.UserName,
+		Frequency:      sub.Frequency,
+		From:           from.In(d.loc),
+		To:             to.In(d.loc),
+		SiteURL:        d.siteURL,
+		UnsubscribeURL: d.siteURL + "/user/digest/unsubscribe?token=" + UnsubscribeToken(d.secret, sub.UserID),
+		Articles:       articles,
+	})
+	if err != nil {
+		return fmt.Errorf("can't render digest: %w", err)
+	}
+
+	msg.To = sub.Email
+
+	if err := d.mailer.Send(ctx, *msg); err != nil {
+		return err
+	}
+
+	d.log.Info("Digest sent", "user id", sub.UserID, "frequency", sub.Frequency, "articles", len(articles))
+
+	return nil
+}
+
+// nextSend returns the first time after t digest of the frequency is sent.
+func (d *Digester) nextSend(t time.Time, frequency string) time.Time {
+	t = t.In(d.loc)
+
+	for day := t.Day(); ; day++ {
+		next := time.Date(t.Year(), t.Month(), day, d.sendHour, d.sendMinute, 0, 0, d.loc)
+
+		if next.After(t) && (frequency != models.DigestWeekly || next.Weekday() == d.weekday) {
+			return next
+		}
+	}
+}
+
+func period(frequency string) time.Duration {
+	if frequency == models.DigestWeekly {
+		return 7 * 24 * time.Hour
+	}
+
+	return 24 * time.Hour
+}
+
+// isPermanent reports whether SMTP server rejected the message for good.
+func isPermanent(err error) bool {
+	var protoErr *textproto.Error
+
+	return errors.As(err, &protoErr) && protoErr.Code >= 500
+}
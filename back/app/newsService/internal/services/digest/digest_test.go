@@ -0,0 +1,99 @@
+package digest
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"newsWebApp/app/newsService/internal/models"
+)
+
+func TestNextSend(t *testing.T) {
+	loc := time.FixedZone("UTC+3", 3*60*60)
+	d := &Digester{loc: loc, sendHour: 8, sendMinute: 30, weekday: time.Monday}
+
+	// 2024-05-01 is Wednesday.
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2024, 5, day, hour, minute, 0, 0, loc)
+	}
+
+	tests := []struct {
+		name      string
+		t         time.Time
+		frequency string
+		want      time.Time
+	}{
+		{"daily before send time", at(1, 7, 0), models.DigestDaily, at(1, 8, 30)},
+		{"daily at send time", at(1, 8, 30), models.DigestDaily, at(2, 8, 30)},
+		{"daily after send time", at(1, 23, 0), models.DigestDaily, at(2, 8, 30)},
+		{"daily other zone", time.Date(2024, 5, 1, 4, 0, 0, 0, time.UTC), models.DigestDaily, at(1, 8, 30)},
+		{"daily end of month", at(31, 9, 0), models.DigestDaily, time.Date(2024, 6, 1, 8, 30, 0, 0, loc)},
+		{"weekly", at(1, 7, 0), models.DigestWeekly, at(6, 8, 30)},
+		{"weekly on its day", at(6, 8, 0), models.DigestWeekly, at(6, 8, 30)},
+		{"weekly after send time", at(6, 9, 0), models.DigestWeekly, at(13, 8, 30)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := d.nextSend(tt.t, tt.frequency); !got.Equal(tt.want) {
+				t.Errorf("nextSend(%v, %s) = %v, want %v", tt.t, tt.frequency, got, tt.want)
+			}
+		})
+	}
+}
+
+type fakeSubs struct {
+	DigestStorage
+
+	saved []models.DigestSubscription
+}
+
+func (s *fakeSubs) SaveDigestSchedule(ctx context.Context, sub models.DigestSubscription) error {
+	s.saved = append(s.saved, sub)
+	return nil
+}
+
+type fakeArticles struct{}
+
+func (fakeArticles) PostedBetween(ctx context.Context, from time.Time, to time.Time, limit int) ([]models.Article, error) {
+	return []models.Article{{ID: 1, Title: "Bike lanes", Link: "https://example.com/a", SourceName: "blog", PostedAt: to}}, nil
+}
+
+// fakeMailer fails like SMTP client when ctx is done.
+type fakeMailer struct {
+	sent []Message
+}
+
+func (m *fakeMailer) Send(ctx context.Context, msg Message) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	m.sent = append(m.sent, msg)
+	return nil
+}
+
+func TestDeliverOnShutdown(t *testing.T) {
+	subs, mailer := &fakeSubs{}, &fakeMailer{}
+
+	d, err := New(subs, fakeArticles{}, mailer, "secret", "http://localhost", "08:00", "monday", "UTC",
+		time.Minute, 1, 10, time.Hour, time.Second, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	d.deliver(ctx, models.DigestSubscription{UserID: 2, Email: "a@example.com", Frequency: models.DigestDaily})
+
+	if len(mailer.sent) != 1 {
+		t.Fatalf("sent %d digests, want 1", len(mailer.sent))
+	}
+
+	if len(subs.saved) != 1 || subs.saved[0].LastSentAt.IsZero() {
+		t.Fatalf("saved schedules = %v, want one with LastSentAt", subs.saved)
+	}
+}
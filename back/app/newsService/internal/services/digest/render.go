@@ -0,0 +1,156 @@
+package digest
+
+import (
+	"bytes"
+	"fmt"
+	htmltemplate "html/template"
+	"sort"
+	"strings"
+	texttemplate "text/template"
+	"time"
+
+	"newsWebApp/app/newsService/internal/models"
+)
+
+// otherSource groups articles without a source.
+const otherSource = "Other"
+
+// Digest is what a digest email is made of.
+type Digest struct {
+	UserName       string
+	Frequency      string
+	From           time.Time
+	To             time.Time
+	SiteURL        string
+	UnsubscribeURL string
+	Articles       []models.Article
+}
+
+// Message is an email with plain text and HTML versions of the same content.
+type Message struct {
+	To             string
+	Subject        string
+	Text           string
+	HTML           string
+	UnsubscribeURL string
+}
+
+type sourceGroup struct {
+	Source   string
+	Articles []models.Article
+}
+
+type digestView struct {
+	Digest
+	Title   string
+	Period  string
+	Count   int
+	Sources []sourceGroup
+}
+
+var htmlTemplate = htmltemplate.Must(htmltemplate.New("digest").Parse(`<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>{{.Title}}</title></head>
+<body style="font-family: sans-serif; max-width: 640px; margin: 0 auto;">
+<h1 style="font-size: 22px;">{{.Title}}</h1>
+<p>Hi {{.UserName}}, here {{if eq .Count 1}}is 1 article{{else}}are {{.Count}} articles{{end}} posted {{.Period}}.</p>
+{{range .Sources}}<h2 style="font-size: 18px; border-bottom: 1px solid #ddd;">{{.Source}}</h2>
+<ul style="padding-left: 18px;">
+{{range .Articles}}<li style="margin-bottom: 12px;"><a href="{{.Link}}">{{.Title}}</a>{{if .Excerpt}}<br><span style="color: #555;">{{.Excerpt}}</span>{{end}}</li>
+{{end}}</ul>
+{{end}}<p style="font-size: 12px; color: #888;">You get this {{.Frequency}} digest from <a href="{{.SiteURL}}">{{.SiteURL}}</a>. <a href="{{.UnsubscribeURL}}">Unsubscribe</a></p>
+</body>
+</html>
+`))
+
+var textTemplate = texttemplate.Must(texttemplate.New("digest").Parse(`{{.Title}}
+
+Hi {{.UserName}}, here {{if eq .Count 1}}is 1 article{{else}}are {{.Count}} articles{{end}} posted {{.Period}}.
+{{range .Sources}}
+== {{.Source}} ==
+{{range .Articles}}
+* {{.Title}}
+  {{.Link}}
+{{end}}{{end}}
+--
+You get this {{.Frequency}} digest from {{.SiteURL}}.
+Unsubscribe: {{.UnsubscribeURL}}
+`))
+
+// Render returns digest email with articles grouped by source. Sources go in
+// alphabetical order, articles of a source from the newest one.
+func Render(digest Digest) (*Message, error) {
+	view := digestView{
+		Digest:  digest,
+		Title:   titleOf(digest.Frequency),
+		Period:  fmt.Sprintf("from %s till %s", digest.From.Format("Jan 2 15:04"), digest.To.Format("Jan 2 15:04 MST")),
+		Count:   len(digest.Articles),
+		Sources: groupBySource(digest.Articles),
+	}
+
+	var html, text bytes.Buffer
+
+	if err := htmlTemplate.Execute(&html, view); err != nil {
+		return nil, err
+	}
+
+	if err := textTemplate.Execute(&text, view); err != nil {
+		return nil, err
+	}
+
+	return &Message{
+		Subject:        fmt.Sprintf("%s: %d new %s", view.Title, view.Count, plural(view.Count, "article", "articles")),
+		Text:           text.String(),
+		HTML:           html.String(),
+		UnsubscribeURL: digest.UnsubscribeURL,
+	}, nil
+}
+
+func groupBySource(articles []models.Article) []sourceGroup {
+	groups := []sourceGroup{}
+	index := make(map[string]int)
+
+	for _, article := range articles {
+		source := strings.TrimSpace(article.SourceName)
+		if source == "" {
+			source = otherSource
+		}
+
+		i, ok := index[strings.ToLower(source)]
+		if !ok {
+			i = len(groups)
+			index[strings.ToLower(source)] = i
+			groups = append(groups, sourceGroup{Source: source})
+		}
+
+		groups[i].Articles = append(groups[i].Articles, article)
+	}
+
+	sort.SliceStable(groups, func(i, j int) bool {
+		return strings.ToLower(groups[i].Source) < strings.ToLower(groups[j].Source)
+	})
+
+	for _, group := range groups {
+		sort.SliceStable(group.Articles, func(i, j int) bool {
+			return group.Articles[i].PostedAt.After(group.Articles[j].PostedAt)
+		})
+	}
+
+	return groups
+}
+
+func titleOf(frequency string) string {
+	if frequency == models.DigestWeekly {
+		return "Weekly news digest"
+	}
+
+	return "Daily news digest"
+}
+
+func plural(n int, one string, many string) string {
+	if n == 1 {
+		return one
+	}
+
+	return many
+}
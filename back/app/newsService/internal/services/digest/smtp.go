@@ -0,0 +1,219 @@
+package digest
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/hex"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Connection security of SMTP server.
+const (
+	SecurityNone     = "none"
+	SecurityStartTLS = "starttls"
+	SecurityTLS      = "tls"
+)
+
+// SMTP sends messages through SMTP server. Server without security, like a local
+// test sink, gets credentials only when it's on localhost.
+type SMTP struct {
+	addr     string
+	host     string
+	security string
+	username string
+	password string
+	from     *mail.Address
+	timeout  time.Duration
+}
+
+func NewSMTP(host string, port int, security string, username string, password string, from string, timeout time.Duration) (*SMTP, error) {
+	const op = "services.digest.new_smtp"
+
+	switch security {
+	case SecurityNone, SecurityStartTLS, SecurityTLS:
+	default:
+		return nil, fmt.Errorf("%s: unknown security %q", op, security)
+	}
+
+	sender, err := mail.ParseAddress(from)
+	if err != nil {
+		return nil, fmt.Errorf("%s: from: %w", op, err)
+	}
+
+	return &SMTP{
+		addr:     net.JoinHostPort(host, strconv.Itoa(port)),
+		host:     host,
+		security: security,
+		username: username,
+		password: password,
+		from:     sender,
+		timeout:  timeout,
+	}, nil
+}
+
+func (s *SMTP) Send(ctx context.Context, msg Message) error {
+	to, err := mail.ParseAddress(msg.To)
+	if err != nil {
+		return &textproto.Error{Code: 553, Msg: fmt.Sprintf("invalid address %q", msg.To)}
+	}
+
+	data, err := s.compose(to, msg)
+	if err != nil {
+		return fmt.Errorf("can't compose message: %w", err)
+	}
+
+	conn, err := s.dial(ctx)
+	if err != nil {
+		return err
+	}
+
+	deadline := time.Now().Add(s.timeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+
+	if err := conn.SetDeadline(deadline); err != nil {
+		conn.Close()
+		return err
+	}
+
+	c, err := smtp.NewClient(conn, s.host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer c.Close()
+
+	if s.security == SecurityStartTLS {
+		if ok, _ := c.Extension("STARTTLS"); !ok {
+			return fmt.Errorf("smtp server %s doesn't support STARTTLS", s.addr)
+		}
+
+		if err := c.StartTLS(&tls.Config{ServerName: s.host}); err != nil {
+			return err
+		}
+	}
+
+	if s.username != "" {
+		if err := c.Auth(smtp.PlainAuth("", s.username, s.password, s.host)); err != nil {
+			return err
+		}
+	}
+
+	if err := c.Mail(s.from.Address); err != nil {
+		return err
+	}
+
+	if err := c.Rcpt(to.Address); err != nil {
+		return err
+	}
+
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+
+	if _, err := w.Write(data); err != nil {
+		return err
+	}
+
+	if err := w.Close(); err != nil {
+		return err
+	}
+
+	return c.Quit()
+}
+
+func (s *SMTP) dial(ctx context.Context) (net.Conn, error) {
+	dialer := &net.Dialer{Timeout: s.timeout}
+
+	if s.security == SecurityTLS {
+		tlsDialer := &tls.Dialer{NetDialer: dialer, Config: &tls.Config{ServerName: s.host}}
+		return tlsDialer.DialContext(ctx, "tcp", s.addr)
+	}
+
+	return dialer.DialContext(ctx, "tcp", s.addr)
+}
+
+// compose returns multipart/alternative message with plain text and HTML parts.
+func (s *SMTP) compose(to *mail.Address, msg Message) ([]byte, error) {
+	var body bytes.Buffer
+
+	parts := multipart.NewWriter(&body)
+
+	for _, part := range []struct {
+		contentType string
+		content     string
+	}{
+		{"text/plain; charset=utf-8", msg.Text},
+		{"text/html; charset=utf-8", msg.HTML},
+	} {
+		w, err := parts.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		qp := quotedprintable.NewWriter(w)
+
+		if _, err := qp.Write([]byte(part.content)); err != nil {
+			return nil, err
+		}
+
+		if err := qp.Close(); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := parts.Close(); err != nil {
+		return nil, err
+	}
+
+	headers := []string{
+		"From: " + s.from.String(),
+		"To: " + to.String(),
+		"Subject: " + mime.QEncoding.Encode("utf-8", msg.Subject),
+		"Date: " + time.Now().Format(time.RFC1123Z),
+		"Message-ID: " + s.messageID(),
+		"MIME-Version: 1.0",
+		"Content-Type: multipart/alternative; boundary=" + parts.Boundary(),
+	}
+
+	if msg.UnsubscribeURL != "" {
+		headers = append(headers,
+			"List-Unsubscribe: <"+msg.UnsubscribeURL+">",
+			"List-Unsubscribe-Post: List-Unsubscribe=One-Click",
+		)
+	}
+
+	var buf bytes.Buffer
+
+	buf.WriteString(strings.Join(headers, "\r\n"))
+	buf.WriteString("\r\n\r\n")
+	buf.Write(body.Bytes())
+
+	return buf.Bytes(), nil
+}
+
+func (s *SMTP) messageID() string {
+	id := make([]byte, 16)
+	_, _ = rand.Read(id)
+
+	domain := s.from.Address[strings.LastIndex(s.from.Address, "@")+1:]
+
+	return "<" + hex.EncodeToString(id) + "@" + domain + ">"
+}
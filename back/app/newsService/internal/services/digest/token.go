@@ -0,0 +1,54 @@
+package digest
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+)
+
+var errInvalidToken = errors.New("invalid unsubscribe token")
+
+// UnsubscribeToken returns token of unsubscribe link of the user, "id.mac" where mac
+// is HMAC-SHA256 of the id with the secret. It doesn't expire, so links in old
+// digests keep working.
+func UnsubscribeToken(secret []byte, userID int64) string {
+	id := strconv.FormatInt(userID, 10)
+
+	return id + "." + base64.RawURLEncoding.EncodeToString(tokenMAC(secret, id))
+}
+
+// ParseUnsubscribeToken returns id of the user the token was made for.
+func ParseUnsubscribeToken(secret []byte, token string) (int64, error) {
+	// Without a secret anyone could make a token.
+	if len(secret) == 0 {
+		return 0, errInvalidToken
+	}
+
+	id, mac, ok := strings.Cut(token, ".")
+	if !ok {
+		return 0, errInvalidToken
+	}
+
+	userID, err := strconv.ParseInt(id, 10, 64)
+	if err != nil || userID <= 0 {
+		return 0, errInvalidToken
+	}
+
+	got, err := base64.RawURLEncoding.DecodeString(mac)
+	if err != nil || !hmac.Equal(got, tokenMAC(secret, id)) {
+		return 0, errInvalidToken
+	}
+
+	return userID, nil
+}
+
+func tokenMAC(secret []byte, id string) []byte {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte("digest-unsubscribe:"))
+	mac.Write([]byte(id))
+
+	return mac.Sum(nil)
+}
@@ -0,0 +1,60 @@
+package digest
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestUnsubscribeToken(t *testing.T) {
+	secret := []byte("secret")
+
+	for _, userID := range []int64{1, 42, 9007199254740993} {
+		token := UnsubscribeToken(secret, userID)
+
+		if strings.ContainsAny(token, "+/=") {
+			t.Errorf("token %q isn't url safe", token)
+		}
+
+		got, err := ParseUnsubscribeToken(secret, token)
+		if err != nil {
+			t.Fatalf("ParseUnsubscribeToken(%q) error = %v", token, err)
+		}
+
+		if got != userID {
+			t.Errorf("ParseUnsubscribeToken(%q) = %d, want %d", token, got, userID)
+		}
+	}
+}
+
+func TestParseUnsubscribeTokenInvalid(t *testing.T) {
+	secret := []byte("secret")
+	token := UnsubscribeToken(secret, 42)
+	_, mac, _ := strings.Cut(token, ".")
+
+	tests := []struct {
+		name   string
+		secret []byte
+		token  string
+	}{
+		{"no secret", nil, UnsubscribeToken(nil, 42)},
+		{"other secret", []byte("other"), token},
+		{"other user", secret, "43." + mac},
+		{"no mac", secret, "42"},
+		{"empty mac", secret, "42."},
+		{"bad mac", secret, "42.!!!"},
+		{"truncated mac", secret, token[:len(token)-2]},
+		{"zero id", secret, UnsubscribeToken(secret, 0)},
+		{"negative id", secret, UnsubscribeToken(secret, -1)},
+		{"bad id", secret, "x." + mac},
+		{"empty", secret, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseUnsubscribeToken(tt.secret, tt.token); !errors.Is(err, errInvalidToken) {
+				t.Errorf("ParseUnsubscribeToken(%q) error = %v, want %v", tt.token, err, errInvalidToken)
+			}
+		})
+	}
+}
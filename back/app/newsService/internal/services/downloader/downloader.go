@@ -0,0 +1,212 @@
+package downloader
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Downloader is a pooled http client shared by all sources. It limits number of
+// requests in flight and rate of requests to every host.
+type Downloader struct {
+	client *http.Client
+	slots  chan struct{}
+
+	hostRate  time.Duration
+	hostBurst int
+	hosts     *hosts
+}
+
+func New(workers int, hostRate time.Duration, hostBurst int, timeout time.Duration) *Downloader {
+	if workers < 1 {
+		workers = 1
+	}
+
+	if hostBurst < 1 {
+		hostBurst = 1
+	}
+
+	transport := &http.Transport{
+		Proxy: http.ProxyFromEnvironment,
+		DialContext: (&net.Dialer{
+			Timeout:   10 * time.Second,
+			KeepAlive: 10 * time.Second,
+		}).DialContext,
+		MaxIdleConns:          100,
+		MaxIdleConnsPerHost:   hostBurst,
+		IdleConnTimeout:       60 * time.Second,
+		TLSHandshakeTimeout:   5 * time.Second,
+		ExpectContinueTimeout: 1 * time.Second,
+	}
+
+	return &Downloader{
+		client: &http.Client{
+			Timeout:   timeout,
+			Transport: transport,
+		},
+		slots:     make(chan struct{}, workers),
+		hostRate:  hostRate,
+		hostBurst: hostBurst,
+		hosts:     &hosts{buckets: make(map[string]*bucket)},
+	}
+}
+
+// Do waits for a free slot and a token of the request host, then sends the request.
+// The slot is held until response body is closed.
+func (d *Downloader) Do(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+
+	if err := d.bucket(req.URL.Hostname()).wait(ctx); err != nil {
+		return nil, err
+	}
+
+	select {
+	case d.slots <- struct{}{}:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		<-d.slots
+		return nil, err
+	}
+
+	resp.Body = &releaseBody{ReadCloser: resp.Body, release: func() { <-d.slots }}
+
+	return resp, nil
+}
+
+// hosts keeps buckets of hosts requested lately. Links come from users too, so
+// buckets of idle hosts are evicted, otherwise the map would grow without limit.
+type hosts struct {
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+}
+
+func (d *Downloader) bucket(host string) *bucket {
+	host = strings.TrimPrefix(strings.ToLower(host), "www.")
+
+	d.hosts.mu.Lock()
+	defer d.hosts.mu.Unlock()
+
+	b, ok := d.hosts.buckets[host]
+	if !ok {
+		d.evictIdle(time.Now())
+
+		b = newBucket(d.hostRate, d.hostBurst)
+		d.hosts.buckets[host] = b
+	}
+
+	return b
+}
+
+// evictIdle drops buckets which are full again. New bucket of such host is the same
+// as the dropped one. Buckets are swept at most once per refill time of a bucket.
+// Caller holds d.hosts.mu.
+func (d *Downloader) evictIdle(now time.Time) {
+	refill := d.hostRate * time.Duration(d.hostBurst)
+
+	if now.Sub(d.hosts.lastSweep) < refill {
+		return
+	}
+
+	d.hosts.lastSweep = now
+
+	for host, b := range d.hosts.buckets {
+		if b.full(now) {
+			delete(d.hosts.buckets, host)
+		}
+	}
+}
+
+type releaseBody struct {
+	io.ReadCloser
+	once    sync.Once
+	release func()
+}
+
+func (b *releaseBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.once.Do(b.release)
+	return err
+}
+
+// bucket is a token bucket refilled with one token every interval.
+type bucket struct {
+	interval time.Duration
+	burst    float64
+	tokens   float64
+	last     time.Time
+	mu       *sync.Mutex
+}
+
+func newBucket(interval time.Duration, burst int) *bucket {
+	return &bucket{
+		interval: interval,
+		burst:    float64(burst),
+		tokens:   float64(burst),
+		last:     time.Now(),
+		mu:       new(sync.Mutex),
+	}
+}
+
+func (b *bucket) wait(ctx context.Context) error {
+	if b.interval <= 0 {
+		return nil
+	}
+
+	for {
+		delay := b.take()
+		if delay == 0 {
+			return nil
+		}
+
+		timer := time.NewTimer(delay)
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// full tells that the bucket is refilled up to burst by now. Bucket without rate is never used.
+func (b *bucket) full(now time.Time) bool {
+	if b.interval <= 0 {
+		return true
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.tokens+float64(now.Sub(b.last))/float64(b.interval) >= b.burst
+}
+
+// take returns zero when token is taken or time to wait for the next token.
+func (b *bucket) take() time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := time.Now()
+
+	b.tokens += float64(now.Sub(b.last)) / float64(b.interval)
+	if b.tokens > b.burst {
+		b.tokens = b.burst
+	}
+	b.last = now
+
+	if b.tokens >= 1 {
+		b.tokens--
+		return 0
+	}
+
+	return time.Duration((1 - b.tokens) * float64(b.interval))
+}
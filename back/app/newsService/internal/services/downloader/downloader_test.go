@@ -0,0 +1,181 @@
+package downloader
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestBucketTake(t *testing.T) {
+	b := newBucket(time.Hour, 2)
+
+	if d := b.take(); d != 0 {
+		t.Fatalf("first take() = %s, want 0", d)
+	}
+
+	if d := b.take(); d != 0 {
+		t.Fatalf("second take() within burst = %s, want 0", d)
+	}
+
+	if d := b.take(); d <= 59*time.Minute || d > time.Hour {
+		t.Errorf("take() of empty bucket = %s, want about an hour", d)
+	}
+
+	// Half of the interval later half of a token is refilled.
+	b.last = b.last.Add(-30 * time.Minute)
+
+	if d := b.take(); d <= 29*time.Minute || d > 30*time.Minute {
+		t.Errorf("take() after half of interval = %s, want about 30m", d)
+	}
+
+	// Refill is capped by burst.
+	b.last = b.last.Add(-10 * time.Hour)
+
+	for i := 0; i < 2; i++ {
+		if d := b.take(); d != 0 {
+			t.Fatalf("take() %d after refill = %s, want 0", i, d)
+		}
+	}
+
+	if d := b.take(); d == 0 {
+		t.Error("take() over burst = 0, want delay")
+	}
+}
+
+func TestBucketWait(t *testing.T) {
+	b := newBucket(20*time.Millisecond, 1)
+
+	start := time.Now()
+
+	for i := 0; i < 3; i++ {
+		if err := b.wait(context.Background()); err != nil {
+			t.Fatalf("wait() error = %v", err)
+		}
+	}
+
+	if elapsed := time.Since(start); elapsed < 40*time.Millisecond {
+		t.Errorf("3 requests with burst 1 took %s, want at least 40ms", elapsed)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if err := newBucket(time.Hour, 1).wait(ctx); err != nil {
+		t.Errorf("wait() with a token error = %v, want nil", err)
+	}
+
+	empty := newBucket(time.Hour, 1)
+	empty.take()
+
+	if err := empty.wait(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("wait() of empty bucket error = %v, want context.Canceled", err)
+	}
+}
+
+func TestBucketEviction(t *testing.T) {
+	d := New(1, time.Minute, 2, time.Second)
+	g := d.Guarded(3)
+
+	busy := d.bucket("www.Busy.example")
+	idle := g.bucket("idle.example")
+
+	if got := d.bucket("busy.example"); got != busy {
+		t.Error("bucket of the same host isn't reused")
+	}
+
+	if got := d.bucket("idle.example"); got != idle {
+		t.Error("guarded downloader doesn't share buckets")
+	}
+
+	// Later than refill time of a bucket idle one is full again, busy one was just used.
+	now := time.Now().Add(3 * time.Minute)
+
+	busy.mu.Lock()
+	busy.tokens, busy.last = 0, now
+	busy.mu.Unlock()
+
+	d.hosts.mu.Lock()
+	d.evictIdle(now)
+	d.hosts.mu.Unlock()
+
+	if _, ok := d.hosts.buckets["idle.example"]; ok {
+		t.Error("idle bucket isn't evicted")
+	}
+
+	if _, ok := d.hosts.buckets["busy.example"]; !ok {
+		t.Error("bucket with taken token is evicted")
+	}
+
+	// Sweep isn't repeated within refill time.
+	d.hosts.buckets["idle.example"] = newBucket(time.Minute, 2)
+
+	d.hosts.mu.Lock()
+	d.evictIdle(now.Add(time.Second))
+	d.hosts.mu.Unlock()
+
+	if _, ok := d.hosts.buckets["idle.example"]; !ok {
+		t.Error("buckets are swept again within refill time")
+	}
+}
+
+func TestDoHoldsSlotUntilBodyClosed(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("page"))
+	}))
+	defer srv.Close()
+
+	d := New(1, 0, 1, time.Second)
+
+	get := func(ctx context.Context) (*http.Response, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return d.Do(req)
+	}
+
+	first, err := get(context.Background())
+	if err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	if _, err := get(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Do() while slot is held error = %v, want deadline exceeded", err)
+	}
+
+	io.ReadAll(first.Body)
+	first.Body.Close()
+	// Second close doesn't free a slot taken by someone else.
+	first.Body.Close()
+
+	second, err := get(context.Background())
+	if err != nil {
+		t.Fatalf("Do() after body is closed error = %v", err)
+	}
+	defer second.Body.Close()
+
+	if len(d.slots) != 1 {
+		t.Errorf("slots in use = %d, want 1", len(d.slots))
+	}
+}
+
+func TestDoReleasesSlotOnError(t *testing.T) {
+	d := New(1, 0, 1, time.Second)
+
+	req, _ := http.NewRequest(http.MethodGet, "http://127.0.0.1:0/", nil)
+
+	if _, err := d.Do(req); err == nil {
+		t.Fatal("Do() to closed port error = nil")
+	}
+
+	if len(d.slots) != 0 {
+		t.Errorf("slots in use after failed request = %d, want 0", len(d.slots))
+	}
+}
@@ -0,0 +1,57 @@
+package downloader
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"newsWebApp/pkg/netguard"
+)
+
+var (
+	ErrForbiddenAddress = netguard.ErrForbiddenAddress
+	ErrTooManyRedirects = errors.New("too many redirects")
+)
+
+// Guarded returns downloader for untrusted links. It shares workers and host limits
+// with d, but connects only to public addresses and follows at most maxRedirects redirects.
+func (d *Downloader) Guarded(maxRedirects int) *Downloader {
+	dialer := &net.Dialer{
+		Timeout:   10 * time.Second,
+		KeepAlive: 10 * time.Second,
+		Control:   netguard.Control,
+	}
+
+	transport := &http.Transport{
+		DialContext:           dialer.DialContext,
+		MaxIdleConns:          100,
+		MaxIdleConnsPerHost:   d.hostBurst,
+		IdleConnTimeout:       60 * time.Second,
+		TLSHandshakeTimeout:   5 * time.Second,
+		ExpectContinueTimeout: 1 * time.Second,
+	}
+
+	return &Downloader{
+		client: &http.Client{
+			Timeout:   d.client.Timeout,
+			Transport: transport,
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				if len(via) > maxRedirects {
+					return ErrTooManyRedirects
+				}
+
+				if req.URL.Scheme != "http" && req.URL.Scheme != "https" {
+					return fmt.Errorf("%w: redirect to %s scheme", ErrForbiddenAddress, req.URL.Scheme)
+				}
+
+				return nil
+			},
+		},
+		slots:     d.slots,
+		hostRate:  d.hostRate,
+		hostBurst: d.hostBurst,
+		hosts:     d.hosts,
+	}
+}
@@ -3,14 +3,41 @@ package services
 import "errors"
 
 var (
-	ErrNoPublishedArticles = errors.New("there are no published articles")
-	ErrNoNewArticles       = errors.New("there are no new articles")
-	ErrNoNewArticle        = errors.New("there is no new article")
-	ErrNoSources           = errors.New("there are no sources")
-	ErrArticleExists       = errors.New("article already exists")
-	ErrArticleSkipped      = errors.New("invalid article")
-	ErrLinkExists          = errors.New("link already exists")
-	ErrNoOfferedArticles   = errors.New("there are no offered articles")
-	ErrArticleNotAvailable = errors.New("article not available")
-	ErrInvalidUrl          = errors.New("url is invalid")
+	ErrNoPublishedArticles  = errors.New("there are no published articles")
+	ErrNoNewArticles        = errors.New("there are no new articles")
+	ErrNoNewArticle         = errors.New("there is no new article")
+	ErrNoSources            = errors.New("there are no sources")
+	ErrArticleExists        = errors.New("article already exists")
+	ErrArticleSkipped       = errors.New("invalid article")
+	ErrLinkExists           = errors.New("link already exists")
+	ErrNoOfferedArticles    = errors.New("there are no offered articles")
+	ErrArticleNotAvailable  = errors.New("article not available")
+	ErrArticleNotFound      = errors.New("article not found")
+	ErrInvalidUrl           = errors.New("url is invalid")
+	ErrForbiddenUrl         = errors.New("url is forbidden")
+	ErrFeedNotModified      = errors.New("feed not modified")
+	ErrSourceNotFound       = errors.New("source not found")
+	ErrSourceExists         = errors.New("source already exists")
+	ErrInvalidSource        = errors.New("invalid source")
+	ErrInvalidOPML          = errors.New("invalid opml document")
+	ErrInvalidConfig        = errors.New("invalid config")
+	ErrNothingToExplain     = errors.New("link or title is required")
+	ErrEmptyQuery           = errors.New("query is required")
+	ErrInvalidCursor        = errors.New("invalid cursor")
+	ErrNothingFound         = errors.New("nothing found")
+	ErrWatcherTooSlow       = errors.New("watcher is too slow")
+	ErrPublisherStopped     = errors.New("publisher stopped")
+	ErrNotDue               = errors.New("not time to publish")
+	ErrInvalidWebhook       = errors.New("invalid webhook")
+	ErrWebhookNotFound      = errors.New("webhook not found")
+	ErrNoWebhooks           = errors.New("there are no webhooks")
+	ErrInvalidStatus        = errors.New("invalid status")
+	ErrInvalidFollower      = errors.New("invalid follower")
+	ErrFollowerNotFound     = errors.New("follower not found")
+	ErrInvalidFrequency     = errors.New("invalid frequency")
+	ErrSubscriptionNotFound = errors.New("subscription not found")
+	ErrInvalidToken         = errors.New("invalid token")
+	ErrSubmissionNotFound   = errors.New("submission not found")
+	ErrNotPending           = errors.New("submission isn't pending")
+	ErrReasonRequired       = errors.New("reason is required")
 )
@@ -0,0 +1,35 @@
+package fetcher
+
+import (
+	"context"
+	"strings"
+
+	"newsWebApp/app/newsService/internal/models"
+	"newsWebApp/app/newsService/internal/services/sanitize"
+)
+
+// wordsPerMinute is an average reading speed used to estimate reading time.
+const wordsPerMinute = 200
+
+// saveContent saves extracted content of the article with sanitized HTML, pages are
+// never trusted. Article is kept without content when it can't be saved.
+func (f *Fetcher) saveContent(ctx context.Context, artID int64, item models.Item) {
+	words := len(strings.Fields(item.Text))
+
+	minutes := (words + wordsPerMinute - 1) / wordsPerMinute
+	if minutes == 0 && words > 0 {
+		minutes = 1
+	}
+
+	if err := f.articleStor.SaveContent(ctx, models.ArticleContent{
+		ArticleID:      artID,
+		HTML:           sanitize.HTML(item.HTML),
+		Text:           item.Text,
+		WordCount:      words,
+		ReadingMinutes: minutes,
+		Byline:         strings.TrimSpace(item.Byline),
+		Language:       item.Language,
+	}); err != nil {
+		f.log.Error("Can't save article content", "article id", artID, "err", err.Error())
+	}
+}
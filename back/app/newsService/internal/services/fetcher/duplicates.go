@@ -0,0 +1,220 @@
+package fetcher
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"newsWebApp/app/newsService/internal/models"
+	"newsWebApp/app/newsService/internal/services/simhash"
+	"newsWebApp/app/newsService/internal/storage"
+)
+
+// fingerprints keeps fingerprints of recent articles. They are reloaded at the start
+// of every fetch, so articles saved by other replicas are seen too. Lookup and save
+// of an article are made under mu, so copies fetched from two sources at once
+// can't both become heads of their groups.
+type fingerprints struct {
+	mu     sync.Mutex
+	recent []models.ArticleFingerprint
+}
+
+// reloadFingerprints replaces the kept fingerprints with the ones from dupWindow.
+// On failure old ones are kept.
+func (f *Fetcher) reloadFingerprints(ctx context.Context) {
+	if f.dupDistance < 0 {
+		return
+	}
+
+	recent, err := f.articleStor.RecentFingerprints(ctx, time.Now().Add(-f.dupWindow))
+	if err != nil {
+		f.log.Warn("Can't get recent fingerprints", "err", err.Error())
+		return
+	}
+
+	f.dups.mu.Lock()
+	f.dups.recent = recent
+	f.dups.mu.Unlock()
+}
+
+// saveArticle saves article with fingerprint of its title and text and extracted
+// content of the item and returns its id. Article close to a recent one joins its group
+// of duplicates, only the copy from the most preferred source stays eligible for posting.
+func (f *Fetcher) saveArticle(ctx context.Context, item models.Item, article models.Article) (int64, error) {
+	article.Fingerprint = simhash.Fingerprint(article.Title + " " + item.Text)
+
+	id, err := f.saveFingerprinted(ctx, article)
+	if err != nil {
+		return 0, err
+	}
+
+	f.saveContent(ctx, id, item)
+
+	return id, nil
+}
+
+// saveFingerprinted saves article as a duplicate of its group, if any, and makes it
+// head of the group when it's from a more preferred source.
+func (f *Fetcher) saveFingerprinted(ctx context.Context, article models.Article) (int64, error) {
+	f.dups.mu.Lock()
+	defer f.dups.mu.Unlock()
+
+	head, found := f.duplicateGroup(article)
+
+	if found {
+		// New copy is never eligible before it's linked, even for a moment.
+		article.DuplicateOf = head.ID
+	}
+
+	id, err := f.articleStor.SaveArticle(ctx, article)
+	if err != nil {
+		return 0, err
+	}
+
+	if article.Fingerprint != 0 {
+		f.dups.recent = append(f.dups.recent, models.ArticleFingerprint{
+			ID:          id,
+			UserID:      article.UserID,
+			SourceName:  article.SourceName,
+			Fingerprint: article.Fingerprint,
+			DuplicateOf: article.DuplicateOf,
+		})
+	}
+
+	if !found {
+		return id, nil
+	}
+
+	replaceHead := !head.Posted && f.rank(article.UserID, article.SourceName) < f.rank(head.UserID, head.SourceName)
+
+	f.log.Debug("Near duplicate article", "link", article.Link, "group", head.ID, "replaces", replaceHead)
+
+	if !replaceHead {
+		return id, nil
+	}
+
+	if err := f.articleStor.LinkDuplicates(ctx, head.ID, id); err != nil {
+		if errors.Is(err, storage.ErrArticleNotAvailable) {
+			f.log.Debug("Head of duplicates is posted already", "head id", head.ID, "article id", id)
+			f.markPosted(head.ID)
+			return id, nil
+		}
+		f.log.Error("Can't link duplicates", "head id", head.ID, "article id", id, "err", err.Error())
+		return id, nil
+	}
+
+	for i, fp := range f.dups.recent {
+		switch {
+		case fp.ID == id:
+			f.dups.recent[i].DuplicateOf = 0
+		case fp.ID == head.ID || fp.DuplicateOf == head.ID:
+			f.dups.recent[i].DuplicateOf = id
+		}
+	}
+
+	return id, nil
+}
+
+// deleteFingerprinted deletes article of the user. When it's head of a group of duplicates,
+// the copy from the most preferred source becomes the head, so only one copy stays eligible.
+func (f *Fetcher) deleteFingerprinted(ctx context.Context, userID int64, artID int64) error {
+	f.dups.mu.Lock()
+	defer f.dups.mu.Unlock()
+
+	var nextHead models.ArticleFingerprint
+
+	for _, fp := range f.dups.recent {
+		if fp.DuplicateOf != artID {
+			continue
+		}
+
+		if nextHead.ID == 0 || f.rank(fp.UserID, fp.SourceName) < f.rank(nextHead.UserID, nextHead.SourceName) {
+			nextHead = fp
+		}
+	}
+
+	if err := f.articleStor.DeleteArticle(ctx, userID, artID, nextHead.ID); err != nil {
+		return err
+	}
+
+	recent := f.dups.recent[:0]
+
+	for _, fp := range f.dups.recent {
+		switch {
+		case fp.ID == artID:
+			continue
+		case fp.ID == nextHead.ID:
+			fp.DuplicateOf = 0
+		case fp.DuplicateOf == artID:
+			fp.DuplicateOf = nextHead.ID
+		}
+
+		recent = append(recent, fp)
+	}
+
+	f.dups.recent = recent
+
+	return nil
+}
+
+// duplicateGroup returns head of the group of the nearest recent article within dupDistance.
+// Caller holds f.dups.mu.
+func (f *Fetcher) duplicateGroup(article models.Article) (models.ArticleFingerprint, bool) {
+	if f.dupDistance < 0 || article.Fingerprint == 0 {
+		return models.ArticleFingerprint{}, false
+	}
+
+	recent := f.dups.recent
+	nearest, best := -1, f.dupDistance+1
+
+	for i, fp := range recent {
+		if d := simhash.Distance(article.Fingerprint, fp.Fingerprint); d < best {
+			nearest, best = i, d
+		}
+	}
+
+	if nearest < 0 {
+		return models.ArticleFingerprint{}, false
+	}
+
+	match := recent[nearest]
+	if match.DuplicateOf == 0 {
+		return match, true
+	}
+
+	for _, fp := range recent {
+		if fp.ID == match.DuplicateOf {
+			return fp, true
+		}
+	}
+
+	// Head is older than the window, it's never replaced.
+	return models.ArticleFingerprint{ID: match.DuplicateOf, Posted: true}, true
+}
+
+// markPosted remembers that the article was posted by the publisher.
+// Caller holds f.dups.mu.
+func (f *Fetcher) markPosted(id int64) {
+	for i := range f.dups.recent {
+		if f.dups.recent[i].ID == id {
+			f.dups.recent[i].Posted = true
+			return
+		}
+	}
+}
+
+// rank returns priority of the copy, lower is better. Copies offered by users go first,
+// then preferred sources in configured order.
+func (f *Fetcher) rank(userID int64, sourceName string) int {
+	if userID > 1 {
+		return -1
+	}
+
+	if i, ok := f.preferred[strings.ToLower(sourceName)]; ok {
+		return i
+	}
+
+	return len(f.preferred)
+}
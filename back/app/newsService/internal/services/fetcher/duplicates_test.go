@@ -0,0 +1,272 @@
+package fetcher
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+
+	"newsWebApp/app/newsService/internal/models"
+	"newsWebApp/app/newsService/internal/storage"
+)
+
+// fakeArticles keeps saved articles in memory. Save is slow, so concurrent saves
+// without a lock would overlap.
+type fakeArticles struct {
+	ArticleStorage
+
+	mu       sync.Mutex
+	articles map[int64]models.Article
+	nextID   int64
+	loads    int
+}
+
+func newFakeArticles() *fakeArticles {
+	return &fakeArticles{articles: make(map[int64]models.Article)}
+}
+
+func (s *fakeArticles) SaveArticle(ctx context.Context, article models.Article) (int64, error) {
+	time.Sleep(10 * time.Millisecond)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.nextID++
+	article.ID = s.nextID
+	s.articles[article.ID] = article
+
+	return article.ID, nil
+}
+
+func (s *fakeArticles) SaveContent(ctx context.Context, content models.ArticleContent) error {
+	return nil
+}
+
+func (s *fakeArticles) RecentFingerprints(ctx context.Context, since time.Time) ([]models.ArticleFingerprint, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.loads++
+
+	recent := []models.ArticleFingerprint{}
+	for _, a := range s.articles {
+		recent = append(recent, models.ArticleFingerprint{
+			ID:          a.ID,
+			UserID:      a.UserID,
+			SourceName:  a.SourceName,
+			Fingerprint: a.Fingerprint,
+			DuplicateOf: a.DuplicateOf,
+			Posted:      !a.PostedAt.IsZero(),
+		})
+	}
+
+	return recent, nil
+}
+
+func (s *fakeArticles) LinkDuplicates(ctx context.Context, headID int64, newHeadID int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.articles[headID].PostedAt.IsZero() {
+		return storage.ErrArticleNotAvailable
+	}
+
+	for id, a := range s.articles {
+		switch {
+		case id == newHeadID:
+			a.DuplicateOf = 0
+		case id == headID || a.DuplicateOf == headID:
+			a.DuplicateOf = newHeadID
+		default:
+			continue
+		}
+		s.articles[id] = a
+	}
+
+	return nil
+}
+
+// DeleteArticle deletes not posted article of the user and makes nextHeadID, or else
+// the oldest copy, head of the group, like the storage does.
+func (s *fakeArticles) DeleteArticle(ctx context.Context, userID int64, artID int64, nextHeadID int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	deleted, ok := s.articles[artID]
+	if !ok || deleted.UserID != userID || !deleted.PostedAt.IsZero() {
+		return storage.ErrArticleNotAvailable
+	}
+
+	delete(s.articles, artID)
+
+	newHead := int64(0)
+	for id, a := range s.articles {
+		if a.DuplicateOf == artID && (newHead == 0 || id < newHead) {
+			newHead = id
+		}
+	}
+
+	if s.articles[nextHeadID].DuplicateOf == artID && nextHeadID != 0 {
+		newHead = nextHeadID
+	}
+
+	for id, a := range s.articles {
+		if a.DuplicateOf != artID {
+			continue
+		}
+		if id == newHead {
+			a.DuplicateOf = 0
+		} else {
+			a.DuplicateOf = newHead
+		}
+		s.articles[id] = a
+	}
+
+	return nil
+}
+
+// heads returns ids of articles which may be posted.
+func (s *fakeArticles) heads() []int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	heads := []int64{}
+	for id, a := range s.articles {
+		if a.DuplicateOf == 0 {
+			heads = append(heads, id)
+		}
+	}
+
+	return heads
+}
+
+const story = `The city council voted on Tuesday to expand the bike lane network across
+the downtown area, adding twelve kilometres of protected lanes over the next two years.`
+
+func newDupFetcher(articles *fakeArticles, preferred ...string) *Fetcher {
+	return New(articles, nil, nil, nil, nil, nil, nil, Settings{},
+		Options{DupDistance: 3, DupWindow: time.Hour, PreferredSources: preferred},
+		slog.New(slog.NewTextHandler(io.Discard, nil)))
+}
+
+func TestSaveArticleConcurrentCopies(t *testing.T) {
+	articles := newFakeArticles()
+	f := newDupFetcher(articles)
+	f.reloadFingerprints(context.Background())
+
+	var wg sync.WaitGroup
+
+	for _, source := range []string{"a", "b", "c", "d"} {
+		wg.Add(1)
+
+		go func(source string) {
+			defer wg.Done()
+
+			if _, err := f.saveArticle(context.Background(), models.Item{Text: story}, models.Article{Title: "Bike lanes", SourceName: source}); err != nil {
+				t.Errorf("saveArticle() error = %v", err)
+			}
+		}(source)
+	}
+
+	wg.Wait()
+
+	if heads := articles.heads(); len(heads) != 1 {
+		t.Errorf("eligible copies = %v, want one", heads)
+	}
+
+	if articles.loads != 1 {
+		t.Errorf("fingerprints are loaded %d times, want once", articles.loads)
+	}
+}
+
+func TestSaveArticlePreferredSource(t *testing.T) {
+	ctx := context.Background()
+	item := models.Item{Text: story}
+
+	t.Run("replaces head", func(t *testing.T) {
+		articles := newFakeArticles()
+		f := newDupFetcher(articles, "wire")
+		f.reloadFingerprints(ctx)
+
+		first, _ := f.saveArticle(ctx, item, models.Article{Title: "Bike lanes", SourceName: "blog"})
+		second, _ := f.saveArticle(ctx, item, models.Article{Title: "Bike lanes", SourceName: "other blog"})
+		wire, _ := f.saveArticle(ctx, item, models.Article{Title: "Bike lanes", SourceName: "Wire"})
+		last, _ := f.saveArticle(ctx, item, models.Article{Title: "Bike lanes", SourceName: "last blog"})
+
+		if heads := articles.heads(); len(heads) != 1 || heads[0] != wire {
+			t.Fatalf("eligible copies = %v, want [%d]", heads, wire)
+		}
+
+		for _, id := range []int64{first, second, last} {
+			if got := articles.articles[id].DuplicateOf; got != wire {
+				t.Errorf("article %d is duplicate of %d, want %d", id, got, wire)
+			}
+		}
+	})
+
+	t.Run("posted head stays", func(t *testing.T) {
+		articles := newFakeArticles()
+		f := newDupFetcher(articles, "wire")
+		f.reloadFingerprints(ctx)
+
+		first, _ := f.saveArticle(ctx, item, models.Article{Title: "Bike lanes", SourceName: "blog"})
+
+		// Publisher posts the head after fingerprints were loaded.
+		posted := articles.articles[first]
+		posted.PostedAt = time.Now()
+		articles.articles[first] = posted
+
+		wire, _ := f.saveArticle(ctx, item, models.Article{Title: "Bike lanes", SourceName: "wire"})
+
+		if heads := articles.heads(); len(heads) != 1 || heads[0] != first {
+			t.Fatalf("eligible copies = %v, want [%d]", heads, first)
+		}
+
+		if got := articles.articles[wire].DuplicateOf; got != first {
+			t.Errorf("preferred copy is duplicate of %d, want %d", got, first)
+		}
+	})
+}
+
+func TestDeleteHeadOfDuplicates(t *testing.T) {
+	ctx := context.Background()
+	item := models.Item{Text: story}
+
+	articles := newFakeArticles()
+	f := newDupFetcher(articles, "wire")
+	f.reloadFingerprints(ctx)
+
+	blog, _ := f.saveArticle(ctx, item, models.Article{Title: "Bike lanes", SourceName: "blog"})
+	wire, _ := f.saveArticle(ctx, item, models.Article{Title: "Bike lanes", SourceName: "wire"})
+	user, _ := f.saveArticle(ctx, item, models.Article{Title: "Bike lanes", UserID: 2})
+
+	if heads := articles.heads(); len(heads) != 1 || heads[0] != user {
+		t.Fatalf("eligible copies = %v, want [%d]", heads, user)
+	}
+
+	if err := f.deleteFingerprinted(ctx, 2, user); err != nil {
+		t.Fatalf("deleteFingerprinted() error = %v", err)
+	}
+
+	if heads := articles.heads(); len(heads) != 1 || heads[0] != wire {
+		t.Fatalf("eligible copies after delete = %v, want [%d]", heads, wire)
+	}
+
+	if got := articles.articles[blog].DuplicateOf; got != wire {
+		t.Errorf("article %d is duplicate of %d, want %d", blog, got, wire)
+	}
+
+	for _, fp := range f.dups.recent {
+		if fp.ID == user {
+			t.Errorf("fingerprint of deleted article %d is kept", user)
+		}
+	}
+
+	last, _ := f.saveArticle(ctx, item, models.Article{Title: "Bike lanes", SourceName: "last blog"})
+
+	if got := articles.articles[last].DuplicateOf; got != wire {
+		t.Errorf("new copy is duplicate of %d, want %d", got, wire)
+	}
+}
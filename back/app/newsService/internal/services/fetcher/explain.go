@@ -0,0 +1,66 @@
+package fetcher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"newsWebApp/app/newsService/internal/models"
+	"newsWebApp/app/newsService/internal/services"
+	"newsWebApp/app/newsService/internal/services/filter"
+	"newsWebApp/app/newsService/internal/services/itemHandler"
+)
+
+// ExplainFilter applies filter rules to the page of the link or to the title and
+// categories and tells which rules match. Nothing is cached or saved.
+func (f *Fetcher) ExplainFilter(ctx context.Context, link string, title string, categories []string, source string) (*models.FilterExplanation, error) {
+	const op = "services.fetcher.explain_filter"
+
+	item := models.Item{
+		Title:      strings.TrimSpace(title),
+		Categories: categories,
+		SourceName: source,
+	}
+
+	switch {
+	case link != "":
+		loaded, err := itemHandler.Preview(ctx, f.userLoader, f.maxPageSize, link)
+		if err != nil {
+			switch {
+			case errors.Is(err, services.ErrInvalidUrl):
+				f.log.Debug("Can't explain filter", "err", err.Error())
+				return nil, services.ErrInvalidUrl
+			case errors.Is(err, services.ErrForbiddenUrl):
+				f.log.Warn("Forbidden link in filter preview", "err", err.Error())
+				return nil, services.ErrForbiddenUrl
+			default:
+				f.log.Error("Can't explain filter", "link", link, "err", err.Error())
+				return nil, fmt.Errorf("%s: %w", op, err)
+			}
+		}
+
+		loaded.Categories = categories
+		if source != "" {
+			loaded.SourceName = source
+		}
+		item = loaded
+	case item.Title == "":
+		return nil, services.ErrNothingToExplain
+	}
+
+	decision, matched := f.Settings().Filter.Explain(filter.Item{
+		Title:      item.Title,
+		Categories: item.Categories,
+		Excerpt:    item.Excerpt,
+		Source:     item.SourceName,
+		Link:       item.Link,
+	})
+
+	return &models.FilterExplanation{
+		Item:         item,
+		Accepted:     decision.Accepted,
+		Rule:         decision.Rule,
+		MatchedRules: matched,
+	}, nil
+}
@@ -5,26 +5,40 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
-	"regexp"
+	"net/http"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"newsWebApp/app/newsService/internal/models"
 	"newsWebApp/app/newsService/internal/services"
+	"newsWebApp/app/newsService/internal/services/filter"
 	"newsWebApp/app/newsService/internal/services/itemHandler"
+	"newsWebApp/app/newsService/internal/services/simhash"
 	"newsWebApp/app/newsService/internal/storage"
 )
 
 type ArticleStorage interface {
-	SaveArticle(ctx context.Context, article models.Article) error
+	SaveArticle(ctx context.Context, article models.Article) (int64, error)
+	SaveContent(ctx context.Context, content models.ArticleContent) error
+	RecentFingerprints(ctx context.Context, since time.Time) ([]models.ArticleFingerprint, error)
+	LinkDuplicates(ctx context.Context, headID int64, newHeadID int64) error
 	UpdateArticle(ctx context.Context, artID int64, newArt models.Article) error
-	DeleteArticle(ctx context.Context, userID int64, artID int64) error
+	DeleteArticle(ctx context.Context, userID int64, artID int64, nextHeadID int64) error
 	LinkById(ctx context.Context, artID int64) (string, error)
 }
 
 type SourceStorage interface {
-	GetList(ctx context.Context) ([]models.Source, error)
+	GetDueList(ctx context.Context) ([]models.Source, error)
+	UpdateFeedState(ctx context.Context, source models.Source) error
+	MarkFetchSucceeded(ctx context.Context, id int64) error
+	MarkFetchFailed(ctx context.Context, id int64, lastError string, backoff time.Duration, maxBackoff time.Duration, maxFailures int) (*models.Source, error)
+}
+
+type DecisionStorage interface {
+	SaveDecision(ctx context.Context, decision models.FilterDecision) error
+	DeleteDecisionsBefore(ctx context.Context, before time.Time) (int64, error)
 }
 
 type LinkCacher interface {
@@ -33,57 +47,136 @@ type LinkCacher interface {
 	DeleteLink(ctx context.Context, link string) error
 }
 
+// Notifier tells webhooks about articles offered by users and failing sources.
+type Notifier interface {
+	ArticleSubmitted(ctx context.Context, article models.Article)
+	SourceFailed(ctx context.Context, source models.Source)
+}
+
+type Loader interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// Settings are options of the fetcher which may be changed without restart.
+type Settings struct {
+	Filter        *filter.Engine
+	FetchInterval time.Duration
+}
+
+// Options are options of the fetcher which are set on start. Failed source is put off
+// by Backoff doubled on every failure up to MaxBackoff and disabled after MaxFailures
+// failures, 0 never disables it. Articles within DupDistance of each other posted in
+// DupWindow are duplicates, negative DupDistance turns it off. PreferredSources go
+// first in their groups.
+type Options struct {
+	SourceBudget      int
+	MaxPageSize       int64
+	Backoff           time.Duration
+	MaxBackoff        time.Duration
+	MaxFailures       int
+	DupDistance       int
+	DupWindow         time.Duration
+	PreferredSources  []string
+	DecisionRetention time.Duration
+}
+
 type Fetcher struct {
 	articleStor ArticleStorage
 	sourceStor  SourceStorage
 	cacher      LinkCacher
-
-	fetchInterval time.Duration
-	keywordsSet   *sync.Map
-	regularExprs  []*regexp.Regexp
-	log           *slog.Logger
+	loader      Loader
+	userLoader  Loader
+
+	settings     *atomic.Pointer[Settings]
+	reloaded     chan struct{}
+	sourceBudget int
+	maxPageSize  int64
+	backoff      time.Duration
+	maxBackoff   time.Duration
+	maxFailures  int
+	dupDistance  int
+	dupWindow    time.Duration
+	dups         fingerprints
+	preferred    map[string]int
+	decisions    DecisionStorage
+	retention    time.Duration
+	notifier     Notifier
+	log          *slog.Logger
 }
 
 func New(
 	articleStorage ArticleStorage,
 	soureStorage SourceStorage,
 	cacher LinkCacher,
-	fetchInterval time.Duration,
-	filterKeywords []string,
+	loader Loader,
+	userLoader Loader,
+	decisions DecisionStorage,
+	notifier Notifier,
+	settings Settings,
+	opts Options,
 	log *slog.Logger,
 ) *Fetcher {
-	l := len(filterKeywords)
-	regExpr := make([]*regexp.Regexp, l)
-	keySet := sync.Map{}
-
-	for i, keyword := range filterKeywords {
-		keySet.Store(keyword, struct{}{})
+	preferred := make(map[string]int, len(opts.PreferredSources))
 
-		r := regexp.MustCompile(fmt.Sprintf("\\b%s\\b", keyword))
-		regExpr[i] = r
+	for i, name := range opts.PreferredSources {
+		preferred[strings.ToLower(name)] = i
 	}
 
+	current := new(atomic.Pointer[Settings])
+	current.Store(&settings)
+
 	return &Fetcher{
-		articleStor:   articleStorage,
-		sourceStor:    soureStorage,
-		cacher:        cacher,
-		fetchInterval: fetchInterval,
-		keywordsSet:   &keySet,
-		regularExprs:  regExpr,
-		log:           log,
+		articleStor:  articleStorage,
+		sourceStor:   soureStorage,
+		cacher:       cacher,
+		loader:       loader,
+		userLoader:   userLoader,
+		settings:     current,
+		reloaded:     make(chan struct{}, 1),
+		sourceBudget: opts.SourceBudget,
+		maxPageSize:  opts.MaxPageSize,
+		backoff:      opts.Backoff,
+		maxBackoff:   opts.MaxBackoff,
+		maxFailures:  opts.MaxFailures,
+		dupDistance:  opts.DupDistance,
+		dupWindow:    opts.DupWindow,
+		preferred:    preferred,
+		decisions:    decisions,
+		retention:    opts.DecisionRetention,
+		notifier:     notifier,
+		log:          log,
 	}
 }
 
+// Apply replaces settings of the fetcher. Items being filtered at the moment
+// may still see the previous settings.
+func (f *Fetcher) Apply(settings Settings) {
+	f.settings.Store(&settings)
+
+	select {
+	case f.reloaded <- struct{}{}:
+	default:
+	}
+}
+
+// Settings returns current settings of the fetcher.
+func (f *Fetcher) Settings() Settings {
+	return *f.settings.Load()
+}
+
 func (f *Fetcher) Start(ctx context.Context) error {
 	const op = "services.fetcher.start"
 
-	ticker := time.NewTicker(f.fetchInterval)
+	ticker := time.NewTicker(f.Settings().FetchInterval)
 	defer ticker.Stop()
 
 	if err := f.intervalFetch(ctx); err != nil {
-		if errors.Is(err, services.ErrNoSources) {
+		switch {
+		case errors.Is(err, context.Canceled):
+			return err
+		case errors.Is(err, services.ErrNoSources):
 			f.log.Debug("Can't do interval fetch", "err", err.Error())
-		} else {
+		default:
 			f.log.Error("Can't do interval fetch", "err", err.Error())
 			return fmt.Errorf("%s: %w", op, err)
 		}
@@ -93,11 +186,16 @@ func (f *Fetcher) Start(ctx context.Context) error {
 		select {
 		case <-ctx.Done():
 			return ctx.Err()
+		case <-f.reloaded:
+			ticker.Reset(f.Settings().FetchInterval)
 		case <-ticker.C:
 			if err := f.intervalFetch(ctx); err != nil {
-				if errors.Is(err, services.ErrNoSources) {
+				switch {
+				case errors.Is(err, context.Canceled):
+					return err
+				case errors.Is(err, services.ErrNoSources):
 					f.log.Debug("Can't do interval fetch", "err", err.Error())
-				} else {
+				default:
 					f.log.Error("Can't do interval fetch", "err", err.Error())
 					return fmt.Errorf("%s: %w", op, err)
 				}
@@ -109,7 +207,7 @@ func (f *Fetcher) Start(ctx context.Context) error {
 func (f *Fetcher) SaveArticleFromUser(ctx context.Context, userID int64, link string) error {
 	const op = "services.fetcher.save_article_from_user"
 
-	userHandler := itemHandler.NewFromUser(f.cacher, userID, link)
+	userHandler := itemHandler.NewFromUser(f.cacher, f.userLoader, f.maxPageSize, userID, link)
 
 	item, err := userHandler.LoadItem(ctx)
 	if err != nil {
@@ -120,6 +218,9 @@ func (f *Fetcher) SaveArticleFromUser(ctx context.Context, userID int64, link st
 		case errors.Is(err, services.ErrInvalidUrl):
 			f.log.Debug("Can't save article from user", "err", err.Error())
 			return services.ErrInvalidUrl
+		case errors.Is(err, services.ErrForbiddenUrl):
+			f.log.Warn("Forbidden article link from user", "user id", userID, "err", err.Error())
+			return services.ErrForbiddenUrl
 		default:
 			f.cacher.DeleteLink(ctx, link)
 			f.log.Error("Can't save article", "link", link, "err", err.Error())
@@ -127,20 +228,25 @@ func (f *Fetcher) SaveArticleFromUser(ctx context.Context, userID int64, link st
 		}
 	}
 
-	if f.itemShouldBeSkipped(item) {
-		f.cacher.DeleteLink(ctx, link)
+	if f.itemShouldBeSkipped(ctx, item, item.SourceName) {
+		f.cacher.DeleteLink(ctx, item.Link)
 		return services.ErrArticleSkipped
 	}
 
-	if err := f.articleStor.SaveArticle(ctx, models.Article{
-		UserID:      userID,
-		SourceName:  item.SourceName,
-		Title:       item.Title,
-		Link:        item.Link,
-		Excerpt:     item.Excerpt,
-		ImageURL:    item.ImageURL,
-		PublishedAt: item.Date,
-	}); err != nil {
+	article := models.Article{
+		UserID:       userID,
+		SourceName:   item.SourceName,
+		Title:        item.Title,
+		Link:         item.Link,
+		OriginalLink: item.OriginalLink,
+		Excerpt:      item.Excerpt,
+		ImageURL:     item.ImageURL,
+		PublishedAt:  item.Date,
+		Status:       models.StatusPending,
+	}
+
+	article.ID, err = f.saveArticle(ctx, item, article)
+	if err != nil {
 		if errors.Is(err, storage.ErrArticleExists) {
 			f.log.Debug("Can't save article from user", "err", err.Error())
 			return services.ErrArticleExists
@@ -149,13 +255,15 @@ func (f *Fetcher) SaveArticleFromUser(ctx context.Context, userID int64, link st
 		return fmt.Errorf("%s: %w", op, err)
 	}
 
+	f.notifier.ArticleSubmitted(ctx, article)
+
 	return nil
 }
 
 func (f *Fetcher) UpdateArticleByID(ctx context.Context, userID int64, artID int64, link string) error {
 	const op = "services.fetcher.update_article_by_id"
 
-	userHandler := itemHandler.NewFromUser(f.cacher, userID, link)
+	userHandler := itemHandler.NewFromUser(f.cacher, f.userLoader, f.maxPageSize, userID, link)
 
 	oldLink, err := f.articleStor.LinkById(ctx, artID)
 	if err != nil {
@@ -172,6 +280,9 @@ func (f *Fetcher) UpdateArticleByID(ctx context.Context, userID int64, artID int
 		case errors.Is(err, services.ErrInvalidUrl):
 			f.log.Debug("Can't update article from user", "err", err.Error())
 			return services.ErrInvalidUrl
+		case errors.Is(err, services.ErrForbiddenUrl):
+			f.log.Warn("Forbidden article link from user", "user id", userID, "err", err.Error())
+			return services.ErrForbiddenUrl
 		default:
 			f.cacher.DeleteLink(ctx, link)
 			f.log.Error("Can't update article", "link", link, "err", err.Error())
@@ -179,19 +290,22 @@ func (f *Fetcher) UpdateArticleByID(ctx context.Context, userID int64, artID int
 		}
 	}
 
-	if f.itemShouldBeSkipped(item) {
-		f.cacher.DeleteLink(ctx, link)
+	if f.itemShouldBeSkipped(ctx, item, item.SourceName) {
+		f.cacher.DeleteLink(ctx, item.Link)
 		return services.ErrArticleSkipped
 	}
 
 	if err := f.articleStor.UpdateArticle(ctx, artID, models.Article{
-		UserID:      userID,
-		SourceName:  item.SourceName,
-		Title:       item.Title,
-		Link:        item.Link,
-		Excerpt:     item.Excerpt,
-		ImageURL:    item.ImageURL,
-		PublishedAt: item.Date,
+		UserID:       userID,
+		SourceName:   item.SourceName,
+		Title:        item.Title,
+		Fingerprint:  simhash.Fingerprint(item.Title + " " + item.Text),
+		Link:         item.Link,
+		OriginalLink: item.OriginalLink,
+		Excerpt:      item.Excerpt,
+		ImageURL:     item.ImageURL,
+		PublishedAt:  item.Date,
+		Status:       models.StatusPending,
 	}); err != nil {
 		switch {
 		case errors.Is(err, storage.ErrArticleExists):
@@ -199,7 +313,7 @@ func (f *Fetcher) UpdateArticleByID(ctx context.Context, userID int64, artID int
 			return services.ErrArticleExists
 		case errors.Is(err, storage.ErrArticleNotAvailable):
 			f.log.Debug("Can't update article from user", "err", err.Error())
-			f.cacher.DeleteLink(ctx, link)
+			f.cacher.DeleteLink(ctx, item.Link)
 			return services.ErrArticleNotAvailable
 		default:
 			f.log.Error("Can't update article from user", "err", err.Error())
@@ -207,13 +321,15 @@ func (f *Fetcher) UpdateArticleByID(ctx context.Context, userID int64, artID int
 		}
 	}
 
+	f.saveContent(ctx, artID, item)
+
 	return nil
 }
 
 func (f *Fetcher) DeleteArticleByID(ctx context.Context, userID int64, artID int64) error {
 	const op = "services.fetcher.delete_article_by_id"
 
-	userHandler := itemHandler.NewFromUser(f.cacher, userID, "")
+	userHandler := itemHandler.NewFromUser(f.cacher, f.userLoader, f.maxPageSize, userID, "")
 
 	oldLink, err := f.articleStor.LinkById(ctx, artID)
 	if err != nil {
@@ -221,7 +337,7 @@ func (f *Fetcher) DeleteArticleByID(ctx context.Context, userID int64, artID int
 		return fmt.Errorf("%s: %w", op, err)
 	}
 
-	if err := f.articleStor.DeleteArticle(ctx, userID, artID); err != nil {
+	if err := f.deleteFingerprinted(ctx, userID, artID); err != nil {
 		if errors.Is(err, storage.ErrArticleNotAvailable) {
 			f.log.Debug("Can't delete article from user", "err", err.Error())
 			return services.ErrArticleNotAvailable
@@ -241,9 +357,11 @@ func (f *Fetcher) DeleteArticleByID(ctx context.Context, userID int64, artID int
 func (f *Fetcher) intervalFetch(ctx context.Context) error {
 	const op = "services.fetcher.interval_fetch"
 
-	sources, err := f.sourceStor.GetList(ctx)
+	sources, err := f.sourceStor.GetDueList(ctx)
 	if err != nil || len(sources) == 0 {
 		switch {
+		case ctx.Err() != nil:
+			return ctx.Err()
 		case len(sources) == 0:
 			return services.ErrNoSources
 		case errors.Is(err, storage.ErrNoSources):
@@ -253,58 +371,117 @@ func (f *Fetcher) intervalFetch(ctx context.Context) error {
 		}
 	}
 
+	f.reloadFingerprints(ctx)
+
 	wg := new(sync.WaitGroup)
 
 	for _, src := range sources {
 		wg.Add(1)
 
-		rssSource := itemHandler.NewFromRSS(f.cacher, src)
+		rssSource := itemHandler.NewFromRSS(f.cacher, f.loader, f.sourceBudget, src)
 
-		go func(rssSource *itemHandler.RSS) {
+		go func(src models.Source, rssSource *itemHandler.RSS) {
 			defer wg.Done()
 
 			ich := make(chan models.Item)
+			errCh := make(chan error, 1)
 
 			go func() {
-				err := rssSource.IntervalLoad(ctx, f.log, ich)
-				if err != nil {
-					f.log.Warn("Can't fetch items from source", "source name", rssSource.SourceName(), "err", err.Error())
-					return
-				}
+				errCh <- rssSource.IntervalLoad(ctx, f.log, ich)
 			}()
 
 			for itm := range ich {
-				if err := f.saveItem(ctx, itm); err != nil {
+				if err := f.saveItem(ctx, itm, src.Name); err != nil {
 					f.log.Warn("Can't save items in articles", "source name", rssSource.SourceName(), "err", err.Error())
+					rssSource.MarkIncomplete()
 					continue
 				}
 			}
 
-		}(rssSource)
+			f.saveSourceState(ctx, src, rssSource, <-errCh)
+		}(src, rssSource)
 	}
 
 	wg.Wait()
 
+	f.pruneDecisions(ctx)
+
 	return nil
 }
 
-func (f *Fetcher) saveItem(ctx context.Context, item models.Item) error {
+func (f *Fetcher) saveSourceState(ctx context.Context, src models.Source, rssSource *itemHandler.RSS, loadErr error) {
+	if ctx.Err() != nil {
+		return
+	}
+
+	if loadErr != nil {
+		if !errors.Is(loadErr, services.ErrFeedNotModified) {
+			f.markFetchFailed(ctx, src, loadErr)
+			return
+		}
+		f.log.Debug("Feed not modified", "source name", rssSource.SourceName())
+	}
+
+	if err := f.sourceStor.UpdateFeedState(ctx, rssSource.FeedState()); err != nil {
+		f.log.Warn("Can't save feed state", "source name", rssSource.SourceName(), "err", err.Error())
+	}
+
+	if err := f.sourceStor.MarkFetchSucceeded(ctx, src.ID); err != nil {
+		f.log.Warn("Can't save source health", "source name", rssSource.SourceName(), "err", err.Error())
+	}
+}
+
+// markFetchFailed pushes next fetch of the source back exponentially and disables
+// the source when it has failed maxFailures times in a row.
+func (f *Fetcher) markFetchFailed(ctx context.Context, src models.Source, fetchErr error) {
+	updated, err := f.sourceStor.MarkFetchFailed(ctx, src.ID, fetchErr.Error(), f.backoff, f.maxBackoff, f.maxFailures)
+	if err != nil {
+		if errors.Is(err, storage.ErrSourceNotFound) {
+			f.log.Debug("Source deleted while fetched", "source name", src.Name)
+			return
+		}
+		f.log.Warn("Can't save source health", "source name", src.Name, "err", err.Error())
+		return
+	}
+
+	disabled := f.maxFailures > 0 && updated.FailureCount == f.maxFailures
+
+	switch {
+	case disabled:
+		f.log.Warn("Source disabled after failed fetches", "source name", updated.Name, "failures", updated.FailureCount, "err", fetchErr.Error())
+	case updated.FailureCount == 1:
+		f.log.Warn("Can't fetch items from source", "source name", updated.Name, "err", fetchErr.Error())
+	default:
+		f.log.Debug("Can't fetch items from source", "source name", updated.Name, "failures", updated.FailureCount, "next fetch", updated.NextFetchAt)
+	}
+
+	// Webhooks hear about a source when it starts failing and when it's disabled.
+	if updated.FailureCount == 1 || disabled {
+		f.notifier.SourceFailed(ctx, *updated)
+	}
+}
+
+func (f *Fetcher) saveItem(ctx context.Context, item models.Item, source string) error {
 	const op = "services.fetcher.save_item"
 
-	if f.itemShouldBeSkipped(item) {
+	if f.itemShouldBeSkipped(ctx, item, source) {
 		f.cacher.DeleteLink(ctx, item.Link)
 		return nil
 	}
 
-	if err := f.articleStor.SaveArticle(ctx, models.Article{
-		SourceName:  item.SourceName,
-		Title:       item.Title,
-		Link:        item.Link,
-		Excerpt:     item.Excerpt,
-		ImageURL:    item.ImageURL,
-		PublishedAt: item.Date,
+	if _, err := f.saveArticle(ctx, item, models.Article{
+		SourceName:   item.SourceName,
+		Title:        item.Title,
+		Link:         item.Link,
+		OriginalLink: item.OriginalLink,
+		Excerpt:      item.Excerpt,
+		ImageURL:     item.ImageURL,
+		PublishedAt:  item.Date,
+		Status:       models.StatusApproved,
 	}); err != nil {
 		if !errors.Is(err, storage.ErrArticleExists) {
+			// Item is taken again on the next load.
+			f.cacher.DeleteLink(ctx, item.Link)
 			f.log.Error("Can't save item", "err", err.Error())
 			return fmt.Errorf("%s: %w", op, err)
 		}
@@ -312,37 +489,46 @@ func (f *Fetcher) saveItem(ctx context.Context, item models.Item) error {
 	return nil
 }
 
-func (f *Fetcher) itemShouldBeSkipped(item models.Item) bool {
-	if f.categoriesContainKeyword(item.Categories) || f.titleContainsKeyword(item.Title) {
-		return false
+// pruneDecisions deletes filter decisions older than retention.
+func (f *Fetcher) pruneDecisions(ctx context.Context) {
+	if f.retention <= 0 || ctx.Err() != nil {
+		return
 	}
-	return true
-}
 
-func (f *Fetcher) categoriesContainKeyword(categories []string) bool {
-	l := len(categories)
-
-	if l == 0 {
-		return false
+	deleted, err := f.decisions.DeleteDecisionsBefore(ctx, time.Now().Add(-f.retention))
+	if err != nil {
+		f.log.Warn("Can't delete old filter decisions", "err", err.Error())
+		return
 	}
 
-	for _, category := range categories {
-		if _, categoryEqualKeyword := f.keywordsSet.Load(category); categoryEqualKeyword {
-			return true
-		}
+	if deleted > 0 {
+		f.log.Debug("Old filter decisions deleted", "count", deleted)
 	}
-
-	return false
 }
 
-func (f *Fetcher) titleContainsKeyword(title string) bool {
-	validTitle := strings.ToLower(title)
-
-	for _, regular := range f.regularExprs {
-		if regular.MatchString(validTitle) {
-			return true
-		}
+// itemShouldBeSkipped applies filter rules to the item and records the decision.
+// Source is a name of the feed, rules scoped to it override global ones.
+func (f *Fetcher) itemShouldBeSkipped(ctx context.Context, item models.Item, source string) bool {
+	decision := f.Settings().Filter.Evaluate(filter.Item{
+		Title:      item.Title,
+		Categories: item.Categories,
+		Excerpt:    item.Excerpt,
+		Source:     source,
+		Link:       item.Link,
+	})
+
+	f.log.Debug("Filter decision", "link", item.Link, "accepted", decision.Accepted, "rule", decision.Rule)
+
+	if err := f.decisions.SaveDecision(ctx, models.FilterDecision{
+		Link:      item.Link,
+		Source:    source,
+		Title:     item.Title,
+		Accepted:  decision.Accepted,
+		Rule:      decision.Rule,
+		DecidedAt: time.Now(),
+	}); err != nil {
+		f.log.Warn("Can't record filter decision", "link", item.Link, "err", err.Error())
 	}
 
-	return false
+	return !decision.Accepted
 }
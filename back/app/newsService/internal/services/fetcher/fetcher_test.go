@@ -0,0 +1,52 @@
+package fetcher
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"newsWebApp/app/newsService/internal/models"
+	"newsWebApp/app/newsService/internal/services/filter"
+)
+
+type fakeDecisions struct{}
+
+func (fakeDecisions) SaveDecision(ctx context.Context, decision models.FilterDecision) error {
+	return nil
+}
+
+func (fakeDecisions) DeleteDecisionsBefore(ctx context.Context, before time.Time) (int64, error) {
+	return 0, nil
+}
+
+func TestSaveItemApproved(t *testing.T) {
+	engine, err := filter.Compile(nil, filter.ActionInclude)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	articles := newFakeArticles()
+
+	f := New(articles, nil, nil, nil, nil, fakeDecisions{}, nil, Settings{Filter: engine},
+		Options{DupDistance: -1, DupWindow: time.Hour},
+		slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	item := models.Item{Title: "Bike lanes", SourceName: "blog", Link: "https://example.com/a", Text: story}
+
+	if err := f.saveItem(context.Background(), item, "blog"); err != nil {
+		t.Fatalf("saveItem() error = %v", err)
+	}
+
+	if len(articles.articles) != 1 {
+		t.Fatalf("saved articles = %d, want 1", len(articles.articles))
+	}
+
+	for _, a := range articles.articles {
+		// Articles from sources aren't moderated, pending ones would never be posted.
+		if a.Status != models.StatusApproved {
+			t.Errorf("article from source status = %q, want %q", a.Status, models.StatusApproved)
+		}
+	}
+}
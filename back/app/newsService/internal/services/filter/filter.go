@@ -0,0 +1,179 @@
+package filter
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"newsWebApp/app/newsService/internal/models"
+)
+
+const (
+	ActionInclude = "include"
+	ActionExclude = "exclude"
+
+	// DefaultRule is a name of the decision made when no rule matches.
+	DefaultRule = "default"
+)
+
+// Item is what rules are matched against.
+type Item struct {
+	Title      string
+	Categories []string
+	Excerpt    string
+	Source     string
+	Link       string
+}
+
+// Decision tells whether item is accepted and which rule decided it.
+type Decision struct {
+	Accepted bool
+	Rule     string
+	Action   string
+}
+
+type rule struct {
+	name    string
+	action  string
+	sources map[string]struct{}
+	expr    node
+}
+
+// Engine is a compiled set of rules. Rules scoped to item source are checked before
+// global ones, the first matching rule decides. Engine is immutable and safe for
+// concurrent use.
+type Engine struct {
+	scoped        []rule
+	global        []rule
+	defaultAction string
+}
+
+// Compile validates and compiles rules. All invalid rules are reported in one error.
+func Compile(rules []models.FilterRule, defaultAction string) (*Engine, error) {
+	if defaultAction == "" {
+		defaultAction = ActionExclude
+	}
+
+	errs := []error{}
+
+	if defaultAction != ActionInclude && defaultAction != ActionExclude {
+		errs = append(errs, fmt.Errorf("default action must be %s or %s, got %q", ActionInclude, ActionExclude, defaultAction))
+	}
+
+	e := &Engine{defaultAction: defaultAction}
+	names := make(map[string]struct{}, len(rules))
+
+	for i, r := range rules {
+		name := r.Name
+		if name == "" {
+			name = "rule " + strconv.Itoa(i+1)
+		}
+
+		if _, ok := names[name]; ok {
+			errs = append(errs, fmt.Errorf("%s: duplicate rule name", name))
+			continue
+		}
+		names[name] = struct{}{}
+
+		action := strings.ToLower(r.Action)
+		if action != ActionInclude && action != ActionExclude {
+			errs = append(errs, fmt.Errorf("%s: action must be %s or %s, got %q", name, ActionInclude, ActionExclude, r.Action))
+			continue
+		}
+
+		expr, err := parse(r.Expr)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", name, err))
+			continue
+		}
+
+		compiled := rule{name: name, action: action, expr: expr}
+
+		if len(r.Sources) == 0 {
+			e.global = append(e.global, compiled)
+			continue
+		}
+
+		compiled.sources = make(map[string]struct{}, len(r.Sources))
+		for _, src := range r.Sources {
+			compiled.sources[strings.ToLower(src)] = struct{}{}
+		}
+		e.scoped = append(e.scoped, compiled)
+	}
+
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	return e, nil
+}
+
+// FromKeywords returns rule which accepts items with one of keywords in title or categories.
+func FromKeywords(keywords []string) models.FilterRule {
+	terms := make([]string, 0, 2*len(keywords))
+
+	for _, keyword := range keywords {
+		value := quote(keyword)
+		terms = append(terms, "categories:"+value, "title:"+value)
+	}
+
+	return models.FilterRule{
+		Name:   "filter_keywords",
+		Action: ActionInclude,
+		Expr:   strings.Join(terms, " OR "),
+	}
+}
+
+// Len returns number of rules.
+func (e *Engine) Len() int {
+	return len(e.scoped) + len(e.global)
+}
+
+// Evaluate returns decision of the first matching rule or the default one.
+func (e *Engine) Evaluate(item Item) Decision {
+	source := strings.ToLower(item.Source)
+
+	for _, r := range e.scoped {
+		if _, ok := r.sources[source]; ok && r.expr.match(&item) {
+			return decision(r.name, r.action)
+		}
+	}
+
+	for _, r := range e.global {
+		if r.expr.match(&item) {
+			return decision(r.name, r.action)
+		}
+	}
+
+	return decision(DefaultRule, e.defaultAction)
+}
+
+// Explain returns decision of Evaluate and names of all rules which match the item,
+// in order they are checked.
+func (e *Engine) Explain(item Item) (Decision, []string) {
+	source := strings.ToLower(item.Source)
+	matched := []string{}
+
+	for _, r := range e.scoped {
+		if _, ok := r.sources[source]; ok && r.expr.match(&item) {
+			matched = append(matched, r.name)
+		}
+	}
+
+	for _, r := range e.global {
+		if r.expr.match(&item) {
+			matched = append(matched, r.name)
+		}
+	}
+
+	return e.Evaluate(item), matched
+}
+
+func decision(name, action string) Decision {
+	return Decision{
+		Accepted: action == ActionInclude,
+		Rule:     name,
+		Action:   action,
+	}
+}
@@ -0,0 +1,99 @@
+package filter
+
+import (
+	"strings"
+	"testing"
+
+	"newsWebApp/app/newsService/internal/models"
+)
+
+func TestCompileErrors(t *testing.T) {
+	rules := []models.FilterRule{
+		{Name: "ok", Action: ActionInclude, Expr: "title:go"},
+		{Name: "ok", Action: ActionInclude, Expr: "title:rust"},
+		{Name: "action", Action: "drop", Expr: "title:go"},
+		{Action: ActionExclude, Expr: "title:"},
+	}
+
+	_, err := Compile(rules, "maybe")
+	if err == nil {
+		t.Fatal("Compile() error = nil")
+	}
+
+	for _, want := range []string{
+		`default action must be include or exclude, got "maybe"`,
+		"ok: duplicate rule name",
+		`action: action must be include or exclude, got "drop"`,
+		`rule 4: empty value of field "title"`,
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Compile() error = %q, want it to contain %q", err, want)
+		}
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	rules := []models.FilterRule{
+		{Name: "no ads", Action: ActionExclude, Expr: "categories:sponsored"},
+		{Name: "go", Action: ActionInclude, Expr: "title:go"},
+		{Name: "blog only", Action: "EXCLUDE", Expr: "title:go*", Sources: []string{"Go Blog"}},
+	}
+
+	e, err := Compile(rules, "")
+	if err != nil {
+		t.Fatalf("Compile() error = %v", err)
+	}
+
+	if e.Len() != 3 {
+		t.Errorf("Len() = %d, want 3", e.Len())
+	}
+
+	tests := []struct {
+		name    string
+		item    Item
+		want    Decision
+		matched []string
+	}{
+		{
+			name:    "first global rule",
+			item:    Item{Title: "Go news", Categories: []string{"Sponsored"}},
+			want:    Decision{Accepted: false, Rule: "no ads", Action: ActionExclude},
+			matched: []string{"no ads", "go"},
+		},
+		{
+			name:    "scoped rule first",
+			item:    Item{Title: "Go news", Source: "go blog"},
+			want:    Decision{Accepted: false, Rule: "blog only", Action: ActionExclude},
+			matched: []string{"blog only", "go"},
+		},
+		{
+			name:    "scoped rule of other source",
+			item:    Item{Title: "Go news", Source: "Other"},
+			want:    Decision{Accepted: true, Rule: "go", Action: ActionInclude},
+			matched: []string{"go"},
+		},
+		{
+			name:    "default",
+			item:    Item{Title: "Rust news"},
+			want:    Decision{Accepted: false, Rule: DefaultRule, Action: ActionExclude},
+			matched: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, matched := e.Explain(tt.item)
+			if got != tt.want {
+				t.Errorf("Explain() decision = %+v, want %+v", got, tt.want)
+			}
+
+			if strings.Join(matched, ",") != strings.Join(tt.matched, ",") {
+				t.Errorf("Explain() matched = %q, want %q", matched, tt.matched)
+			}
+
+			if got := e.Evaluate(tt.item); got != tt.want {
+				t.Errorf("Evaluate() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,351 @@
+package filter
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// Expression grammar:
+//
+//	expr    = and { "OR" and }
+//	and     = unary { "AND" unary }
+//	unary   = "NOT" unary | primary
+//	primary = "(" expr ")" | field ":" value
+//
+// Fields are title, categories, excerpt, source and domain. Value is a word or
+// a "quoted string" where \" and \\ are a quote and a backslash, trailing * matches
+// any word ending. Matching ignores case.
+
+type node interface {
+	match(item *Item) bool
+}
+
+type orNode struct{ left, right node }
+
+func (n orNode) match(item *Item) bool { return n.left.match(item) || n.right.match(item) }
+
+type andNode struct{ left, right node }
+
+func (n andNode) match(item *Item) bool { return n.left.match(item) && n.right.match(item) }
+
+type notNode struct{ inner node }
+
+func (n notNode) match(item *Item) bool { return !n.inner.match(item) }
+
+// textNode matches value as a whole word of title or excerpt.
+type textNode struct {
+	field string
+	re    *regexp.Regexp
+}
+
+func (n textNode) match(item *Item) bool {
+	switch n.field {
+	case "title":
+		return n.re.MatchString(strings.ToLower(item.Title))
+	default:
+		return n.re.MatchString(strings.ToLower(item.Excerpt))
+	}
+}
+
+// categoriesNode matches value with any whole category.
+type categoriesNode struct{ re *regexp.Regexp }
+
+func (n categoriesNode) match(item *Item) bool {
+	for _, category := range item.Categories {
+		if n.re.MatchString(strings.ToLower(strings.TrimSpace(category))) {
+			return true
+		}
+	}
+	return false
+}
+
+type sourceNode struct{ re *regexp.Regexp }
+
+func (n sourceNode) match(item *Item) bool {
+	return n.re.MatchString(strings.ToLower(item.Source))
+}
+
+// domainNode matches host of the link and its subdomains.
+type domainNode struct{ domain string }
+
+func (n domainNode) match(item *Item) bool {
+	u, err := url.Parse(item.Link)
+	if err != nil {
+		return false
+	}
+
+	host := strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+
+	return host == n.domain || strings.HasSuffix(host, "."+n.domain)
+}
+
+const wordChars = `\p{L}\p{N}_`
+
+type tokenKind int
+
+const (
+	tokenTerm tokenKind = iota
+	tokenOpen
+	tokenClose
+	tokenAnd
+	tokenOr
+	tokenNot
+)
+
+type token struct {
+	kind  tokenKind
+	field string
+	value string
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func parse(expr string) (node, error) {
+	tokens, err := tokenize(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("empty expression")
+	}
+
+	p := &parser{tokens: tokens}
+
+	n, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+
+	if p.pos < len(p.tokens) {
+		return nil, fmt.Errorf("unexpected token at position %d", p.pos+1)
+	}
+
+	return n, nil
+}
+
+func (p *parser) next() (token, bool) {
+	if p.pos >= len(p.tokens) {
+		return token{}, false
+	}
+	return p.tokens[p.pos], true
+}
+
+func (p *parser) parseOr() (node, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		t, ok := p.next()
+		if !ok || t.kind != tokenOr {
+			return left, nil
+		}
+		p.pos++
+
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+
+		left = orNode{left: left, right: right}
+	}
+}
+
+func (p *parser) parseAnd() (node, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		t, ok := p.next()
+		if !ok || t.kind != tokenAnd {
+			return left, nil
+		}
+		p.pos++
+
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+
+		left = andNode{left: left, right: right}
+	}
+}
+
+func (p *parser) parseUnary() (node, error) {
+	t, ok := p.next()
+	if !ok {
+		return nil, fmt.Errorf("unexpected end of expression")
+	}
+
+	switch t.kind {
+	case tokenNot:
+		p.pos++
+
+		inner, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+
+		return notNode{inner: inner}, nil
+	case tokenOpen:
+		p.pos++
+
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+
+		if t, ok := p.next(); !ok || t.kind != tokenClose {
+			return nil, fmt.Errorf("missing closing parenthesis")
+		}
+		p.pos++
+
+		return inner, nil
+	case tokenTerm:
+		p.pos++
+		return term(t.field, t.value)
+	default:
+		return nil, fmt.Errorf("unexpected token at position %d", p.pos+1)
+	}
+}
+
+func term(field, value string) (node, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if value == "" || value == "*" {
+		return nil, fmt.Errorf("empty value of field %q", field)
+	}
+
+	switch field {
+	case "title", "excerpt":
+		re, err := regexp.Compile(`(?:^|[^` + wordChars + `])` + pattern(value) + `(?:$|[^` + wordChars + `])`)
+		if err != nil {
+			return nil, err
+		}
+		return textNode{field: field, re: re}, nil
+	case "categories", "category":
+		re, err := regexp.Compile(`^` + pattern(value) + `$`)
+		if err != nil {
+			return nil, err
+		}
+		return categoriesNode{re: re}, nil
+	case "source":
+		re, err := regexp.Compile(`^` + pattern(value) + `$`)
+		if err != nil {
+			return nil, err
+		}
+		return sourceNode{re: re}, nil
+	case "domain":
+		return domainNode{domain: strings.TrimPrefix(value, "www.")}, nil
+	default:
+		return nil, fmt.Errorf("unknown field %q", field)
+	}
+}
+
+// pattern quotes value, so "c++" or "go.dev" are matched literally.
+func pattern(value string) string {
+	if strings.HasSuffix(value, "*") {
+		return regexp.QuoteMeta(strings.TrimSuffix(value, "*")) + `[` + wordChars + `]*`
+	}
+	return regexp.QuoteMeta(value)
+}
+
+func tokenize(expr string) ([]token, error) {
+	tokens := []token{}
+	runes := []rune(expr)
+
+	for i := 0; i < len(runes); {
+		switch r := runes[i]; {
+		case r == ' ' || r == '\t' || r == '\n' || r == '\r':
+			i++
+		case r == '(':
+			tokens = append(tokens, token{kind: tokenOpen})
+			i++
+		case r == ')':
+			tokens = append(tokens, token{kind: tokenClose})
+			i++
+		default:
+			start := i
+			for i < len(runes) && !isSeparator(runes[i]) && runes[i] != ':' {
+				i++
+			}
+			word := string(runes[start:i])
+
+			if i >= len(runes) || runes[i] != ':' {
+				switch strings.ToUpper(word) {
+				case "AND":
+					tokens = append(tokens, token{kind: tokenAnd})
+				case "OR":
+					tokens = append(tokens, token{kind: tokenOr})
+				case "NOT":
+					tokens = append(tokens, token{kind: tokenNot})
+				default:
+					return nil, fmt.Errorf("%q must be field:value or operator", word)
+				}
+				continue
+			}
+			i++
+
+			field := strings.ToLower(word)
+
+			if i < len(runes) && runes[i] == '"' {
+				value, end, err := quoted(runes, i)
+				if err != nil {
+					return nil, fmt.Errorf("%w in value of field %q", err, field)
+				}
+				tokens = append(tokens, token{kind: tokenTerm, field: field, value: value})
+				i = end
+				continue
+			}
+
+			start = i
+			for i < len(runes) && !isSeparator(runes[i]) {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokenTerm, field: field, value: string(runes[start:i])})
+		}
+	}
+
+	return tokens, nil
+}
+
+// quoted returns value of the quoted string starting at runes[start] and position
+// after its closing quote. Backslash makes the next character literal, so \" and \\
+// are a quote and a backslash.
+func quoted(runes []rune, start int) (string, int, error) {
+	var value strings.Builder
+
+	for i := start + 1; i < len(runes); i++ {
+		switch runes[i] {
+		case '"':
+			return value.String(), i + 1, nil
+		case '\\':
+			i++
+			if i >= len(runes) {
+				return "", 0, fmt.Errorf("unterminated quote")
+			}
+		}
+		value.WriteRune(runes[i])
+	}
+
+	return "", 0, fmt.Errorf("unterminated quote")
+}
+
+// quote returns value as a quoted string of an expression.
+func quote(value string) string {
+	return `"` + quoteReplacer.Replace(value) + `"`
+}
+
+var quoteReplacer = strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+
+func isSeparator(r rune) bool {
+	return r == ' ' || r == '\t' || r == '\n' || r == '\r' || r == '(' || r == ')'
+}
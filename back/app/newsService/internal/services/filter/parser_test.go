@@ -0,0 +1,256 @@
+package filter
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestTokenize(t *testing.T) {
+	tests := []struct {
+		name    string
+		expr    string
+		want    []token
+		wantErr string
+	}{
+		{
+			name: "term",
+			expr: "title:golang",
+			want: []token{{kind: tokenTerm, field: "title", value: "golang"}},
+		},
+		{
+			name: "field case",
+			expr: "Title:Go",
+			want: []token{{kind: tokenTerm, field: "title", value: "Go"}},
+		},
+		{
+			name: "operators and parentheses",
+			expr: "(title:a or\tcategories:b) AND not source:c",
+			want: []token{
+				{kind: tokenOpen},
+				{kind: tokenTerm, field: "title", value: "a"},
+				{kind: tokenOr},
+				{kind: tokenTerm, field: "categories", value: "b"},
+				{kind: tokenClose},
+				{kind: tokenAnd},
+				{kind: tokenNot},
+				{kind: tokenTerm, field: "source", value: "c"},
+			},
+		},
+		{
+			name: "quoted value",
+			expr: `title:"machine (learning)" OR source:x`,
+			want: []token{
+				{kind: tokenTerm, field: "title", value: "machine (learning)"},
+				{kind: tokenOr},
+				{kind: tokenTerm, field: "source", value: "x"},
+			},
+		},
+		{
+			name: "escaped quote and backslash",
+			expr: `title:"say \"hi\" to c:\\go" OR title:"\x"`,
+			want: []token{
+				{kind: tokenTerm, field: "title", value: `say "hi" to c:\go`},
+				{kind: tokenOr},
+				{kind: tokenTerm, field: "title", value: "x"},
+			},
+		},
+		{
+			name:    "escaped closing quote",
+			expr:    `title:"open\"`,
+			wantErr: `unterminated quote in value of field "title"`,
+		},
+		{
+			name:    "backslash at the end",
+			expr:    `title:"open\`,
+			wantErr: `unterminated quote in value of field "title"`,
+		},
+		{
+			name: "value with colon",
+			expr: "domain:example.com:8080",
+			want: []token{{kind: tokenTerm, field: "domain", value: "example.com:8080"}},
+		},
+		{
+			name:    "bare word",
+			expr:    "title:a golang",
+			wantErr: `"golang" must be field:value or operator`,
+		},
+		{
+			name:    "unterminated quote",
+			expr:    `title:"open`,
+			wantErr: `unterminated quote in value of field "title"`,
+		},
+		{
+			name: "empty",
+			expr: "  \n ",
+			want: []token{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tokenize(tt.expr)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("tokenize(%q) error = %v, want %q", tt.expr, err, tt.wantErr)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("tokenize(%q) error = %v", tt.expr, err)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("tokenize(%q) = %+v, want %+v", tt.expr, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		expr    string
+		wantErr string
+	}{
+		{"", "empty expression"},
+		{"title:a AND", "unexpected end of expression"},
+		{"NOT", "unexpected end of expression"},
+		{"(title:a", "missing closing parenthesis"},
+		{"title:a)", "unexpected token at position 2"},
+		{"OR title:a", "unexpected token at position 1"},
+		{"title:a title:b", "unexpected token at position 2"},
+		{"()", "unexpected token at position 2"},
+		{"body:a", `unknown field "body"`},
+		{`title:""`, `empty value of field "title"`},
+		{"title:*", `empty value of field "title"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			_, err := parse(tt.expr)
+			if err == nil || err.Error() != tt.wantErr {
+				t.Errorf("parse(%q) error = %v, want %q", tt.expr, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestMatch(t *testing.T) {
+	item := &Item{
+		Title:      "Go 1.22 released: range over integers",
+		Categories: []string{"Programming", " Open Source "},
+		Excerpt:    "What's new in C++ and Go this spring.",
+		Source:     "Go Blog",
+		Link:       "https://www.blog.go.dev/go1.22",
+	}
+
+	tests := []struct {
+		expr string
+		want bool
+	}{
+		{"title:go", true},
+		{"title:GO", true},
+		{"title:released", true},
+		{"title:release", false},
+		{"title:release*", true},
+		{"title:integer*", true},
+		{"title:1.22", true},
+		{"title:1x22", false},
+		{`title:"range over"`, true},
+		{`title:"over range"`, false},
+		{"excerpt:c++", true},
+		{"excerpt:c", true},
+		{"title:c++", false},
+		{"categories:programming", true},
+		{"categories:program*", true},
+		{"categories:program", false},
+		{`category:"open source"`, true},
+		{"categories:open", false},
+		{`source:"go blog"`, true},
+		{"source:go", false},
+		{"domain:go.dev", true},
+		{"domain:www.go.dev", true},
+		{"domain:blog.go.dev", true},
+		{"domain:o.dev", false},
+		{"domain:dev", true},
+		{"NOT title:go", false},
+		{"NOT NOT title:go", true},
+		{"title:rust OR title:go", true},
+		{"title:rust AND title:go", false},
+		{"title:rust OR title:go AND title:python", false},
+		{"title:go OR title:rust AND title:python", true},
+		{"(title:go OR title:rust) AND title:python", false},
+		{"title:go AND NOT (categories:news OR source:other)", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			n, err := parse(tt.expr)
+			if err != nil {
+				t.Fatalf("parse(%q) error = %v", tt.expr, err)
+			}
+
+			if got := n.match(item); got != tt.want {
+				t.Errorf("%q matches = %v, want %v", tt.expr, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMatchBadLink(t *testing.T) {
+	n, err := parse("domain:example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, link := range []string{"", "::not a url", "/relative/path"} {
+		if n.match(&Item{Link: link}) {
+			t.Errorf("domain matches link %q", link)
+		}
+	}
+}
+
+func TestFromKeywords(t *testing.T) {
+	r := FromKeywords([]string{"Machine Learning", "go"})
+
+	if !strings.Contains(r.Expr, `title:"Machine Learning"`) {
+		t.Errorf("expression %q doesn't quote keywords", r.Expr)
+	}
+
+	n, err := parse(r.Expr)
+	if err != nil {
+		t.Fatalf("parse(%q) error = %v", r.Expr, err)
+	}
+
+	if !n.match(&Item{Title: "Intro to machine learning"}) {
+		t.Error("keyword in title isn't matched")
+	}
+
+	if !n.match(&Item{Categories: []string{"Go"}}) {
+		t.Error("keyword in categories isn't matched")
+	}
+
+	if n.match(&Item{Title: "Machine", Excerpt: "go"}) {
+		t.Error("item without keywords is matched")
+	}
+}
+
+func TestFromKeywordsEscapes(t *testing.T) {
+	keywords := []string{`say "hi"`, `c:\go`, `\"`, `"`}
+
+	n, err := parse(FromKeywords(keywords).Expr)
+	if err != nil {
+		t.Fatalf("parse() error = %v", err)
+	}
+
+	for _, keyword := range keywords {
+		if !n.match(&Item{Title: "x " + keyword + " x"}) {
+			t.Errorf("keyword %q isn't matched", keyword)
+		}
+	}
+
+	if n.match(&Item{Title: "say hi"}) {
+		t.Error("keyword is matched without quotes")
+	}
+}
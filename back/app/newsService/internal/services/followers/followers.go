@@ -0,0 +1,91 @@
+package followers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"time"
+
+	"newsWebApp/app/newsService/internal/models"
+	"newsWebApp/app/newsService/internal/services"
+	"newsWebApp/app/newsService/internal/storage"
+)
+
+type FollowerStorage interface {
+	SaveFollower(ctx context.Context, follower models.Follower) error
+	DeleteFollower(ctx context.Context, actorID string) error
+	Followers(ctx context.Context) ([]models.Follower, error)
+}
+
+// Registry keeps ActivityPub followers of the news stream.
+type Registry struct {
+	followers FollowerStorage
+
+	log *slog.Logger
+}
+
+func New(followers FollowerStorage, log *slog.Logger) *Registry {
+	return &Registry{
+		followers: followers,
+		log:       log,
+	}
+}
+
+func (r *Registry) AddFollower(ctx context.Context, actorID string, inbox string, sharedInbox string) error {
+	const op = "services.followers.add_follower"
+
+	if !validURL(actorID) || !validURL(inbox) || (sharedInbox != "" && !validURL(sharedInbox)) {
+		r.log.Debug("Can't add follower", "actor", actorID, "inbox", inbox)
+		return services.ErrInvalidFollower
+	}
+
+	if err := r.followers.SaveFollower(ctx, models.Follower{
+		ActorID:     actorID,
+		Inbox:       inbox,
+		SharedInbox: sharedInbox,
+		CreatedAt:   time.Now(),
+	}); err != nil {
+		r.log.Error("Can't add follower", "actor", actorID, "err", err.Error())
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (r *Registry) RemoveFollower(ctx context.Context, actorID string) error {
+	const op = "services.followers.remove_follower"
+
+	if err := r.followers.DeleteFollower(ctx, actorID); err != nil {
+		if errors.Is(err, storage.ErrFollowerNotFound) {
+			r.log.Debug("Can't remove follower", "err", err.Error())
+			return services.ErrFollowerNotFound
+		}
+		r.log.Error("Can't remove follower", "actor", actorID, "err", err.Error())
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (r *Registry) ListFollowers(ctx context.Context) ([]models.Follower, error) {
+	const op = "services.followers.list_followers"
+
+	followers, err := r.followers.Followers(ctx)
+	if err != nil {
+		r.log.Error("Can't get followers", "err", err.Error())
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return followers, nil
+}
+
+func validURL(value string) bool {
+	u, err := url.Parse(value)
+	if err != nil {
+		return false
+	}
+
+	return (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
@@ -0,0 +1,50 @@
+package itemHandler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+
+	"newsWebApp/app/newsService/internal/services"
+	"newsWebApp/app/newsService/internal/services/canonical"
+)
+
+// canonicalLink returns url which the page declares as canonical, cleaned of tracking
+// parameters. Page may declare only url of its own site. Redirects are already followed
+// by loader, so final url of the response is used when page doesn't declare it.
+func canonicalLink(resp *http.Response, page []byte, link string) string {
+	final := resp.Request.URL
+
+	if href := canonical.FromPage(final, page); href != "" {
+		if u, err := url.Parse(href); err == nil && canonical.SameSite(u, final) {
+			if canon, err := canonical.Clean(href); err == nil {
+				return canon
+			}
+		}
+	}
+
+	if canon, err := canonical.Clean(final.String()); err == nil {
+		return canon
+	}
+
+	return link
+}
+
+// recacheLink replaces cached link with its canonical one. It returns ErrLinkExists
+// and forgets the link when canonical link is already cached.
+func recacheLink(ctx context.Context, cacher Cacher, canon, link string) error {
+	if canonical.Key(canon) == canonical.Key(link) {
+		return nil
+	}
+
+	if err := cacher.UpdateLink(ctx, canon, link); err != nil {
+		cacher.DeleteLink(ctx, link)
+		if errors.Is(err, services.ErrLinkExists) {
+			return services.ErrLinkExists
+		}
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,50 @@
+package itemHandler
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+func TestCanonicalLink(t *testing.T) {
+	tests := []struct {
+		name  string
+		final string
+		page  string
+		want  string
+	}{
+		{
+			name:  "canonical of the same site",
+			final: "https://www.example.com/a?utm_source=x",
+			page:  `<head><link rel="canonical" href="https://blog.example.com/post/"></head>`,
+			want:  "https://blog.example.com/post/",
+		},
+		{
+			name:  "canonical of other site",
+			final: "https://example.com/a",
+			page:  `<head><link rel="canonical" href="https://evil.net/a"></head>`,
+			want:  "https://example.com/a",
+		},
+		{
+			name:  "no canonical",
+			final: "http://m.example.com/a/?utm_medium=y&id=1#top",
+			page:  `<p>text</p>`,
+			want:  "http://m.example.com/a/?id=1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			final, err := url.Parse(tt.final)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			resp := &http.Response{Request: &http.Request{URL: final}}
+
+			if got := canonicalLink(resp, []byte(tt.page), "https://example.com/a"); got != tt.want {
+				t.Errorf("canonicalLink() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,33 @@
+package itemHandler
+
+import (
+	"context"
+	"fmt"
+
+	"newsWebApp/app/newsService/internal/models"
+	"newsWebApp/app/newsService/internal/services"
+	"newsWebApp/app/newsService/internal/services/canonical"
+)
+
+// Preview loads item of the link like User does, but neither caches the link
+// nor checks whether it's already known.
+func Preview(ctx context.Context, loader Loader, maxBodySize int64, link string) (models.Item, error) {
+	const op = "services.handler.preview"
+
+	normalized, err := canonical.Normalize(link)
+	if err != nil {
+		return models.Item{}, services.ErrInvalidUrl
+	}
+
+	resp, page, err := downloadPage(ctx, loader, maxBodySize, link)
+	if err != nil {
+		return models.Item{}, err
+	}
+
+	itm, err := itemFromPage(resp, page, canonicalLink(resp, page, normalized), link)
+	if err != nil {
+		return models.Item{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return itm, nil
+}
@@ -1,18 +1,23 @@
 package itemHandler
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"fmt"
+	"io"
 	"log/slog"
 	"net"
 	"net/http"
-	"net/url"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"newsWebApp/app/newsService/internal/models"
 	"newsWebApp/app/newsService/internal/services"
+	"newsWebApp/app/newsService/internal/services/canonical"
 
 	"github.com/SlyMarbo/rss"
 	"github.com/go-shiori/go-readability"
@@ -24,20 +29,41 @@ type Cacher interface {
 	DeleteLink(context.Context, string) error
 }
 
+// Loader sends requests through the shared pool of connections.
+type Loader interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
 type RSS struct {
 	cacher Cacher
+	loader Loader
+	budget int
 
 	sourceURL  string
 	sourceID   int64
 	sourceName string
+
+	etag         string
+	lastModified string
+	contentHash  string
+
+	// incomplete is set when some new items of the load weren't handled. Validators
+	// of such load aren't kept, so the next load gets these items again.
+	incomplete atomic.Bool
 }
 
-func NewFromRSS(cacher Cacher, m models.Source) *RSS {
+// NewFromRSS returns handler of the rss source which takes at most budget new items of the feed per load.
+func NewFromRSS(cacher Cacher, loader Loader, budget int, m models.Source) *RSS {
 	return &RSS{
-		cacher:     cacher,
-		sourceURL:  m.FeedURL,
-		sourceID:   m.ID,
-		sourceName: m.Name,
+		cacher:       cacher,
+		loader:       loader,
+		budget:       budget,
+		sourceURL:    m.FeedURL,
+		sourceID:     m.ID,
+		sourceName:   m.Name,
+		etag:         m.ETag,
+		lastModified: m.LastModified,
+		contentHash:  m.ContentHash,
 	}
 }
 
@@ -45,6 +71,32 @@ func (s *RSS) SourceName() string {
 	return s.sourceName
 }
 
+// FeedState returns source with validators of the last successfully loaded feed.
+// Validators are empty when the load is incomplete.
+func (s *RSS) FeedState() models.Source {
+	if s.incomplete.Load() {
+		return models.Source{
+			ID:      s.sourceID,
+			Name:    s.sourceName,
+			FeedURL: s.sourceURL,
+		}
+	}
+
+	return models.Source{
+		ID:           s.sourceID,
+		Name:         s.sourceName,
+		FeedURL:      s.sourceURL,
+		ETag:         s.etag,
+		LastModified: s.lastModified,
+		ContentHash:  s.contentHash,
+	}
+}
+
+// MarkIncomplete tells that some new item of the load wasn't handled.
+func (s *RSS) MarkIncomplete() {
+	s.incomplete.Store(true)
+}
+
 func (s *RSS) IntervalLoad(ctx context.Context, slog *slog.Logger, ich chan models.Item) error {
 	const op = "services.source.rss.interval_load"
 
@@ -52,51 +104,81 @@ func (s *RSS) IntervalLoad(ctx context.Context, slog *slog.Logger, ich chan mode
 
 	feed, err := s.loadFeed(ctx, s.sourceURL)
 	if err != nil {
+		if errors.Is(err, services.ErrFeedNotModified) {
+			return services.ErrFeedNotModified
+		}
 		return fmt.Errorf("%s: failed load rss feed: %w", op, err)
 	}
 
 	wg := new(sync.WaitGroup)
+	taken := 0
 
 	for _, rssItem := range feed.Items {
-		time.Sleep(50 * time.Millisecond)
+		if s.budget > 0 && taken == s.budget {
+			// Rest of new items will be taken on the next load, so feed mustn't be treated as not modified.
+			s.MarkIncomplete()
+			break
+		}
+
+		link, err := canonical.Normalize(rssItem.Link)
+		if err != nil {
+			slog.Debug("Can't normalize link", "link", rssItem.Link, "err", err.Error())
+			continue
+		}
+
+		if err := s.cacher.CacheLink(ctx, link); err != nil {
+			if !errors.Is(err, services.ErrLinkExists) {
+				slog.Debug("Can't save link in cache", "err", err.Error())
+			}
+			continue
+		}
+
+		taken++
 		wg.Add(1)
 
-		go func(rssItem *rss.Item) {
+		go func(rssItem *rss.Item, link string) {
 			defer wg.Done()
-			if err := s.cacher.CacheLink(ctx, rssItem.Link); err != nil {
-				if errors.Is(err, services.ErrLinkExists) {
-					return
-				} else {
-					slog.Debug("Can't save link in cache", "err", err.Error())
-					return
-				}
-			}
 
 			itm := models.Item{
-				Title:      rssItem.Title,
-				Categories: rssItem.Categories,
-				Link:       rssItem.Link,
-				Date:       rssItem.Date.UTC(),
+				Title:        rssItem.Title,
+				Categories:   rssItem.Categories,
+				Link:         link,
+				OriginalLink: rssItem.Link,
+				Date:         rssItem.Date.UTC(),
 			}
 
-			resp, err := getResp(itm.Link)
+			resp, err := getResp(ctx, s.loader, rssItem.Link)
 			if err != nil {
-				s.cacher.DeleteLink(ctx, rssItem.Link)
+				s.cacher.DeleteLink(ctx, link)
+				s.MarkIncomplete()
 				slog.Debug("Failed to get response", "err", err.Error())
 				return
 			}
-			defer resp.Body.Close()
 
-			parsedURL, err := url.Parse(itm.Link)
+			// Body is closed right away, so the slot of downloader isn't held while the page is parsed.
+			page, err := io.ReadAll(resp.Body)
+			resp.Body.Close()
 			if err != nil {
-				s.cacher.DeleteLink(ctx, rssItem.Link)
-				slog.Debug("Failed to parse link", "err", err.Error())
+				s.cacher.DeleteLink(ctx, link)
+				s.MarkIncomplete()
+				slog.Debug("Failed to read body", "err", err.Error())
+				return
+			}
+
+			itm.Link = canonicalLink(resp, page, link)
+
+			if err := recacheLink(ctx, s.cacher, itm.Link, link); err != nil {
+				if !errors.Is(err, services.ErrLinkExists) {
+					s.MarkIncomplete()
+					slog.Debug("Can't save canonical link in cache", "err", err.Error())
+				}
 				return
 			}
 
-			article, err := readability.FromReader(resp.Body, parsedURL)
+			article, err := readability.FromReader(bytes.NewReader(page), resp.Request.URL)
 			if err != nil {
-				s.cacher.DeleteLink(ctx, rssItem.Link)
+				s.cacher.DeleteLink(ctx, itm.Link)
+				s.MarkIncomplete()
 				slog.Debug("Failed to parse body", "err", err.Error())
 				return
 			}
@@ -107,6 +189,10 @@ func (s *RSS) IntervalLoad(ctx context.Context, slog *slog.Logger, ich chan mode
 
 			itm.SourceName = article.SiteName
 			itm.Excerpt = article.Excerpt
+			itm.Text = article.TextContent
+			itm.HTML = article.Content
+			itm.Byline = article.Byline
+			itm.Language = article.Language
 
 			if article.Image != "" {
 				if article.Image[0] != 'h' {
@@ -116,8 +202,12 @@ func (s *RSS) IntervalLoad(ctx context.Context, slog *slog.Logger, ich chan mode
 
 			itm.ImageURL = article.Image
 
-			ich <- itm
-		}(rssItem)
+			select {
+			case ich <- itm:
+			case <-ctx.Done():
+				s.cacher.DeleteLink(ctx, itm.Link)
+			}
+		}(rssItem, link)
 	}
 
 	wg.Wait()
@@ -132,7 +222,12 @@ func (s *RSS) loadFeed(ctx context.Context, url string) (*rss.Feed, error) {
 	for i := 1; i <= 3; i++ {
 		feed, err = s.fetch(ctx, url)
 		if err != nil {
-			time.Sleep(time.Duration(i) * time.Second)
+			if errors.Is(err, services.ErrFeedNotModified) {
+				return nil, err
+			}
+			if err := sleep(ctx, time.Duration(i)*time.Second); err != nil {
+				return nil, err
+			}
 		} else {
 			break
 		}
@@ -145,42 +240,78 @@ func (s *RSS) loadFeed(ctx context.Context, url string) (*rss.Feed, error) {
 	return feed, nil
 }
 
+// fetch makes conditional request for feed and returns ErrFeedNotModified
+// when server answers 304 or feed body has the same hash as before.
 func (s *RSS) fetch(ctx context.Context, url string) (*rss.Feed, error) {
 	const op = "services.source.rss.load_feed"
 
-	var feedCh = make(chan *rss.Feed)
-	var errCh = make(chan error)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
 
-	go func() {
-		feed, err := rss.Fetch(url)
-		if err != nil {
-			errCh <- err
-			return
-		}
+	if s.etag != "" {
+		req.Header.Set("If-None-Match", s.etag)
+	}
 
-		feedCh <- feed
-	}()
+	if s.lastModified != "" {
+		req.Header.Set("If-Modified-Since", s.lastModified)
+	}
 
-	select {
-	case <-ctx.Done():
-		return nil, fmt.Errorf("%s: %w", op, ctx.Err())
-	case err := <-errCh:
+	resp, err := s.loader.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotModified:
+		return nil, services.ErrFeedNotModified
+	default:
+		return nil, fmt.Errorf("%s: unexpected status: %s", op, resp.Status)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
 		return nil, fmt.Errorf("%s: %w", op, err)
-	case feed := <-feedCh:
-		return feed, nil
 	}
+
+	sum := sha256.Sum256(body)
+	hash := hex.EncodeToString(sum[:])
+
+	// Validators of the response are kept only when its body is known to be a feed,
+	// otherwise the retry of a malformed feed would be answered with 304.
+	etag := resp.Header.Get("ETag")
+	lastModified := resp.Header.Get("Last-Modified")
+
+	if hash == s.contentHash {
+		s.etag, s.lastModified = etag, lastModified
+		return nil, services.ErrFeedNotModified
+	}
+
+	feed, err := rss.Parse(body)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	s.etag, s.lastModified, s.contentHash = etag, lastModified, hash
+
+	return feed, nil
 }
 
-func getResp(link string) (*http.Response, error) {
+func getResp(ctx context.Context, loader Loader, link string) (*http.Response, error) {
 	var err error
 	var resp *http.Response
 
 	for i := 1; i <= 3; i++ {
-		resp, err = httpGet(link)
+		resp, err = httpGet(ctx, loader, link)
 		if err != nil {
 			e, ok := err.(net.Error)
-			if ok && e.Timeout() {
-				time.Sleep(time.Duration(i) * time.Second)
+			if ok && e.Timeout() && ctx.Err() == nil {
+				if err := sleep(ctx, time.Duration(i)*time.Second); err != nil {
+					return nil, err
+				}
 			} else {
 				return nil, err
 			}
@@ -196,27 +327,29 @@ func getResp(link string) (*http.Response, error) {
 	return resp, nil
 }
 
-func httpGet(url string) (*http.Response, error) {
-	transport := &http.Transport{
-		DialContext: (&net.Dialer{
-			Timeout:   10 * time.Second,
-			KeepAlive: 10 * time.Second,
-			DualStack: true,
-		}).DialContext,
-		IdleConnTimeout:       60 * time.Second,
-		TLSHandshakeTimeout:   5 * time.Second,
-		ExpectContinueTimeout: 1 * time.Second,
+func httpGet(ctx context.Context, loader Loader, url string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
 	}
 
-	client := &http.Client{
-		Timeout:   6 * time.Second,
-		Transport: transport,
-	}
+	return loader.Do(req)
+}
 
-	req, err := http.NewRequest(http.MethodGet, url, nil)
-	if err != nil {
-		return nil, err
+// sleep pauses before the next retry. It returns ctx error when ctx is done
+// or its deadline comes earlier than the pause ends.
+func sleep(ctx context.Context, d time.Duration) error {
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < d {
+		return context.DeadlineExceeded
 	}
 
-	return client.Do(req)
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
 }
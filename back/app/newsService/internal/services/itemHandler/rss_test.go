@@ -0,0 +1,231 @@
+package itemHandler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"newsWebApp/app/newsService/internal/models"
+	"newsWebApp/app/newsService/internal/services"
+	"newsWebApp/app/newsService/internal/services/canonical"
+)
+
+const testFeed = `<?xml version="1.0"?>
+<rss version="2.0"><channel><title>Test</title><link>https://example.com/</link>
+<item><title>A</title><link>https://example.com/a</link></item>
+</channel></rss>`
+
+func TestFetchMalformedFeedKeepsValidators(t *testing.T) {
+	var calls atomic.Int32
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("ETag", `"v2"`)
+		w.Header().Set("Last-Modified", "Mon, 02 Jan 2006 15:04:05 GMT")
+
+		if calls.Add(1) == 1 {
+			w.Write([]byte("<html>not a feed"))
+			return
+		}
+
+		if r.Header.Get("If-None-Match") == `"v2"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+
+		w.Write([]byte(testFeed))
+	}))
+	defer srv.Close()
+
+	s := NewFromRSS(nil, srv.Client(), 0, models.Source{ID: 1, FeedURL: srv.URL, ETag: `"v1"`})
+
+	if _, err := s.fetch(context.Background(), srv.URL); err == nil || errors.Is(err, services.ErrFeedNotModified) {
+		t.Fatalf("fetch() of malformed feed error = %v, want parse error", err)
+	}
+
+	if got := s.FeedState(); got.ETag != `"v1"` || got.LastModified != "" || got.ContentHash != "" {
+		t.Fatalf("FeedState() after malformed feed = %+v, want old validators", got)
+	}
+
+	feed, err := s.fetch(context.Background(), srv.URL)
+	if err != nil {
+		t.Fatalf("fetch() on retry error = %v", err)
+	}
+
+	if len(feed.Items) != 1 {
+		t.Errorf("fetch() items = %d, want 1", len(feed.Items))
+	}
+
+	got := s.FeedState()
+	if got.ETag != `"v2"` || got.LastModified == "" || got.ContentHash == "" {
+		t.Errorf("FeedState() after parsed feed = %+v, want new validators", got)
+	}
+
+	if _, err := s.fetch(context.Background(), srv.URL); !errors.Is(err, services.ErrFeedNotModified) {
+		t.Errorf("fetch() with new validators error = %v, want ErrFeedNotModified", err)
+	}
+}
+
+func TestFetchSameBodyIsNotModified(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(testFeed))
+	}))
+	defer srv.Close()
+
+	s := NewFromRSS(nil, srv.Client(), 0, models.Source{ID: 1, FeedURL: srv.URL})
+
+	if _, err := s.fetch(context.Background(), srv.URL); err != nil {
+		t.Fatalf("first fetch() error = %v", err)
+	}
+
+	if _, err := s.fetch(context.Background(), srv.URL); !errors.Is(err, services.ErrFeedNotModified) {
+		t.Errorf("second fetch() error = %v, want ErrFeedNotModified", err)
+	}
+}
+
+// fakeCacher keeps cached links in memory. updateErr fails replacing of links.
+type fakeCacher struct {
+	mu        sync.Mutex
+	links     map[string]bool
+	updateErr error
+}
+
+func (c *fakeCacher) CacheLink(ctx context.Context, link string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.links[link] {
+		return services.ErrLinkExists
+	}
+	c.links[link] = true
+
+	return nil
+}
+
+func (c *fakeCacher) UpdateLink(ctx context.Context, newLink string, oldLink string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.updateErr != nil {
+		return c.updateErr
+	}
+
+	if c.links[newLink] {
+		return services.ErrLinkExists
+	}
+	delete(c.links, oldLink)
+	c.links[newLink] = true
+
+	return nil
+}
+
+func (c *fakeCacher) DeleteLink(ctx context.Context, link string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.links, link)
+
+	return nil
+}
+
+func TestIntervalLoadBudget(t *testing.T) {
+	mux := http.NewServeMux()
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	mux.HandleFunc("/feed", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("ETag", `"v1"`)
+		fmt.Fprintf(w, `<?xml version="1.0"?><rss version="2.0"><channel><title>Test</title><link>%[1]s</link>
+<item><title>A</title><link>%[1]s/a</link></item>
+<item><title>B</title><link>%[1]s/b</link></item>
+<item><title>C</title><link>%[1]s/c</link></item>
+<item><title>D</title><link>%[1]s/d</link></item>
+</channel></rss>`, srv.URL)
+	})
+
+	// Page d declares other canonical url.
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		canon := ""
+		if r.URL.Path == "/d" {
+			canon = fmt.Sprintf(`<link rel="canonical" href="%s/story/d">`, srv.URL)
+		}
+
+		fmt.Fprintf(w, `<html><head><title>Page %[1]s</title>%[2]s</head><body><article><h1>Page %[1]s</h1>
+<p>Long enough text of the page %[1]s, so readability takes it as the content of the article.</p>
+</article></body></html>`, r.URL.Path, canon)
+	})
+
+	load := func(budget int, updateErr error, cached ...string) (*RSS, *fakeCacher, []models.Item) {
+		cacher := &fakeCacher{links: map[string]bool{}, updateErr: updateErr}
+		for _, path := range cached {
+			link, _ := canonical.Normalize(srv.URL + path)
+			cacher.links[link] = true
+		}
+
+		s := NewFromRSS(cacher, srv.Client(), budget, models.Source{ID: 1, Name: "test", FeedURL: srv.URL + "/feed"})
+
+		ich := make(chan models.Item, 10)
+		if err := s.IntervalLoad(context.Background(), slog.New(slog.NewTextHandler(io.Discard, nil)), ich); err != nil {
+			t.Fatalf("IntervalLoad() error = %v", err)
+		}
+
+		items := []models.Item{}
+		for itm := range ich {
+			items = append(items, itm)
+		}
+
+		return s, cacher, items
+	}
+
+	t.Run("budget counts new items only", func(t *testing.T) {
+		s, cacher, items := load(2, nil, "/a")
+
+		titles := map[string]bool{}
+		for _, itm := range items {
+			titles[itm.Title] = true
+		}
+
+		if len(items) != 2 || !titles["B"] || !titles["C"] {
+			t.Errorf("loaded items = %v, want B and C", titles)
+		}
+
+		link, _ := canonical.Normalize(srv.URL + "/d")
+		if cacher.links[link] {
+			t.Error("item over budget is cached")
+		}
+
+		if got := s.FeedState(); got.ETag != "" || got.ContentHash != "" {
+			t.Errorf("FeedState() of incomplete load = %+v, want no validators", got)
+		}
+	})
+
+	t.Run("no budget", func(t *testing.T) {
+		s, _, items := load(0, nil)
+
+		if len(items) != 4 {
+			t.Errorf("loaded items = %d, want 4", len(items))
+		}
+
+		if got := s.FeedState(); got.ETag != `"v1"` || got.ContentHash == "" {
+			t.Errorf("FeedState() of complete load = %+v, want validators", got)
+		}
+	})
+
+	t.Run("cache error", func(t *testing.T) {
+		s, _, items := load(0, errors.New("connection refused"))
+
+		if len(items) != 3 {
+			t.Errorf("loaded items = %d, want 3", len(items))
+		}
+
+		if got := s.FeedState(); got.ETag != "" || got.ContentHash != "" {
+			t.Errorf("FeedState() of load with cache error = %+v, want no validators", got)
+		}
+	})
+}
@@ -1,137 +1,156 @@
 package itemHandler
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
-	"net/url"
+	"io"
+	"mime"
+	"net/http"
 	"strings"
 	"time"
 
 	"newsWebApp/app/newsService/internal/models"
 	"newsWebApp/app/newsService/internal/services"
+	"newsWebApp/app/newsService/internal/services/canonical"
+	"newsWebApp/app/newsService/internal/services/downloader"
 
 	"github.com/go-shiori/go-readability"
 )
 
 type User struct {
 	cacher Cacher
+	loader Loader
 
-	userID   int64
-	userLink string
+	maxBodySize int64
+	userID      int64
+	userLink    string
 }
 
-func NewFromUser(cacher Cacher, userID int64, link string) *User {
+func NewFromUser(cacher Cacher, loader Loader, maxBodySize int64, userID int64, link string) *User {
 	return &User{
-		cacher:   cacher,
-		userID:   userID,
-		userLink: link,
+		cacher:      cacher,
+		loader:      loader,
+		maxBodySize: maxBodySize,
+		userID:      userID,
+		userLink:    link,
 	}
 }
 
 func (u *User) LoadItem(ctx context.Context) (models.Item, error) {
 	const op = "services.handler.user.load_from_user"
 
-	itm := models.Item{}
+	link, err := canonical.Normalize(u.userLink)
+	if err != nil {
+		return models.Item{}, services.ErrInvalidUrl
+	}
 
-	if err := u.cacher.CacheLink(ctx, u.userLink); err != nil {
+	if err := u.cacher.CacheLink(ctx, link); err != nil {
 		if errors.Is(err, services.ErrLinkExists) {
-			return itm, services.ErrArticleExists
+			return models.Item{}, services.ErrArticleExists
 		} else {
-			return itm, fmt.Errorf("%s: %w", op, err)
+			return models.Item{}, fmt.Errorf("%s: %w", op, err)
 		}
 	}
 
-	resp, err := getResp(u.userLink)
+	return u.loadPage(ctx, op, link)
+}
+
+func (u *User) UpdateItem(ctx context.Context, oldLink string) (models.Item, error) {
+	const op = "services.handler.user.update_from_user"
+
+	link, err := canonical.Normalize(u.userLink)
 	if err != nil {
-		switch {
-		case strings.Contains(err.Error(), "unsupported protocol scheme"):
-			u.cacher.DeleteLink(ctx, u.userLink)
-			return itm, services.ErrInvalidUrl
-		case strings.Contains(err.Error(), "no such host"):
-			u.cacher.DeleteLink(ctx, u.userLink)
-			return itm, services.ErrInvalidUrl
-		default:
-			u.cacher.DeleteLink(ctx, u.userLink)
-			return itm, fmt.Errorf("%s: failed to download %s: %v", op, u.userLink, err)
-		}
+		return models.Item{}, services.ErrInvalidUrl
 	}
-	defer resp.Body.Close()
 
-	parsedURL, err := url.Parse(u.userLink)
-	if err != nil {
-		u.cacher.DeleteLink(ctx, u.userLink)
-		return itm, fmt.Errorf("%s: error parsing url %s: %v", op, u.userLink, err)
+	if err := u.cacher.UpdateLink(ctx, link, oldLink); err != nil {
+		if errors.Is(err, services.ErrLinkExists) {
+			return models.Item{}, services.ErrArticleExists
+		} else {
+			return models.Item{}, fmt.Errorf("%s: %w", op, err)
+		}
 	}
 
-	article, err := readability.FromReader(resp.Body, parsedURL)
+	return u.loadPage(ctx, op, link)
+}
+
+// loadPage downloads the page of cached normalized link and replaces it in cache
+// with the canonical link of the page.
+func (u *User) loadPage(ctx context.Context, op string, link string) (models.Item, error) {
+	resp, page, err := downloadPage(ctx, u.loader, u.maxBodySize, u.userLink)
 	if err != nil {
-		u.cacher.DeleteLink(ctx, u.userLink)
-		return itm, fmt.Errorf("%s: failed to parse %s: %v", op, u.userLink, err)
+		u.cacher.DeleteLink(ctx, link)
+		return models.Item{}, err
 	}
 
-	itm.Title = article.Title
-	itm.Link = u.userLink
-	itm.Date = time.Now().UTC()
-	itm.Excerpt = article.Excerpt
-	itm.SourceName = article.SiteName
+	canon := canonicalLink(resp, page, link)
 
-	if article.Image != "" {
-		if article.Image[0] != 'h' {
-			article.Image = ""
+	if err := recacheLink(ctx, u.cacher, canon, link); err != nil {
+		if errors.Is(err, services.ErrLinkExists) {
+			return models.Item{}, services.ErrArticleExists
 		}
+		return models.Item{}, fmt.Errorf("%s: %w", op, err)
 	}
 
-	itm.ImageURL = article.Image
+	itm, err := itemFromPage(resp, page, canon, u.userLink)
+	if err != nil {
+		u.cacher.DeleteLink(ctx, canon)
+		return models.Item{}, fmt.Errorf("%s: %w", op, err)
+	}
 
 	return itm, nil
 }
 
-func (u *User) UpdateItem(ctx context.Context, oldLink string) (models.Item, error) {
-	const op = "services.handler.user.update_from_user"
-
-	itm := models.Item{}
-
-	if err := u.cacher.UpdateLink(ctx, u.userLink, oldLink); err != nil {
-		if errors.Is(err, services.ErrLinkExists) {
-			return itm, services.ErrArticleExists
-		} else {
-			return itm, fmt.Errorf("%s: %w", op, err)
-		}
-	}
+// downloadPage returns html page of the link and response it came with.
+// Body of the response is already read and closed.
+func downloadPage(ctx context.Context, loader Loader, maxBodySize int64, link string) (*http.Response, []byte, error) {
+	const op = "services.handler.user.download_page"
 
-	resp, err := getResp(u.userLink)
+	resp, err := getResp(ctx, loader, link)
 	if err != nil {
 		switch {
 		case strings.Contains(err.Error(), "unsupported protocol scheme"):
-			u.cacher.DeleteLink(ctx, u.userLink)
-			return itm, services.ErrInvalidUrl
+			return nil, nil, services.ErrInvalidUrl
 		case strings.Contains(err.Error(), "no such host"):
-			u.cacher.DeleteLink(ctx, u.userLink)
-			return itm, services.ErrInvalidUrl
+			return nil, nil, services.ErrInvalidUrl
+		case errors.Is(err, downloader.ErrForbiddenAddress):
+			return nil, nil, services.ErrForbiddenUrl
+		case errors.Is(err, downloader.ErrTooManyRedirects):
+			return nil, nil, services.ErrInvalidUrl
 		default:
-			u.cacher.DeleteLink(ctx, u.userLink)
-			return itm, fmt.Errorf("%s: failed to download %s: %v", op, u.userLink, err)
+			return nil, nil, fmt.Errorf("%s: failed to download %s: %v", op, link, err)
 		}
 	}
 	defer resp.Body.Close()
 
-	parsedURL, err := url.Parse(u.userLink)
+	page, err := readPage(resp, maxBodySize)
 	if err != nil {
-		u.cacher.DeleteLink(ctx, u.userLink)
-		return itm, fmt.Errorf("%s: error parsing url %s: %v", op, u.userLink, err)
+		return nil, nil, err
 	}
 
-	article, err := readability.FromReader(resp.Body, parsedURL)
+	return resp, page, nil
+}
+
+// itemFromPage extracts title, excerpt, text, site name and image of the page.
+func itemFromPage(resp *http.Response, page []byte, link string, originalLink string) (models.Item, error) {
+	itm := models.Item{}
+
+	article, err := readability.FromReader(bytes.NewReader(page), resp.Request.URL)
 	if err != nil {
-		u.cacher.DeleteLink(ctx, u.userLink)
-		return itm, fmt.Errorf("%s: failed to parse %s: %v", op, u.userLink, err)
+		return itm, fmt.Errorf("failed to parse %s: %v", originalLink, err)
 	}
 
 	itm.Title = article.Title
-	itm.Link = u.userLink
+	itm.Link = link
+	itm.OriginalLink = originalLink
 	itm.Date = time.Now().UTC()
 	itm.Excerpt = article.Excerpt
+	itm.Text = article.TextContent
+	itm.HTML = article.Content
+	itm.Byline = article.Byline
+	itm.Language = article.Language
 	itm.SourceName = article.SiteName
 
 	if article.Image != "" {
@@ -145,6 +164,31 @@ func (u *User) UpdateItem(ctx context.Context, oldLink string) (models.Item, err
 	return itm, nil
 }
 
+// readPage returns body of html page which isn't larger than maxBodySize.
+func readPage(resp *http.Response, maxBodySize int64) ([]byte, error) {
+	const op = "services.handler.user.read_page"
+
+	mediaType, _, err := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if err != nil || (mediaType != "text/html" && mediaType != "application/xhtml+xml") {
+		return nil, fmt.Errorf("%w: unsupported content type %q", services.ErrInvalidUrl, resp.Header.Get("Content-Type"))
+	}
+
+	if resp.ContentLength > maxBodySize {
+		return nil, fmt.Errorf("%w: page is larger than %d bytes", services.ErrInvalidUrl, maxBodySize)
+	}
+
+	page, err := io.ReadAll(io.LimitReader(resp.Body, maxBodySize+1))
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to read %s: %v", op, resp.Request.URL, err)
+	}
+
+	if int64(len(page)) > maxBodySize {
+		return nil, fmt.Errorf("%w: page is larger than %d bytes", services.ErrInvalidUrl, maxBodySize)
+	}
+
+	return page, nil
+}
+
 func (u *User) DeleteItem(ctx context.Context, link string) error {
 	const op = "services.handler.user.delete_from_user"
 
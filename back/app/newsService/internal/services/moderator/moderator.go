@@ -0,0 +1,116 @@
+package moderator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"newsWebApp/app/newsService/internal/models"
+	"newsWebApp/app/newsService/internal/services"
+	"newsWebApp/app/newsService/internal/storage"
+)
+
+const (
+	defaultLimit = 50
+	maxLimit     = 200
+	maxNoteLen   = 1000
+)
+
+type SubmissionStorage interface {
+	PendingSubmissions(ctx context.Context, limit int) ([]models.Article, error)
+	ModerateSubmission(ctx context.Context, artID int64, status string, note string, moderator string) (*models.Article, error)
+	ReleaseDuplicates(ctx context.Context, headID int64) error
+}
+
+// Moderator keeps articles offered by users out of posting until they are approved.
+// Submission goes from pending to approved or rejected, approved one is posted by scheduler.
+type Moderator struct {
+	submissions SubmissionStorage
+
+	log *slog.Logger
+}
+
+func New(submissions SubmissionStorage, log *slog.Logger) *Moderator {
+	return &Moderator{
+		submissions: submissions,
+		log:         log,
+	}
+}
+
+// ListPendingSubmissions returns the oldest pending submissions, limit 0 is the default one.
+func (m *Moderator) ListPendingSubmissions(ctx context.Context, limit int) ([]models.Article, error) {
+	const op = "services.moderator.list_pending_submissions"
+
+	if limit <= 0 {
+		limit = defaultLimit
+	}
+
+	articles, err := m.submissions.PendingSubmissions(ctx, min(limit, maxLimit))
+	if err != nil {
+		m.log.Error("Can't get pending submissions", "err", err.Error())
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return articles, nil
+}
+
+// ApproveSubmission lets the article be posted, note is optional.
+func (m *Moderator) ApproveSubmission(ctx context.Context, artID int64, moderator string, note string) (*models.Article, error) {
+	article, err := m.moderate(ctx, artID, models.StatusApproved, moderator, note)
+	if err != nil {
+		return nil, err
+	}
+
+	m.log.Info("Submission approved", "article id", artID, "moderator", moderator)
+
+	return article, nil
+}
+
+// RejectSubmission keeps the article from posting, reason is shown to the user.
+func (m *Moderator) RejectSubmission(ctx context.Context, artID int64, moderator string, reason string) (*models.Article, error) {
+	if strings.TrimSpace(reason) == "" {
+		return nil, services.ErrReasonRequired
+	}
+
+	article, err := m.moderate(ctx, artID, models.StatusRejected, moderator, reason)
+	if err != nil {
+		return nil, err
+	}
+
+	// Copies of the rejected article from sources may be posted instead.
+	if err := m.submissions.ReleaseDuplicates(ctx, artID); err != nil {
+		m.log.Error("Can't release duplicates of rejected submission", "article id", artID, "err", err.Error())
+	}
+
+	m.log.Info("Submission rejected", "article id", artID, "moderator", moderator)
+
+	return article, nil
+}
+
+func (m *Moderator) moderate(ctx context.Context, artID int64, status string, moderator string, note string) (*models.Article, error) {
+	const op = "services.moderator.moderate"
+
+	note = strings.TrimSpace(note)
+	if len([]rune(note)) > maxNoteLen {
+		note = string([]rune(note)[:maxNoteLen])
+	}
+
+	article, err := m.submissions.ModerateSubmission(ctx, artID, status, note, moderator)
+	if err != nil {
+		switch {
+		case errors.Is(err, storage.ErrSubmissionNotFound):
+			m.log.Debug("Can't moderate submission", "article id", artID, "err", err.Error())
+			return nil, services.ErrSubmissionNotFound
+		case errors.Is(err, storage.ErrNotPending):
+			m.log.Debug("Can't moderate submission", "article id", artID, "err", err.Error())
+			return nil, services.ErrNotPending
+		default:
+			m.log.Error("Can't moderate submission", "article id", artID, "err", err.Error())
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	return article, nil
+}
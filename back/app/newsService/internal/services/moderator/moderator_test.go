@@ -0,0 +1,213 @@
+package moderator
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"newsWebApp/app/newsService/internal/models"
+	"newsWebApp/app/newsService/internal/services"
+	"newsWebApp/app/newsService/internal/storage"
+)
+
+// fakeSubmissions moves statuses of submissions the way the storage does:
+// only pending ones are moderated.
+type fakeSubmissions struct {
+	articles map[int64]*models.Article
+	released []int64
+	err      error
+}
+
+func newFakeSubmissions(articles ...models.Article) *fakeSubmissions {
+	s := &fakeSubmissions{articles: map[int64]*models.Article{}}
+
+	for i := range articles {
+		s.articles[articles[i].ID] = &articles[i]
+	}
+
+	return s
+}
+
+func (s *fakeSubmissions) PendingSubmissions(ctx context.Context, limit int) ([]models.Article, error) {
+	return nil, nil
+}
+
+func (s *fakeSubmissions) ModerateSubmission(ctx context.Context, artID int64, status string, note string, moderator string) (*models.Article, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	article, ok := s.articles[artID]
+	if !ok || article.UserID <= 1 {
+		return nil, storage.ErrSubmissionNotFound
+	}
+
+	if article.Status != models.StatusPending {
+		return nil, storage.ErrNotPending
+	}
+
+	article.Status = status
+	article.ModeratorNote = note
+	article.ModeratedBy = moderator
+
+	moderated := *article
+
+	return &moderated, nil
+}
+
+func (s *fakeSubmissions) ReleaseDuplicates(ctx context.Context, headID int64) error {
+	s.released = append(s.released, headID)
+	return nil
+}
+
+func newTestModerator(submissions *fakeSubmissions) *Moderator {
+	return New(submissions, slog.New(slog.NewTextHandler(io.Discard, nil)))
+}
+
+func TestModerateTransitions(t *testing.T) {
+	type step struct {
+		approve bool
+		note    string
+		status  string
+		err     error
+	}
+
+	tests := []struct {
+		name     string
+		article  models.Article
+		steps    []step
+		released []int64
+	}{
+		{
+			name:    "approve pending",
+			article: models.Article{ID: 1, UserID: 2, Status: models.StatusPending},
+			steps: []step{
+				{approve: true, status: models.StatusApproved},
+			},
+		},
+		{
+			name:    "reject pending releases duplicates",
+			article: models.Article{ID: 1, UserID: 2, Status: models.StatusPending},
+			steps: []step{
+				{note: "spam", status: models.StatusRejected},
+			},
+			released: []int64{1},
+		},
+		{
+			name:    "reject needs reason",
+			article: models.Article{ID: 1, UserID: 2, Status: models.StatusPending},
+			steps: []step{
+				{note: "  ", status: models.StatusPending, err: services.ErrReasonRequired},
+				{approve: true, status: models.StatusApproved},
+			},
+		},
+		{
+			name:    "approved once",
+			article: models.Article{ID: 1, UserID: 2, Status: models.StatusPending},
+			steps: []step{
+				{approve: true, status: models.StatusApproved},
+				{approve: true, status: models.StatusApproved, err: services.ErrNotPending},
+				{note: "changed my mind", status: models.StatusApproved, err: services.ErrNotPending},
+			},
+		},
+		{
+			name:    "rejected once",
+			article: models.Article{ID: 1, UserID: 2, Status: models.StatusPending},
+			steps: []step{
+				{note: "off topic", status: models.StatusRejected},
+				{approve: true, status: models.StatusRejected, err: services.ErrNotPending},
+				{note: "again", status: models.StatusRejected, err: services.ErrNotPending},
+			},
+			released: []int64{1},
+		},
+		{
+			name:    "article from source",
+			article: models.Article{ID: 1, UserID: 1, Status: models.StatusApproved},
+			steps: []step{
+				{note: "not ours", status: models.StatusApproved, err: services.ErrSubmissionNotFound},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			submissions := newFakeSubmissions(tt.article)
+			m := newTestModerator(submissions)
+
+			for i, st := range tt.steps {
+				var err error
+				if st.approve {
+					_, err = m.ApproveSubmission(context.Background(), tt.article.ID, "admin", st.note)
+				} else {
+					_, err = m.RejectSubmission(context.Background(), tt.article.ID, "admin", st.note)
+				}
+
+				if !errors.Is(err, st.err) {
+					t.Fatalf("step %d error = %v, want %v", i, err, st.err)
+				}
+
+				if got := submissions.articles[tt.article.ID].Status; got != st.status {
+					t.Fatalf("step %d status = %q, want %q", i, got, st.status)
+				}
+			}
+
+			if len(submissions.released) != len(tt.released) || (len(tt.released) > 0 && submissions.released[0] != tt.released[0]) {
+				t.Errorf("released groups = %v, want %v", submissions.released, tt.released)
+			}
+		})
+	}
+}
+
+func TestModerateUnknownSubmission(t *testing.T) {
+	submissions := newFakeSubmissions()
+	m := newTestModerator(submissions)
+
+	if _, err := m.ApproveSubmission(context.Background(), 7, "admin", ""); !errors.Is(err, services.ErrSubmissionNotFound) {
+		t.Errorf("ApproveSubmission() error = %v, want ErrSubmissionNotFound", err)
+	}
+
+	if _, err := m.RejectSubmission(context.Background(), 7, "admin", "spam"); !errors.Is(err, services.ErrSubmissionNotFound) {
+		t.Errorf("RejectSubmission() error = %v, want ErrSubmissionNotFound", err)
+	}
+
+	if len(submissions.released) != 0 {
+		t.Errorf("released groups = %v, want none", submissions.released)
+	}
+}
+
+func TestModerateStorageError(t *testing.T) {
+	submissions := newFakeSubmissions(models.Article{ID: 1, UserID: 2, Status: models.StatusPending})
+	submissions.err = errors.New("connection refused")
+
+	m := newTestModerator(submissions)
+
+	_, err := m.RejectSubmission(context.Background(), 1, "admin", "spam")
+	if err == nil || errors.Is(err, services.ErrNotPending) || errors.Is(err, services.ErrSubmissionNotFound) {
+		t.Errorf("RejectSubmission() error = %v, want storage error", err)
+	}
+
+	if len(submissions.released) != 0 {
+		t.Errorf("released groups = %v, want none", submissions.released)
+	}
+}
+
+func TestModerateNote(t *testing.T) {
+	submissions := newFakeSubmissions(models.Article{ID: 1, UserID: 2, Status: models.StatusPending})
+	m := newTestModerator(submissions)
+
+	article, err := m.RejectSubmission(context.Background(), 1, "admin", "  "+strings.Repeat("ж", maxNoteLen+5)+"  ")
+	if err != nil {
+		t.Fatalf("RejectSubmission() error = %v", err)
+	}
+
+	if got := []rune(article.ModeratorNote); len(got) != maxNoteLen || got[0] != 'ж' {
+		t.Errorf("note is %d characters, want trimmed to %d", len(got), maxNoteLen)
+	}
+
+	if article.ModeratedBy != "admin" {
+		t.Errorf("moderated by %q, want admin", article.ModeratedBy)
+	}
+}
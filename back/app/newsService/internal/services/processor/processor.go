@@ -5,25 +5,26 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
-	"time"
 
 	"newsWebApp/app/newsService/internal/models"
 	"newsWebApp/app/newsService/internal/services"
 	"newsWebApp/app/newsService/internal/storage"
+	"newsWebApp/pkg/cursor"
 )
 
 type ArticleStorage interface {
-	NewestNotPosted(ctx context.Context) (*models.Article, error)
 	LatestPosted(ctx context.Context) ([]models.Article, error)
-	LatestPostedWithLimit(ctx context.Context, limit int64) ([]models.Article, error)
-	MarkPosted(ctx context.Context, id int64) (time.Time, error)
+	LatestPostedAfter(ctx context.Context, after models.PageCursor, limit int) ([]models.Article, error)
 	ArticlesByUid(ctx context.Context, userID int64) ([]models.Article, error)
+	ArticleWithContent(ctx context.Context, artID int64) (*models.ReaderArticle, error)
+	SearchArticles(ctx context.Context, query string, after models.SearchCursor, limit int) ([]models.SearchResult, error)
 }
 
 type UserArticleStorage interface {
 	SaveArticleFromUser(ctx context.Context, userID int64, link string) error
 	UpdateArticleByID(ctx context.Context, userID int64, artID int64, link string) error
 	DeleteArticleByID(ctx context.Context, userID int64, artID int64) error
+	ExplainFilter(ctx context.Context, link string, title string, categories []string, source string) (*models.FilterExplanation, error)
 }
 
 type Processor struct {
@@ -80,6 +81,9 @@ func (p *Processor) SaveArticleFromUser(ctx context.Context, userID int64, link
 		case errors.Is(err, services.ErrInvalidUrl):
 			p.log.Debug("Can't save article from user", "err", err.Error())
 			return nil, services.ErrInvalidUrl
+		case errors.Is(err, services.ErrForbiddenUrl):
+			p.log.Debug("Can't save article from user", "err", err.Error())
+			return nil, services.ErrForbiddenUrl
 		default:
 			p.log.Error("Can't save article from user", "err", err.Error())
 			return nil, fmt.Errorf("%s: %w", op, err)
@@ -118,6 +122,9 @@ func (p *Processor) UpdateArticleByID(ctx context.Context, userID int64, artID i
 		case errors.Is(err, services.ErrInvalidUrl):
 			p.log.Debug("Can't update article from user", "err", err.Error())
 			return nil, services.ErrInvalidUrl
+		case errors.Is(err, services.ErrForbiddenUrl):
+			p.log.Debug("Can't update article from user", "err", err.Error())
+			return nil, services.ErrForbiddenUrl
 		default:
 			p.log.Error("Can't update article from user", "err", err.Error())
 			return nil, fmt.Errorf("%s: %w", op, err)
@@ -166,6 +173,43 @@ func (p *Processor) DeleteArticleByID(ctx context.Context, userID int64, artID i
 	return articles, nil
 }
 
+func (p *Processor) ExplainFilter(ctx context.Context, link string, title string, categories []string, source string) (*models.FilterExplanation, error) {
+	const op = "services.processor.explain_filter"
+
+	explanation, err := p.uArt.ExplainFilter(ctx, link, title, categories, source)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrInvalidUrl):
+			return nil, services.ErrInvalidUrl
+		case errors.Is(err, services.ErrForbiddenUrl):
+			return nil, services.ErrForbiddenUrl
+		case errors.Is(err, services.ErrNothingToExplain):
+			return nil, services.ErrNothingToExplain
+		default:
+			p.log.Error("Can't explain filter", "err", err.Error())
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	return explanation, nil
+}
+
+func (p *Processor) GetArticle(ctx context.Context, artID int64) (*models.ReaderArticle, error) {
+	const op = "services.processor.get_article"
+
+	article, err := p.articles.ArticleWithContent(ctx, artID)
+	if err != nil {
+		if errors.Is(err, storage.ErrArticleNotFound) {
+			p.log.Debug("Can't get article", "err", err.Error())
+			return nil, services.ErrArticleNotFound
+		}
+		p.log.Error("Can't get article", "err", err.Error())
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return article, nil
+}
+
 func (p *Processor) SelectPostedArticles(ctx context.Context) ([]models.Article, error) {
 	const op = "services.processor.select_posted_articles"
 
@@ -187,49 +231,56 @@ func (p *Processor) SelectPostedArticles(ctx context.Context) ([]models.Article,
 	return articles, nil
 }
 
-func (p *Processor) SelectPostedArticlesWithLimit(ctx context.Context, page int64) ([]models.Article, error) {
-	const op = "services.processor.select_posted_articles"
+// SelectPostedArticlesAfter returns a page of posted articles following the cursor and
+// cursor of the next page, which is empty on the last one.
+func (p *Processor) SelectPostedArticlesAfter(ctx context.Context, token string) ([]models.Article, string, error) {
+	const op = "services.processor.select_posted_articles_after"
 
-	limit := page * int64(p.pageLimit)
+	pos, err := cursor.DecodePage(token)
+	if err != nil {
+		p.log.Debug("Can't decode page cursor", "err", err.Error())
+		return nil, "", services.ErrInvalidCursor
+	}
 
-	articles, err := p.articles.LatestPostedWithLimit(ctx, limit)
+	// One extra article tells whether there is a next page.
+	articles, err := p.articles.LatestPostedAfter(ctx, models.PageCursor{PostedAt: pos.PostedAt, ID: pos.ID}, p.pageLimit+1)
 	if err != nil || len(articles) == 0 {
 		switch {
 		case len(articles) == 0:
 			p.log.Debug("There are no latest posted articles")
-			return nil, services.ErrNoPublishedArticles
-		case errors.Is(err, storage.ErrNoSources):
-			p.log.Debug("Can't get latest posted articles", "err", err.Error())
-			return nil, services.ErrNoPublishedArticles
+			return nil, "", services.ErrNoPublishedArticles
 		default:
 			p.log.Error("Can't get latest posted articles", "err", err.Error())
-			return nil, fmt.Errorf("%s: %w", op, err)
+			return nil, "", fmt.Errorf("%s: %w", op, err)
 		}
 	}
 
-	return articles, nil
+	var next string
+
+	if len(articles) > p.pageLimit {
+		articles = articles[:p.pageLimit]
+		last := articles[len(articles)-1]
+		next = cursor.EncodePage(cursor.Page{PostedAt: last.PostedAt, ID: last.ID})
+	}
+
+	return articles, next, nil
 }
 
-func (p *Processor) SelectAndSendArticle(ctx context.Context) (*models.Article, error) {
-	const op = "services.processor.select_and_send_article"
+// NewestPostedArticle returns the last posted article. Posting is done by scheduler.
+func (p *Processor) NewestPostedArticle(ctx context.Context) (*models.Article, error) {
+	const op = "services.processor.newest_posted_article"
 
-	article, err := p.articles.NewestNotPosted(ctx)
-	if err != nil {
-		if errors.Is(err, storage.ErrNoNewArticles) {
-			p.log.Debug("Can't get last article", "err", err.Error())
+	articles, err := p.articles.LatestPostedAfter(ctx, models.PageCursor{}, 1)
+	if err != nil || len(articles) == 0 {
+		switch {
+		case len(articles) == 0:
+			p.log.Debug("There are no posted articles")
 			return nil, services.ErrNoNewArticle
+		default:
+			p.log.Error("Can't get newest posted article", "err", err.Error())
+			return nil, fmt.Errorf("%s: %w", op, err)
 		}
-		p.log.Error("Can't get last article", "err", err.Error())
-		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	postedAt, err := p.articles.MarkPosted(ctx, article.ID)
-	if err != nil {
-		p.log.Error("Can't mark article as posted", "err", err.Error())
-		return nil, fmt.Errorf("%s: %w", op, err)
-	}
-
-	article.PostedAt = postedAt
-
-	return article, nil
+	return &articles[0], nil
 }
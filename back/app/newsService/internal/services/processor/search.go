@@ -0,0 +1,52 @@
+package processor
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"newsWebApp/app/newsService/internal/models"
+	"newsWebApp/app/newsService/internal/services"
+	"newsWebApp/pkg/cursor"
+)
+
+// SearchArticles returns a page of posted articles matching the query and cursor of
+// the next page, which is empty on the last one.
+func (p *Processor) SearchArticles(ctx context.Context, query string, token string) ([]models.SearchResult, string, error) {
+	const op = "services.processor.search_articles"
+
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil, "", services.ErrEmptyQuery
+	}
+
+	pos, err := cursor.DecodeSearch(token)
+	if err != nil {
+		p.log.Debug("Can't decode search cursor", "err", err.Error())
+		return nil, "", services.ErrInvalidCursor
+	}
+
+	after := models.SearchCursor{Rank: pos.Rank, ID: pos.ID}
+
+	// One extra result tells whether there is a next page.
+	results, err := p.articles.SearchArticles(ctx, query, after, p.pageLimit+1)
+	if err != nil {
+		p.log.Error("Can't search articles", "err", err.Error())
+		return nil, "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	if len(results) == 0 {
+		p.log.Debug("Nothing found", "query", query)
+		return nil, "", services.ErrNothingFound
+	}
+
+	var next string
+
+	if len(results) > p.pageLimit {
+		results = results[:p.pageLimit]
+		last := results[len(results)-1]
+		next = cursor.EncodeSearch(cursor.Search{Rank: last.Rank, ID: last.Article.ID})
+	}
+
+	return results, next, nil
+}
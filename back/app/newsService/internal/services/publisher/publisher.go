@@ -0,0 +1,239 @@
+package publisher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"newsWebApp/app/newsService/internal/models"
+	"newsWebApp/app/newsService/internal/services"
+	"newsWebApp/app/newsService/internal/storage"
+)
+
+// subscriberBuffer is how many published articles a watcher may lag behind before
+// it's dropped and has to resume.
+const subscriberBuffer = 16
+
+type ArticleStorage interface {
+	LatestPostedAfter(ctx context.Context, after models.PageCursor, limit int) ([]models.Article, error)
+	PostedAfter(ctx context.Context, artID int64) ([]models.Article, error)
+}
+
+type Scheduler interface {
+	Publish(ctx context.Context) (*models.Article, error)
+}
+
+// Publisher asks scheduler to post every check and fans out articles posted by any
+// replica to watchers.
+type Publisher struct {
+	articles  ArticleStorage
+	scheduler Scheduler
+	check     time.Duration
+
+	// lastID is the last article sent to watchers, only Start goroutine uses it.
+	lastID int64
+
+	mu     sync.Mutex
+	subs   map[chan models.Article]struct{}
+	closed bool
+
+	log *slog.Logger
+}
+
+func New(articles ArticleStorage, scheduler Scheduler, check time.Duration, log *slog.Logger) *Publisher {
+	return &Publisher{
+		articles:  articles,
+		scheduler: scheduler,
+		check:     check,
+		subs:      make(map[chan models.Article]struct{}),
+		log:       log,
+	}
+}
+
+// Start publishes articles until ctx is done, then ends all watches.
+func (p *Publisher) Start(ctx context.Context) error {
+	ticker := time.NewTicker(p.check)
+	defer ticker.Stop()
+
+	defer p.closeAll()
+
+	p.lastID = p.newestID(ctx)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			article, err := p.scheduler.Publish(ctx)
+			switch {
+			case err == nil:
+				p.log.Info("Article published", "article id", article.ID, "link", article.Link)
+			case errors.Is(err, services.ErrNotDue), errors.Is(err, services.ErrNoNewArticle):
+				p.log.Debug("Nothing to publish", "err", err.Error())
+			default:
+				p.log.Error("Can't publish article", "err", err.Error())
+			}
+
+			p.broadcastPosted(ctx)
+		}
+	}
+}
+
+// broadcastPosted sends watchers articles posted since the last sent one, including
+// those posted by other replicas.
+func (p *Publisher) broadcastPosted(ctx context.Context) {
+	articles, err := p.articles.PostedAfter(ctx, p.lastID)
+	if err != nil {
+		if errors.Is(err, storage.ErrArticleNotFound) {
+			p.log.Warn("Last sent article is gone", "article id", p.lastID)
+			p.lastID = p.newestID(ctx)
+			return
+		}
+		p.log.Error("Can't get posted articles", "err", err.Error())
+		return
+	}
+
+	for _, article := range articles {
+		p.broadcast(article)
+		p.lastID = article.ID
+	}
+}
+
+func (p *Publisher) newestID(ctx context.Context) int64 {
+	latest, err := p.articles.LatestPostedAfter(ctx, models.PageCursor{}, 1)
+	if err != nil {
+		p.log.Error("Can't get newest posted article", "err", err.Error())
+		return p.lastID
+	}
+
+	if len(latest) == 0 {
+		return 0
+	}
+
+	return latest[0].ID
+}
+
+// Watch sends articles posted after the article with lastID and then every newly
+// published one until ctx is done. Zero lastID skips already posted articles.
+func (p *Publisher) Watch(ctx context.Context, lastID int64, send func(models.Article) error) error {
+	const op = "services.publisher.watch"
+
+	// Subscribe first, so nothing published while the backlog is read is lost.
+	sub := p.subscribe()
+	defer p.unsubscribe(sub)
+
+	var last models.PageCursor
+
+	if lastID > 0 {
+		backlog, err := p.articles.PostedAfter(ctx, lastID)
+		if err != nil {
+			if errors.Is(err, storage.ErrArticleNotFound) {
+				p.log.Debug("Can't resume watch", "last id", lastID, "err", err.Error())
+				return services.ErrArticleNotFound
+			}
+			p.log.Error("Can't get posted articles", "err", err.Error())
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		for _, article := range backlog {
+			if err := send(article); err != nil {
+				return fmt.Errorf("%s: %w", op, err)
+			}
+
+			last = models.PageCursor{PostedAt: article.PostedAt, ID: article.ID}
+		}
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case article, ok := <-sub:
+			if !ok {
+				p.mu.Lock()
+				closed := p.closed
+				p.mu.Unlock()
+
+				if closed {
+					return services.ErrPublisherStopped
+				}
+				return services.ErrWatcherTooSlow
+			}
+
+			if !after(article, last) {
+				continue
+			}
+
+			if err := send(article); err != nil {
+				return fmt.Errorf("%s: %w", op, err)
+			}
+
+			last = models.PageCursor{PostedAt: article.PostedAt, ID: article.ID}
+		}
+	}
+}
+
+func (p *Publisher) subscribe() chan models.Article {
+	sub := make(chan models.Article, subscriberBuffer)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.closed {
+		close(sub)
+		return sub
+	}
+
+	p.subs[sub] = struct{}{}
+
+	return sub
+}
+
+func (p *Publisher) unsubscribe(sub chan models.Article) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if _, ok := p.subs[sub]; ok {
+		delete(p.subs, sub)
+		close(sub)
+	}
+}
+
+// broadcast never blocks publishing, a watcher with full buffer is dropped.
+func (p *Publisher) broadcast(article models.Article) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for sub := range p.subs {
+		select {
+		case sub <- article:
+		default:
+			delete(p.subs, sub)
+			close(sub)
+		}
+	}
+}
+
+func (p *Publisher) closeAll() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.closed = true
+
+	for sub := range p.subs {
+		delete(p.subs, sub)
+		close(sub)
+	}
+}
+
+// after reports whether article was posted after the cursor.
+func after(article models.Article, last models.PageCursor) bool {
+	if !article.PostedAt.Equal(last.PostedAt) {
+		return article.PostedAt.After(last.PostedAt)
+	}
+
+	return article.ID > last.ID
+}
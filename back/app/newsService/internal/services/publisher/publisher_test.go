@@ -0,0 +1,189 @@
+package publisher
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"newsWebApp/app/newsService/internal/models"
+	"newsWebApp/app/newsService/internal/services"
+	"newsWebApp/app/newsService/internal/storage"
+)
+
+var posted = time.Date(2024, 2, 7, 10, 0, 0, 0, time.UTC)
+
+func testArticle(id int64) models.Article {
+	// Two articles share a second, order between them is kept by id.
+	return models.Article{ID: id, PostedAt: posted.Add(time.Duration(id/2) * time.Second)}
+}
+
+// fakeArticles returns backlog of posted articles. onRead runs while the backlog is
+// read, like a publish of another goroutine in the meantime.
+type fakeArticles struct {
+	backlog []models.Article
+	onRead  func()
+}
+
+func (s *fakeArticles) LatestPostedAfter(ctx context.Context, after models.PageCursor, limit int) ([]models.Article, error) {
+	return nil, nil
+}
+
+func (s *fakeArticles) PostedAfter(ctx context.Context, artID int64) ([]models.Article, error) {
+	if artID == 404 {
+		return nil, storage.ErrArticleNotFound
+	}
+
+	if s.onRead != nil {
+		s.onRead()
+	}
+
+	return s.backlog, nil
+}
+
+func newTestPublisher(articles *fakeArticles) *Publisher {
+	return New(articles, nil, time.Hour, slog.New(slog.NewTextHandler(io.Discard, nil)))
+}
+
+// watch runs Watch until want articles are sent or it returns.
+func watch(t *testing.T, p *Publisher, lastID int64, want int, published func()) ([]int64, error) {
+	t.Helper()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	sent := []int64{}
+
+	err := p.Watch(ctx, lastID, func(article models.Article) error {
+		sent = append(sent, article.ID)
+
+		if len(sent) == 1 && published != nil {
+			published()
+		}
+
+		if len(sent) == want {
+			cancel()
+		}
+
+		return nil
+	})
+
+	return sent, err
+}
+
+func TestWatchPublishedDuringReplay(t *testing.T) {
+	articles := &fakeArticles{backlog: []models.Article{testArticle(2), testArticle(3)}}
+	p := newTestPublisher(articles)
+
+	// Article 3 is broadcast while the backlog is read and is in the backlog too,
+	// article 4 is broadcast while the backlog is sent.
+	articles.onRead = func() { p.broadcast(testArticle(3)) }
+
+	sent, err := watch(t, p, 1, 3, func() { p.broadcast(testArticle(4)) })
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Watch() error = %v, want context.Canceled", err)
+	}
+
+	if len(sent) != 3 || sent[0] != 2 || sent[1] != 3 || sent[2] != 4 {
+		t.Errorf("sent articles = %v, want [2 3 4]", sent)
+	}
+}
+
+func TestWatchWithoutBacklog(t *testing.T) {
+	articles := &fakeArticles{backlog: []models.Article{testArticle(2)}}
+	p := newTestPublisher(articles)
+
+	go func() {
+		// Wait for the watcher to subscribe.
+		for {
+			p.mu.Lock()
+			n := len(p.subs)
+			p.mu.Unlock()
+
+			if n > 0 {
+				break
+			}
+			time.Sleep(time.Millisecond)
+		}
+
+		p.broadcast(testArticle(5))
+	}()
+
+	sent, _ := watch(t, p, 0, 1, nil)
+
+	if len(sent) != 1 || sent[0] != 5 {
+		t.Errorf("sent articles = %v, want [5]", sent)
+	}
+}
+
+func TestWatchErrors(t *testing.T) {
+	t.Run("unknown last article", func(t *testing.T) {
+		p := newTestPublisher(&fakeArticles{})
+
+		if _, err := watch(t, p, 404, 1, nil); !errors.Is(err, services.ErrArticleNotFound) {
+			t.Errorf("Watch() error = %v, want ErrArticleNotFound", err)
+		}
+	})
+
+	t.Run("deadline", func(t *testing.T) {
+		p := newTestPublisher(&fakeArticles{})
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		err := p.Watch(ctx, 0, func(models.Article) error { return nil })
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("Watch() error = %v, want context.DeadlineExceeded", err)
+		}
+	})
+
+	t.Run("too slow", func(t *testing.T) {
+		articles := &fakeArticles{}
+		p := newTestPublisher(articles)
+
+		// Buffer of the watcher is overflown before it reads anything.
+		articles.onRead = func() {
+			for i := int64(1); i <= subscriberBuffer+1; i++ {
+				p.broadcast(testArticle(i))
+			}
+		}
+
+		if _, err := watch(t, p, 1, subscriberBuffer+2, nil); !errors.Is(err, services.ErrWatcherTooSlow) {
+			t.Errorf("Watch() error = %v, want ErrWatcherTooSlow", err)
+		}
+	})
+
+	t.Run("stopped", func(t *testing.T) {
+		articles := &fakeArticles{}
+		p := newTestPublisher(articles)
+
+		articles.onRead = p.closeAll
+
+		if _, err := watch(t, p, 1, 1, nil); !errors.Is(err, services.ErrPublisherStopped) {
+			t.Errorf("Watch() error = %v, want ErrPublisherStopped", err)
+		}
+	})
+}
+
+func TestAfter(t *testing.T) {
+	last := models.PageCursor{PostedAt: posted, ID: 5}
+
+	tests := []struct {
+		article models.Article
+		want    bool
+	}{
+		{models.Article{ID: 4, PostedAt: posted.Add(time.Second)}, true},
+		{models.Article{ID: 6, PostedAt: posted}, true},
+		{models.Article{ID: 5, PostedAt: posted}, false},
+		{models.Article{ID: 4, PostedAt: posted}, false},
+		{models.Article{ID: 9, PostedAt: posted.Add(-time.Second)}, false},
+	}
+
+	for _, tt := range tests {
+		if got := after(tt.article, last); got != tt.want {
+			t.Errorf("after(%d at %s) = %t, want %t", tt.article.ID, tt.article.PostedAt, got, tt.want)
+		}
+	}
+}
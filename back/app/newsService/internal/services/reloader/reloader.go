@@ -0,0 +1,176 @@
+package reloader
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"newsWebApp/app/newsService/internal/config"
+	"newsWebApp/app/newsService/internal/models"
+	"newsWebApp/app/newsService/internal/services"
+	"newsWebApp/app/newsService/internal/services/fetcher"
+	"newsWebApp/app/newsService/internal/services/filter"
+)
+
+// watchInterval is how often config file is checked for changes.
+const watchInterval = 5 * time.Second
+
+type FilterStorage interface {
+	Rules(ctx context.Context) ([]models.FilterRule, error)
+}
+
+type Fetcher interface {
+	Apply(settings fetcher.Settings)
+}
+
+// Reloader reads config file again and applies filter rules and fetch interval
+// from it. Invalid config is reported and the current settings stay in use.
+type Reloader struct {
+	path    string
+	filters FilterStorage
+	fetcher Fetcher
+	log     *slog.Logger
+
+	mu      *sync.Mutex
+	modTime time.Time
+}
+
+func New(path string, filters FilterStorage, fetcher Fetcher, log *slog.Logger) *Reloader {
+	r := &Reloader{
+		path:    path,
+		filters: filters,
+		fetcher: fetcher,
+		log:     log,
+		mu:      new(sync.Mutex),
+	}
+
+	if info, err := os.Stat(path); err == nil {
+		r.modTime = info.ModTime()
+	}
+
+	return r
+}
+
+// Settings builds fetcher settings from config. Filter keywords are turned into
+// a rule when there are no rules.
+func Settings(ctx context.Context, cfg *config.Config, filters FilterStorage) (fetcher.Settings, error) {
+	rules := []models.FilterRule{}
+
+	switch cfg.Filter.Storage {
+	case "config":
+		for _, r := range cfg.Filter.Rules {
+			rules = append(rules, models.FilterRule{
+				Name:    r.Name,
+				Action:  r.Action,
+				Expr:    r.Expr,
+				Sources: r.Sources,
+			})
+		}
+	case "db":
+		dbRules, err := filters.Rules(ctx)
+		if err != nil {
+			return fetcher.Settings{}, err
+		}
+		rules = dbRules
+	default:
+		return fetcher.Settings{}, fmt.Errorf("%w: unknown filter storage %q", services.ErrInvalidConfig, cfg.Filter.Storage)
+	}
+
+	if len(rules) == 0 && len(cfg.Manager.FilterKeywords) > 0 {
+		rules = append(rules, filter.FromKeywords(cfg.Manager.FilterKeywords))
+	}
+
+	engine, err := filter.Compile(rules, cfg.Filter.Default)
+	if err != nil {
+		return fetcher.Settings{}, fmt.Errorf("%w: %v", services.ErrInvalidConfig, err)
+	}
+
+	if cfg.Manager.FetchInterval <= 0 {
+		return fetcher.Settings{}, fmt.Errorf("%w: fetch interval must be positive", services.ErrInvalidConfig)
+	}
+
+	return fetcher.Settings{
+		Filter:        engine,
+		FetchInterval: cfg.Manager.FetchInterval,
+	}, nil
+}
+
+// ReloadConfig reads config file and applies new settings when they are valid.
+func (r *Reloader) ReloadConfig(ctx context.Context) (*models.ReloadReport, error) {
+	const op = "services.reloader.reload_config"
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if info, err := os.Stat(r.path); err == nil {
+		r.modTime = info.ModTime()
+	}
+
+	cfg, err := config.Load(r.path)
+	if err != nil {
+		r.log.Warn("Can't reload config, old one is kept", "err", err.Error())
+		return nil, fmt.Errorf("%w: %v", services.ErrInvalidConfig, err)
+	}
+
+	settings, err := Settings(ctx, cfg, r.filters)
+	if err != nil {
+		r.log.Warn("Can't reload config, old one is kept", "err", err.Error())
+		if errors.Is(err, services.ErrInvalidConfig) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	r.fetcher.Apply(settings)
+
+	report := &models.ReloadReport{
+		Rules:         settings.Filter.Len(),
+		FetchInterval: settings.FetchInterval,
+		ReloadedAt:    time.Now().UTC(),
+	}
+
+	r.log.Info("Config reloaded", "rules", report.Rules, "fetch interval", report.FetchInterval.String())
+
+	return report, nil
+}
+
+// Watch reloads config on SIGHUP and when config file is changed.
+func (r *Reloader) Watch(ctx context.Context) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+
+	ticker := time.NewTicker(watchInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-hup:
+			r.ReloadConfig(ctx)
+		case <-ticker.C:
+			if r.changed() {
+				r.ReloadConfig(ctx)
+			}
+		}
+	}
+}
+
+func (r *Reloader) changed() bool {
+	info, err := os.Stat(r.path)
+	if err != nil {
+		return false
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return !info.ModTime().Equal(r.modTime)
+}
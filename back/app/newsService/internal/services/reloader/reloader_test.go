@@ -0,0 +1,246 @@
+package reloader
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"newsWebApp/app/newsService/internal/models"
+	"newsWebApp/app/newsService/internal/services"
+	"newsWebApp/app/newsService/internal/services/fetcher"
+)
+
+// fakeFilters returns rules of filter_rules table.
+type fakeFilters struct {
+	rules []models.FilterRule
+	err   error
+}
+
+func (f fakeFilters) Rules(ctx context.Context) ([]models.FilterRule, error) {
+	return f.rules, f.err
+}
+
+// fakeFetcher records applied settings.
+type fakeFetcher struct {
+	applied []fetcher.Settings
+}
+
+func (f *fakeFetcher) Apply(settings fetcher.Settings) {
+	f.applied = append(f.applied, settings)
+}
+
+const validConfig = `env: local
+news_managment:
+  fetch_interval: 10m
+filter:
+  storage: config
+  rules:
+    - name: go
+      action: include
+      expr: title:go
+`
+
+// writeConfig writes config file to dir and returns its path.
+func writeConfig(t *testing.T, dir string, body string) string {
+	t.Helper()
+
+	path := filepath.Join(dir, "config.yaml")
+
+	if err := os.WriteFile(path, []byte(body), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	return path
+}
+
+func newTestReloader(t *testing.T, path string, filters FilterStorage) (*Reloader, *fakeFetcher) {
+	t.Helper()
+
+	t.Setenv("POSTGRES_PASSWORD", "secret")
+
+	f := &fakeFetcher{}
+
+	return New(path, filters, f, slog.New(slog.NewTextHandler(io.Discard, nil))), f
+}
+
+func TestReloadConfig(t *testing.T) {
+	dbErr := errors.New("connection refused")
+
+	tests := []struct {
+		name     string
+		config   string
+		filters  fakeFilters
+		rules    int
+		interval time.Duration
+		wantErr  error
+	}{
+		{
+			name:     "rules from config",
+			config:   validConfig,
+			rules:    1,
+			interval: 10 * time.Minute,
+		},
+		{
+			name: "rules from db",
+			config: `env: local
+news_managment:
+  fetch_interval: 5m
+filter:
+  storage: db
+`,
+			filters:  fakeFilters{rules: []models.FilterRule{{Name: "a", Action: "include", Expr: "title:a"}, {Name: "b", Action: "exclude", Expr: "title:b"}}},
+			rules:    2,
+			interval: 5 * time.Minute,
+		},
+		{
+			name: "keywords without rules",
+			config: `env: local
+news_managment:
+  fetch_interval: 5m
+  filter_keywords: [go, rust]
+`,
+			rules:    1,
+			interval: 5 * time.Minute,
+		},
+		{
+			name: "invalid rule",
+			config: `env: local
+news_managment:
+  fetch_interval: 10m
+filter:
+  rules:
+    - name: go
+      action: drop
+      expr: title:go
+`,
+			wantErr: services.ErrInvalidConfig,
+		},
+		{
+			name: "unknown filter storage",
+			config: `env: local
+news_managment:
+  fetch_interval: 10m
+filter:
+  storage: redis
+`,
+			wantErr: services.ErrInvalidConfig,
+		},
+		{
+			name: "no fetch interval",
+			config: `env: local
+`,
+			wantErr: services.ErrInvalidConfig,
+		},
+		{
+			name:    "broken file",
+			config:  "env: [local\n",
+			wantErr: services.ErrInvalidConfig,
+		},
+		{
+			name: "db error",
+			config: `env: local
+news_managment:
+  fetch_interval: 10m
+filter:
+  storage: db
+`,
+			filters: fakeFilters{err: dbErr},
+			wantErr: dbErr,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, f := newTestReloader(t, writeConfig(t, t.TempDir(), tt.config), tt.filters)
+
+			report, err := r.ReloadConfig(context.Background())
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ReloadConfig() error = %v, want %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr != nil {
+				if len(f.applied) != 0 {
+					t.Errorf("invalid config is applied %d times, want old settings kept", len(f.applied))
+				}
+				return
+			}
+
+			if len(f.applied) != 1 {
+				t.Fatalf("settings are applied %d times, want once", len(f.applied))
+			}
+
+			if got := f.applied[0]; got.Filter.Len() != tt.rules || got.FetchInterval != tt.interval {
+				t.Errorf("applied %d rules every %s, want %d every %s", got.Filter.Len(), got.FetchInterval, tt.rules, tt.interval)
+			}
+
+			if report.Rules != tt.rules || report.FetchInterval != tt.interval {
+				t.Errorf("report = %+v, want %d rules every %s", *report, tt.rules, tt.interval)
+			}
+		})
+	}
+}
+
+func TestReloadConfigKeepsSettingsOnError(t *testing.T) {
+	dir := t.TempDir()
+	r, f := newTestReloader(t, writeConfig(t, dir, validConfig), fakeFilters{})
+
+	if _, err := r.ReloadConfig(context.Background()); err != nil {
+		t.Fatalf("ReloadConfig() error = %v", err)
+	}
+
+	writeConfig(t, dir, validConfig+"      sources: [blog]\n    - name: bad\n      action: include\n      expr: title:(\n")
+
+	if _, err := r.ReloadConfig(context.Background()); !errors.Is(err, services.ErrInvalidConfig) {
+		t.Fatalf("ReloadConfig() of invalid config error = %v, want ErrInvalidConfig", err)
+	}
+
+	if len(f.applied) != 1 {
+		t.Errorf("settings are applied %d times, want only valid config", len(f.applied))
+	}
+}
+
+func TestChanged(t *testing.T) {
+	dir := t.TempDir()
+	path := writeConfig(t, dir, validConfig)
+	r, _ := newTestReloader(t, path, fakeFilters{})
+
+	if r.changed() {
+		t.Fatal("changed() of untouched file = true")
+	}
+
+	later := time.Now().Add(time.Minute)
+	if err := os.Chtimes(path, later, later); err != nil {
+		t.Fatal(err)
+	}
+
+	if !r.changed() {
+		t.Fatal("changed() after file is modified = false")
+	}
+
+	// Reload remembers modification time, even when config is invalid.
+	writeConfig(t, dir, "env: local\n")
+	if err := os.Chtimes(path, later, later); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := r.ReloadConfig(context.Background()); err == nil {
+		t.Fatal("ReloadConfig() of config without fetch interval error = nil")
+	}
+
+	if r.changed() {
+		t.Error("changed() after reload = true")
+	}
+
+	if err := os.Remove(path); err != nil {
+		t.Fatal(err)
+	}
+
+	if r.changed() {
+		t.Error("changed() of removed file = true")
+	}
+}
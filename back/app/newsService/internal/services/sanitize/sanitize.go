@@ -0,0 +1,191 @@
+package sanitize
+
+import (
+	"net/url"
+	"slices"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// allowed are elements kept in the output with the attributes they may have.
+// Other elements are replaced with their children.
+var allowed = map[atom.Atom][]string{
+	atom.P:          nil,
+	atom.Br:         nil,
+	atom.Hr:         nil,
+	atom.H1:         nil,
+	atom.H2:         nil,
+	atom.H3:         nil,
+	atom.H4:         nil,
+	atom.H5:         nil,
+	atom.H6:         nil,
+	atom.Div:        nil,
+	atom.Span:       nil,
+	atom.Section:    nil,
+	atom.Article:    nil,
+	atom.Blockquote: nil,
+	atom.Pre:        nil,
+	atom.Code:       nil,
+	atom.Em:         nil,
+	atom.Strong:     nil,
+	atom.B:          nil,
+	atom.I:          nil,
+	atom.U:          nil,
+	atom.S:          nil,
+	atom.Sub:        nil,
+	atom.Sup:        nil,
+	atom.Mark:       nil,
+	atom.Ul:         nil,
+	atom.Ol:         nil,
+	atom.Li:         nil,
+	atom.Dl:         nil,
+	atom.Dt:         nil,
+	atom.Dd:         nil,
+	atom.Figure:     nil,
+	atom.Figcaption: nil,
+	atom.Table:      nil,
+	atom.Thead:      nil,
+	atom.Tbody:      nil,
+	atom.Tfoot:      nil,
+	atom.Tr:         {"colspan", "rowspan"},
+	atom.Th:         {"colspan", "rowspan"},
+	atom.Td:         {"colspan", "rowspan"},
+	atom.Caption:    nil,
+	atom.A:          {"href", "title"},
+	atom.Img:        {"src", "alt", "title", "width", "height"},
+}
+
+// dropped are elements removed together with their children.
+var dropped = map[atom.Atom]struct{}{
+	atom.Script:   {},
+	atom.Style:    {},
+	atom.Iframe:   {},
+	atom.Frame:    {},
+	atom.Frameset: {},
+	atom.Object:   {},
+	atom.Embed:    {},
+	atom.Applet:   {},
+	atom.Form:     {},
+	atom.Input:    {},
+	atom.Button:   {},
+	atom.Select:   {},
+	atom.Textarea: {},
+	atom.Noscript: {},
+	atom.Template: {},
+	atom.Svg:      {},
+	atom.Math:     {},
+	atom.Link:     {},
+	atom.Meta:     {},
+	atom.Base:     {},
+	atom.Title:    {},
+}
+
+// urlAttrs are attributes which hold links.
+var urlAttrs = map[string]struct{}{
+	"href": {},
+	"src":  {},
+}
+
+// HTML returns the fragment with only allowed elements and attributes. Links
+// are kept when they are relative or http(s), mailto is kept in href.
+func HTML(fragment string) string {
+	context := &html.Node{Type: html.ElementNode, DataAtom: atom.Body, Data: atom.Body.String()}
+
+	nodes, err := html.ParseFragment(strings.NewReader(fragment), context)
+	if err != nil {
+		return html.EscapeString(fragment)
+	}
+
+	var b strings.Builder
+	for _, n := range nodes {
+		render(&b, n)
+	}
+
+	return b.String()
+}
+
+func render(b *strings.Builder, n *html.Node) {
+	switch n.Type {
+	case html.TextNode:
+		b.WriteString(html.EscapeString(n.Data))
+		return
+	case html.ElementNode:
+	default:
+		return
+	}
+
+	if _, ok := dropped[n.DataAtom]; ok {
+		return
+	}
+
+	attrs, ok := allowed[n.DataAtom]
+	if !ok || n.Namespace != "" {
+		renderChildren(b, n)
+		return
+	}
+
+	b.WriteByte('<')
+	b.WriteString(n.Data)
+
+	for _, a := range n.Attr {
+		if a.Namespace != "" || !slices.Contains(attrs, a.Key) {
+			continue
+		}
+
+		if _, ok := urlAttrs[a.Key]; ok && !safeURL(a.Val, a.Key == "href") {
+			continue
+		}
+
+		b.WriteByte(' ')
+		b.WriteString(a.Key)
+		b.WriteString(`="`)
+		b.WriteString(html.EscapeString(a.Val))
+		b.WriteByte('"')
+	}
+
+	b.WriteByte('>')
+
+	if n.DataAtom == atom.Br || n.DataAtom == atom.Hr || n.DataAtom == atom.Img {
+		return
+	}
+
+	renderChildren(b, n)
+
+	b.WriteString("</")
+	b.WriteString(n.Data)
+	b.WriteByte('>')
+}
+
+func renderChildren(b *strings.Builder, n *html.Node) {
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		render(b, c)
+	}
+}
+
+// safeURL reports whether the link can't run a script. Browsers ignore control
+// characters in schemes, so links with them are never safe.
+func safeURL(link string, mailto bool) bool {
+	link = strings.TrimSpace(link)
+
+	for _, r := range link {
+		if r < 0x20 || r == 0x7f {
+			return false
+		}
+	}
+
+	u, err := url.Parse(link)
+	if err != nil {
+		return false
+	}
+
+	switch strings.ToLower(u.Scheme) {
+	case "", "http", "https":
+		return true
+	case "mailto":
+		return mailto
+	default:
+		return false
+	}
+}
@@ -0,0 +1,75 @@
+package sanitize
+
+import "testing"
+
+func TestHTML(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{
+			name: "allowed markup",
+			in:   `<p>Some <strong>bold</strong> and <a href="https://example.com/a?b=1&amp;c=2" title="t">link</a></p>`,
+			want: `<p>Some <strong>bold</strong> and <a href="https://example.com/a?b=1&amp;c=2" title="t">link</a></p>`,
+		},
+		{
+			name: "event handlers",
+			in:   `<p onclick="alert(1)">text<img src="/a.png" onerror="alert(1)" alt="a"></p>`,
+			want: `<p>text<img src="/a.png" alt="a"></p>`,
+		},
+		{
+			name: "dropped elements",
+			in:   `<p>a</p><script>alert(1)</script><iframe src="https://evil.example"></iframe><form action="/x"><input name="q"><button>go</button></form><style>p{}</style><p>b</p>`,
+			want: `<p>a</p><p>b</p>`,
+		},
+		{
+			name: "unknown elements are unwrapped",
+			in:   `<font color="red">red <blink>text</blink></font>`,
+			want: `red text`,
+		},
+		{
+			name: "script links",
+			in:   `<a href="javascript:alert(1)">a</a><a href="java&#9;script:alert(1)">b</a><a href=" JAVASCRIPT:alert(1)">c</a><img src="data:image/svg+xml,x">`,
+			want: `<a>a</a><a>b</a><a>c</a><img>`,
+		},
+		{
+			name: "mailto only in links",
+			in:   `<a href="mailto:a@example.com">mail</a><img src="mailto:a@example.com">`,
+			want: `<a href="mailto:a@example.com">mail</a><img>`,
+		},
+		{
+			name: "style and class",
+			in:   `<div class="c" style="background:url(javascript:x)" id="x">text</div>`,
+			want: `<div>text</div>`,
+		},
+		{
+			name: "svg",
+			in:   `<svg><a href="javascript:alert(1)"><text>x</text></a></svg>after`,
+			want: `after`,
+		},
+		{
+			name: "text is escaped",
+			in:   `1 &lt; 2 &amp;&amp; <b>"x"</b>`,
+			want: `1 &lt; 2 &amp;&amp; <b>&#34;x&#34;</b>`,
+		},
+		{
+			name: "comments",
+			in:   `<p>a<!-- <script>alert(1)</script> --></p>`,
+			want: `<p>a</p>`,
+		},
+		{
+			name: "unclosed tags",
+			in:   `<ul><li>one<li>two</ul><p>end`,
+			want: `<ul><li>one</li><li>two</li></ul><p>end</p>`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := HTML(tt.in); got != tt.want {
+				t.Errorf("HTML() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,187 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"newsWebApp/app/newsService/internal/models"
+	"newsWebApp/app/newsService/internal/services"
+	"newsWebApp/app/newsService/internal/storage"
+)
+
+type ArticleStorage interface {
+	TryPublishLock(ctx context.Context) (func(), bool, error)
+	LatestPostedAfter(ctx context.Context, after models.PageCursor, limit int) ([]models.Article, error)
+	CountPostedSince(ctx context.Context, since time.Time) (int, error)
+	NewestNotPosted(ctx context.Context, skipSource string) (*models.Article, error)
+	MarkPosted(ctx context.Context, id int64) (time.Time, error)
+}
+
+// Notifier hears about posted articles, such as webhooks and sinks.
+type Notifier interface {
+	ArticlePublished(ctx context.Context, article models.Article)
+}
+
+// Scheduler decides when the next article is posted. All its state is in the
+// database, so any replica may publish and at most one does at a time.
+type Scheduler struct {
+	articles  ArticleStorage
+	notifiers []Notifier
+
+	interval  time.Duration
+	quiet     bool
+	quietFrom time.Duration
+	quietTo   time.Duration
+	loc       *time.Location
+	dailyCap  int
+
+	log *slog.Logger
+}
+
+// New returns scheduler posting not more often than interval and not more than
+// dailyCap articles per day, zero dailyCap is unlimited. Nothing is posted from
+// quietFrom till quietTo ("HH:MM" in timeZone), empty or equal ones turn quiet hours off.
+func New(articles ArticleStorage,
+	notifiers []Notifier,
+	interval time.Duration,
+	quietFrom string,
+	quietTo string,
+	timeZone string,
+	dailyCap int,
+	log *slog.Logger,
+) (*Scheduler, error) {
+	const op = "services.scheduler.new"
+
+	loc, err := time.LoadLocation(timeZone)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	s := &Scheduler{
+		articles:  articles,
+		notifiers: notifiers,
+		interval:  interval,
+		loc:       loc,
+		dailyCap:  dailyCap,
+		log:       log,
+	}
+
+	if quietFrom != "" || quietTo != "" {
+		if s.quietFrom, err = clock(quietFrom); err != nil {
+			return nil, fmt.Errorf("%s: quiet_from: %w", op, err)
+		}
+
+		if s.quietTo, err = clock(quietTo); err != nil {
+			return nil, fmt.Errorf("%s: quiet_to: %w", op, err)
+		}
+
+		s.quiet = s.quietFrom != s.quietTo
+	}
+
+	return s, nil
+}
+
+// Publish posts the next article if it's time to. Articles offered by users go
+// before fetched ones, source of the last post is skipped while others have articles.
+func (s *Scheduler) Publish(ctx context.Context) (*models.Article, error) {
+	const op = "services.scheduler.publish"
+
+	now := time.Now().In(s.loc)
+
+	if s.isQuiet(now) {
+		return nil, services.ErrNotDue
+	}
+
+	unlock, locked, err := s.articles.TryPublishLock(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if !locked {
+		// Another replica is publishing right now.
+		return nil, services.ErrNotDue
+	}
+	defer unlock()
+
+	// State is read under the lock, so a post made by another replica is seen here.
+	latest, err := s.articles.LatestPostedAfter(ctx, models.PageCursor{}, 1)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var lastSource string
+
+	if len(latest) > 0 {
+		if now.Sub(latest[0].PostedAt) < s.interval {
+			return nil, services.ErrNotDue
+		}
+
+		lastSource = latest[0].SourceName
+	}
+
+	if s.dailyCap > 0 {
+		dayStart := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, s.loc)
+
+		posted, err := s.articles.CountPostedSince(ctx, dayStart)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		if posted >= s.dailyCap {
+			return nil, services.ErrNotDue
+		}
+	}
+
+	article, err := s.articles.NewestNotPosted(ctx, lastSource)
+	if errors.Is(err, storage.ErrNoNewArticles) && lastSource != "" {
+		// A source isn't held back when it's the only one with new articles.
+		article, err = s.articles.NewestNotPosted(ctx, "")
+	}
+	if err != nil {
+		if errors.Is(err, storage.ErrNoNewArticles) {
+			return nil, services.ErrNoNewArticle
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	postedAt, err := s.articles.MarkPosted(ctx, article.ID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	article.PostedAt = postedAt
+
+	for _, notifier := range s.notifiers {
+		notifier.ArticlePublished(ctx, *article)
+	}
+
+	return article, nil
+}
+
+// isQuiet reports whether t falls into quiet hours, which may span midnight.
+func (s *Scheduler) isQuiet(t time.Time) bool {
+	if !s.quiet {
+		return false
+	}
+
+	day := time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute
+
+	if s.quietFrom < s.quietTo {
+		return day >= s.quietFrom && day < s.quietTo
+	}
+
+	return day >= s.quietFrom || day < s.quietTo
+}
+
+// clock parses "HH:MM" into time since midnight.
+func clock(value string) (time.Duration, error) {
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, err
+	}
+
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
@@ -0,0 +1,301 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"newsWebApp/app/newsService/internal/models"
+	"newsWebApp/app/newsService/internal/services"
+	"newsWebApp/app/newsService/internal/storage"
+)
+
+// fakeArticles keeps not posted articles in order NewestNotPosted returns them.
+type fakeArticles struct {
+	busy    bool
+	latest  []models.Article
+	posted  int
+	pending []models.Article
+
+	since  time.Time
+	skips  []string
+	marked []int64
+}
+
+func (f *fakeArticles) TryPublishLock(ctx context.Context) (func(), bool, error) {
+	return func() {}, !f.busy, nil
+}
+
+func (f *fakeArticles) LatestPostedAfter(ctx context.Context, after models.PageCursor, limit int) ([]models.Article, error) {
+	return f.latest, nil
+}
+
+func (f *fakeArticles) CountPostedSince(ctx context.Context, since time.Time) (int, error) {
+	f.since = since
+	return f.posted, nil
+}
+
+func (f *fakeArticles) NewestNotPosted(ctx context.Context, skipSource string) (*models.Article, error) {
+	f.skips = append(f.skips, skipSource)
+
+	for _, article := range f.pending {
+		if skipSource == "" || article.SourceName != skipSource {
+			return &article, nil
+		}
+	}
+
+	return nil, storage.ErrNoNewArticles
+}
+
+func (f *fakeArticles) MarkPosted(ctx context.Context, id int64) (time.Time, error) {
+	f.marked = append(f.marked, id)
+	return time.Now().UTC(), nil
+}
+
+type fakeNotifier struct{ published []int64 }
+
+func (n *fakeNotifier) ArticlePublished(ctx context.Context, article models.Article) {
+	n.published = append(n.published, article.ID)
+}
+
+func TestIsQuiet(t *testing.T) {
+	at := func(hour, minute int) time.Time {
+		return time.Date(2024, 5, 1, hour, minute, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name     string
+		from, to string
+		t        time.Time
+		want     bool
+	}{
+		{"same day before", "13:00", "14:30", at(12, 59), false},
+		{"same day from", "13:00", "14:30", at(13, 0), true},
+		{"same day inside", "13:00", "14:30", at(14, 29), true},
+		{"same day to", "13:00", "14:30", at(14, 30), false},
+		{"over midnight before", "22:00", "06:00", at(21, 59), false},
+		{"over midnight from", "22:00", "06:00", at(22, 0), true},
+		{"over midnight at midnight", "22:00", "06:00", at(0, 0), true},
+		{"over midnight morning", "22:00", "06:00", at(5, 59), true},
+		{"over midnight to", "22:00", "06:00", at(6, 0), false},
+		{"over midnight noon", "22:00", "06:00", at(12, 0), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			from, err := clock(tt.from)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			to, err := clock(tt.to)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			s := &Scheduler{quiet: true, quietFrom: from, quietTo: to}
+
+			if got := s.isQuiet(tt.t); got != tt.want {
+				t.Errorf("isQuiet(%s) = %v, want %v", tt.t.Format("15:04"), got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewQuietHours(t *testing.T) {
+	tests := []struct {
+		name      string
+		from, to  string
+		wantQuiet bool
+		wantErr   bool
+	}{
+		{"off", "", "", false, false},
+		{"equal is off", "22:00", "22:00", false, false},
+		{"on", "22:00", "06:00", true, false},
+		{"only from", "22:00", "", false, true},
+		{"bad time", "25:00", "06:00", false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := New(&fakeArticles{}, nil, time.Minute, tt.from, tt.to, "UTC", 0, slog.Default())
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("New() error = %v, want error %v", err, tt.wantErr)
+			}
+
+			if err == nil && s.quiet != tt.wantQuiet {
+				t.Errorf("quiet = %v, want %v", s.quiet, tt.wantQuiet)
+			}
+
+			if err == nil && s.isQuiet(time.Date(2024, 5, 1, 22, 0, 0, 0, time.UTC)) != tt.wantQuiet {
+				t.Errorf("isQuiet(22:00) = %v, want %v", !tt.wantQuiet, tt.wantQuiet)
+			}
+		})
+	}
+}
+
+func TestPublish(t *testing.T) {
+	const interval = 10 * time.Minute
+
+	now := time.Now().UTC()
+
+	tests := []struct {
+		name      string
+		articles  *fakeArticles
+		dailyCap  int
+		wantErr   error
+		wantID    int64
+		wantSkips []string
+	}{
+		{
+			name:     "locked by other replica",
+			articles: &fakeArticles{busy: true, pending: []models.Article{{ID: 1}}},
+			wantErr:  services.ErrNotDue,
+		},
+		{
+			name: "interval isn't over",
+			articles: &fakeArticles{
+				latest:  []models.Article{{ID: 1, SourceName: "a", PostedAt: now.Add(-interval / 2)}},
+				pending: []models.Article{{ID: 2, SourceName: "b"}},
+			},
+			wantErr: services.ErrNotDue,
+		},
+		{
+			name: "interval is over",
+			articles: &fakeArticles{
+				latest:  []models.Article{{ID: 1, SourceName: "a", PostedAt: now.Add(-2 * interval)}},
+				pending: []models.Article{{ID: 2, SourceName: "b"}},
+			},
+			wantID:    2,
+			wantSkips: []string{"a"},
+		},
+		{
+			name:      "first post",
+			articles:  &fakeArticles{pending: []models.Article{{ID: 2, SourceName: "b"}}},
+			wantID:    2,
+			wantSkips: []string{""},
+		},
+		{
+			name: "other source goes first",
+			articles: &fakeArticles{
+				latest:  []models.Article{{ID: 1, SourceName: "a", PostedAt: now.Add(-2 * interval)}},
+				pending: []models.Article{{ID: 2, SourceName: "a"}, {ID: 3, SourceName: "b"}},
+			},
+			wantID:    3,
+			wantSkips: []string{"a"},
+		},
+		{
+			name: "only last source has articles",
+			articles: &fakeArticles{
+				latest:  []models.Article{{ID: 1, SourceName: "a", PostedAt: now.Add(-2 * interval)}},
+				pending: []models.Article{{ID: 2, SourceName: "a"}},
+			},
+			wantID:    2,
+			wantSkips: []string{"a", ""},
+		},
+		{
+			name: "nothing to post",
+			articles: &fakeArticles{
+				latest: []models.Article{{ID: 1, SourceName: "a", PostedAt: now.Add(-2 * interval)}},
+			},
+			wantErr:   services.ErrNoNewArticle,
+			wantSkips: []string{"a", ""},
+		},
+		{
+			name:     "daily cap reached",
+			articles: &fakeArticles{posted: 3, pending: []models.Article{{ID: 2}}},
+			dailyCap: 3,
+			wantErr:  services.ErrNotDue,
+		},
+		{
+			name:      "daily cap not reached",
+			articles:  &fakeArticles{posted: 2, pending: []models.Article{{ID: 2}}},
+			dailyCap:  3,
+			wantID:    2,
+			wantSkips: []string{""},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			notifier := &fakeNotifier{}
+
+			s := &Scheduler{
+				articles:  tt.articles,
+				notifiers: []Notifier{notifier},
+				interval:  interval,
+				loc:       time.UTC,
+				dailyCap:  tt.dailyCap,
+				log:       slog.Default(),
+			}
+
+			article, err := s.Publish(context.Background())
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Publish() error = %v, want %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr != nil {
+				if len(tt.articles.marked) != 0 {
+					t.Errorf("articles %v are posted", tt.articles.marked)
+				}
+
+				if len(notifier.published) != 0 {
+					t.Errorf("notified about articles %v", notifier.published)
+				}
+			} else {
+				if article.ID != tt.wantID || article.PostedAt.IsZero() {
+					t.Errorf("Publish() = article %d posted at %v, want article %d", article.ID, article.PostedAt, tt.wantID)
+				}
+
+				if len(tt.articles.marked) != 1 || tt.articles.marked[0] != tt.wantID {
+					t.Errorf("posted articles = %v, want [%d]", tt.articles.marked, tt.wantID)
+				}
+
+				if len(notifier.published) != 1 || notifier.published[0] != tt.wantID {
+					t.Errorf("notified about articles %v, want [%d]", notifier.published, tt.wantID)
+				}
+			}
+
+			if len(tt.articles.skips) != len(tt.wantSkips) {
+				t.Fatalf("skipped sources = %q, want %q", tt.articles.skips, tt.wantSkips)
+			}
+
+			for i := range tt.wantSkips {
+				if tt.articles.skips[i] != tt.wantSkips[i] {
+					t.Errorf("skipped sources = %q, want %q", tt.articles.skips, tt.wantSkips)
+				}
+			}
+		})
+	}
+}
+
+func TestPublishCountsDayFromLocalMidnight(t *testing.T) {
+	for _, offset := range []int{-11, 0, 5, 14} {
+		loc := time.FixedZone("test", offset*60*60)
+
+		articles := &fakeArticles{pending: []models.Article{{ID: 1}}}
+		s := &Scheduler{articles: articles, notifiers: []Notifier{&fakeNotifier{}}, loc: loc, dailyCap: 10, log: slog.Default()}
+
+		before := time.Now().In(loc)
+
+		if _, err := s.Publish(context.Background()); err != nil {
+			t.Fatalf("Publish() error = %v", err)
+		}
+
+		after := time.Now().In(loc)
+
+		if articles.since.Location() != loc {
+			t.Errorf("UTC%+d: day starts in %v, want %v", offset, articles.since.Location(), loc)
+		}
+
+		if h, m, s := articles.since.Clock(); h != 0 || m != 0 || s != 0 {
+			t.Errorf("UTC%+d: day starts at %v, want midnight", offset, articles.since)
+		}
+
+		if articles.since.After(before) || after.Sub(articles.since) > 24*time.Hour {
+			t.Errorf("UTC%+d: day starts at %v, now is %v", offset, articles.since, before)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package simhash
+
+import (
+	"strings"
+	"testing"
+)
+
+const story = `The city council voted on Tuesday to expand the bike lane network across
+the downtown area, adding twelve kilometres of protected lanes over the next two years.
+Supporters said the plan would make cycling safer for commuters, while some shop owners
+worried about losing parking spaces in front of their stores. The mayor promised that
+construction would be scheduled to avoid the busy holiday season and that residents
+would be consulted before work begins on each street.`
+
+func TestFingerprint(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+	}{
+		{"case", "Hello World again", "hello world AGAIN"},
+		{"punctuation and spaces", "hello, world — again!", "  hello world\nagain "},
+		{"digits are words", "release 2 is out", "release, 2 is out."},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if a, b := Fingerprint(tt.a), Fingerprint(tt.b); a != b {
+				t.Errorf("Fingerprint(%q) = %x, Fingerprint(%q) = %x, want equal", tt.a, a, tt.b, b)
+			}
+		})
+	}
+}
+
+func TestFingerprintEmpty(t *testing.T) {
+	for _, text := range []string{"", "   ", "!?—..."} {
+		if got := Fingerprint(text); got != 0 {
+			t.Errorf("Fingerprint(%q) = %x, want 0", text, got)
+		}
+	}
+
+	if Fingerprint("word") == 0 {
+		t.Error("Fingerprint of one word is 0")
+	}
+}
+
+func TestDistance(t *testing.T) {
+	tests := []struct {
+		a, b uint64
+		want int
+	}{
+		{0, 0, 0},
+		{0, 1, 1},
+		{0b1010, 0b0101, 4},
+		{^uint64(0), 0, 64},
+		{1 << 63, 1 << 63, 0},
+	}
+
+	for _, tt := range tests {
+		if got := Distance(tt.a, tt.b); got != tt.want {
+			t.Errorf("Distance(%x, %x) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestNearDuplicates(t *testing.T) {
+	original := Fingerprint(story)
+
+	copied := Distance(original, Fingerprint(strings.ToUpper(strings.Join(strings.Fields(story), "  "))))
+	if copied != 0 {
+		t.Errorf("distance to reformatted copy = %d, want 0", copied)
+	}
+
+	edited := Distance(original, Fingerprint(strings.Replace(story, "twelve", "fifteen", 1)))
+	other := Distance(original, Fingerprint(`Scientists have discovered a new species of deep sea fish near the coast
+of New Zealand. The translucent creature lives at depths of more than three thousand metres
+and feeds on tiny crustaceans drifting down from the surface waters above.`))
+
+	if edited >= other/2 {
+		t.Errorf("distance to edited copy = %d, to other story = %d, want edited copy much closer", edited, other)
+	}
+}
@@ -0,0 +1,207 @@
+package sinks
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"newsWebApp/app/newsService/internal/models"
+)
+
+// ErrRejected is returned by a sink which will never accept the article, it's skipped
+// instead of being retried.
+var ErrRejected = errors.New("article rejected by sink")
+
+// Sink is a place posted articles are sent to, such as a channel in a messenger.
+type Sink interface {
+	// Name identifies state of the sink, it must not change between restarts.
+	Name() string
+	Send(ctx context.Context, article models.Article) error
+}
+
+// RetryError asks to hold the sink back for After before the next attempt.
+type RetryError struct {
+	After time.Duration
+	Err   error
+}
+
+func (e *RetryError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *RetryError) Unwrap() error {
+	return e.Err
+}
+
+type SinkStorage interface {
+	EnsureSink(ctx context.Context, sink string) error
+	ClaimSink(ctx context.Context, sink string, lease time.Duration) (*models.SinkState, bool, error)
+	SaveSinkState(ctx context.Context, state models.SinkState) error
+	PostedSince(ctx context.Context, after models.PageCursor, limit int) ([]models.Article, error)
+}
+
+// Relay sends posted articles to sinks in posting order. Every sink has its own
+// position in the database, so a sink that is down gets its articles when it's back.
+type Relay struct {
+	states SinkStorage
+	sinks  []Sink
+
+	interval   time.Duration
+	timeout    time.Duration
+	batch      int
+	backoff    time.Duration
+	maxBackoff time.Duration
+
+	wake chan struct{}
+	log  *slog.Logger
+}
+
+// New returns relay checking sinks every interval and right after a post. Every send
+// is limited by timeout, failed sink is retried after backoff doubled on every
+// failure up to maxBackoff.
+func New(states SinkStorage,
+	sinks []Sink,
+	interval time.Duration,
+	timeout time.Duration,
+	batch int,
+	backoff time.Duration,
+	maxBackoff time.Duration,
+	log *slog.Logger,
+) *Relay {
+	return &Relay{
+		states:     states,
+		sinks:      sinks,
+		interval:   interval,
+		timeout:    timeout,
+		batch:      batch,
+		backoff:    backoff,
+		maxBackoff: maxBackoff,
+		wake:       make(chan struct{}, 1),
+		log:        log,
+	}
+}
+
+// ArticlePublished wakes the relay up. Article itself is read from the database
+// with others not sent yet.
+func (r *Relay) ArticlePublished(ctx context.Context, article models.Article) {
+	select {
+	case r.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Start relays articles till ctx is done.
+func (r *Relay) Start(ctx context.Context) error {
+	if len(r.sinks) == 0 {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		for _, sink := range r.sinks {
+			r.relay(ctx, sink)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		case <-r.wake:
+		}
+	}
+}
+
+// relay sends the sink articles posted after its position, stopping at the first
+// failure so the order is kept.
+func (r *Relay) relay(ctx context.Context, sink Sink) {
+	if err := r.states.EnsureSink(ctx, sink.Name()); err != nil {
+		r.log.Error("Can't add sink state", "sink", sink.Name(), "err", err.Error())
+		return
+	}
+
+	// Lease covers the whole batch, so another replica doesn't send it twice.
+	state, claimed, err := r.states.ClaimSink(ctx, sink.Name(), time.Duration(r.batch+1)*r.timeout)
+	if err != nil {
+		r.log.Error("Can't claim sink", "sink", sink.Name(), "err", err.Error())
+		return
+	}
+
+	if !claimed {
+		return
+	}
+
+	defer func() {
+		if err := r.states.SaveSinkState(context.Background(), *state); err != nil {
+			r.log.Error("Can't save sink state", "sink", sink.Name(), "err", err.Error())
+		}
+	}()
+
+	articles, err := r.states.PostedSince(ctx, state.Cursor, r.batch)
+	if err != nil {
+		r.log.Error("Can't get articles for sink", "sink", sink.Name(), "err", err.Error())
+		return
+	}
+
+	for _, article := range articles {
+		sendCtx, cancel := context.WithTimeout(ctx, r.timeout)
+		err := sink.Send(sendCtx, article)
+		cancel()
+
+		if err != nil && ctx.Err() != nil {
+			return
+		}
+
+		if err != nil && !errors.Is(err, ErrRejected) {
+			r.markFailed(state, err)
+			return
+		}
+
+		if err != nil {
+			r.log.Error("Article rejected by sink, skipped", "sink", sink.Name(), "article id", article.ID, "err", err.Error())
+		} else {
+			state.LastSentAt = time.Now().UTC()
+		}
+
+		state.Cursor = models.PageCursor{PostedAt: article.PostedAt, ID: article.ID}
+		state.FailureCount = 0
+		state.NextAttemptAt = time.Time{}
+	}
+}
+
+func (r *Relay) markFailed(state *models.SinkState, err error) {
+	state.FailureCount++
+	state.LastError = err.Error()
+	state.LastErrorAt = time.Now().UTC()
+
+	delay := r.backoffDelay(state.FailureCount)
+
+	var retry *RetryError
+	if errors.As(err, &retry) && retry.After > delay {
+		delay = retry.After
+	}
+
+	state.NextAttemptAt = state.LastErrorAt.Add(delay)
+
+	if state.FailureCount == 1 {
+		r.log.Warn("Can't send article to sink", "sink", state.Sink, "err", err.Error())
+	} else {
+		r.log.Debug("Can't send article to sink", "sink", state.Sink, "failures", state.FailureCount, "next attempt", state.NextAttemptAt)
+	}
+}
+
+func (r *Relay) backoffDelay(failures int) time.Duration {
+	delay := r.backoff
+
+	for i := 1; i < failures; i++ {
+		delay *= 2
+		if delay >= r.maxBackoff {
+			return r.maxBackoff
+		}
+	}
+
+	return delay
+}
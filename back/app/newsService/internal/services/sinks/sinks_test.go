@@ -0,0 +1,113 @@
+package sinks
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"testing"
+	"time"
+
+	"newsWebApp/app/newsService/internal/models"
+)
+
+// fakeStates keeps state of one sink and articles posted in order.
+type fakeStates struct {
+	state    models.SinkState
+	articles []models.Article
+}
+
+func (s *fakeStates) EnsureSink(ctx context.Context, sink string) error {
+	s.state.Sink = sink
+	return nil
+}
+
+func (s *fakeStates) ClaimSink(ctx context.Context, sink string, lease time.Duration) (*models.SinkState, bool, error) {
+	state := s.state
+	return &state, true, nil
+}
+
+func (s *fakeStates) SaveSinkState(ctx context.Context, state models.SinkState) error {
+	s.state = state
+	return nil
+}
+
+func (s *fakeStates) PostedSince(ctx context.Context, after models.PageCursor, limit int) ([]models.Article, error) {
+	articles := []models.Article{}
+
+	for _, a := range s.articles {
+		if a.ID > after.ID && len(articles) < limit {
+			articles = append(articles, a)
+		}
+	}
+
+	return articles, nil
+}
+
+func TestRelayCursor(t *testing.T) {
+	posted := time.Date(2024, 2, 7, 10, 0, 0, 0, time.UTC)
+
+	states := &fakeStates{articles: []models.Article{
+		{ID: 1, Title: "A", Link: "https://example.com/a", PostedAt: posted},
+		{ID: 2, Title: "B", Link: "https://example.com/b", PostedAt: posted.Add(time.Minute)},
+		{ID: 3, Title: "C", Link: "https://example.com/c", PostedAt: posted.Add(2 * time.Minute)},
+	}}
+
+	stub, sink := newTelegramStub(t,
+		stubResponse{http.StatusOK, `{"ok":true}`},
+		stubResponse{http.StatusServiceUnavailable, `{"ok":false,"error_code":503,"description":"Service Unavailable"}`},
+	)
+
+	r := New(states, []Sink{sink}, time.Minute, time.Second, 10, time.Minute, time.Hour,
+		slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	r.relay(context.Background(), sink)
+
+	if got := states.state.Cursor; got.ID != 1 || !got.PostedAt.Equal(posted) {
+		t.Fatalf("cursor after outage = %+v, want article 1", got)
+	}
+
+	if states.state.FailureCount != 1 || states.state.NextAttemptAt.IsZero() {
+		t.Errorf("state after outage = %+v, want one failure and next attempt", states.state)
+	}
+
+	stub.responses = []stubResponse{
+		{http.StatusBadRequest, `{"ok":false,"error_code":400,"description":"Bad Request: message is too long"}`},
+	}
+
+	r.relay(context.Background(), sink)
+
+	if got := states.state.Cursor.ID; got != 3 {
+		t.Errorf("cursor after recovery = %d, want 3", got)
+	}
+
+	if states.state.FailureCount != 0 || !states.state.NextAttemptAt.IsZero() {
+		t.Errorf("state after recovery = %+v, want failures reset", states.state)
+	}
+
+	// Article 2 is sent again after the outage, then rejected, article 3 is sent once.
+	if len(stub.messages) != 4 {
+		t.Errorf("messages = %d, want 4", len(stub.messages))
+	}
+}
+
+func TestRelayRetryAfter(t *testing.T) {
+	states := &fakeStates{articles: []models.Article{{ID: 1, Title: "A", Link: "https://example.com/a"}}}
+
+	_, sink := newTelegramStub(t,
+		stubResponse{http.StatusTooManyRequests, `{"ok":false,"error_code":429,"description":"Too Many Requests","parameters":{"retry_after":120}}`},
+	)
+
+	r := New(states, []Sink{sink}, time.Minute, time.Second, 10, time.Second, time.Hour,
+		slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	r.relay(context.Background(), sink)
+
+	if states.state.Cursor.ID != 0 {
+		t.Errorf("cursor = %d, want 0", states.state.Cursor.ID)
+	}
+
+	if wait := states.state.NextAttemptAt.Sub(states.state.LastErrorAt); wait != 2*time.Minute {
+		t.Errorf("next attempt in %s, want 2m0s from retry_after", wait)
+	}
+}
@@ -0,0 +1,140 @@
+package sinks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"newsWebApp/app/newsService/internal/models"
+)
+
+// maxExcerpt keeps messages well below the 4096 characters limit of Telegram.
+const maxExcerpt = 700
+
+// Telegram posts articles to a chat through Telegram Bot API.
+type Telegram struct {
+	baseURL string
+	token   string
+	chatID  string
+	client  *http.Client
+}
+
+// NewTelegram returns sink posting to chatID ("@channel" or numeric id). BaseURL is
+// https://api.telegram.org or address of a local Bot API server or stub.
+func NewTelegram(baseURL string, token string, chatID string, timeout time.Duration) *Telegram {
+	return &Telegram{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		token:   token,
+		chatID:  chatID,
+		client:  &http.Client{Timeout: timeout},
+	}
+}
+
+func (t *Telegram) Name() string {
+	return "telegram:" + t.chatID
+}
+
+type telegramMessage struct {
+	ChatID    string `json:"chat_id"`
+	Text      string `json:"text"`
+	ParseMode string `json:"parse_mode"`
+}
+
+type telegramResponse struct {
+	OK          bool   `json:"ok"`
+	ErrorCode   int    `json:"error_code"`
+	Description string `json:"description"`
+	Parameters  struct {
+		RetryAfter int `json:"retry_after"`
+	} `json:"parameters"`
+}
+
+func (t *Telegram) Send(ctx context.Context, article models.Article) error {
+	body, err := json.Marshal(telegramMessage{
+		ChatID:    t.chatID,
+		Text:      FormatTelegram(article),
+		ParseMode: "HTML",
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.baseURL+"/bot"+t.token+"/sendMessage", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		// Error of the client holds the url with the token.
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return fmt.Errorf("can't reach telegram: %w", err)
+	}
+	defer resp.Body.Close()
+
+	result := telegramResponse{}
+
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&result); err != nil {
+		return fmt.Errorf("telegram responded %s: %w", resp.Status, err)
+	}
+
+	if result.OK {
+		return nil
+	}
+
+	err = fmt.Errorf("telegram error %d: %s", result.ErrorCode, result.Description)
+
+	switch {
+	case result.ErrorCode == http.StatusTooManyRequests:
+		return &RetryError{After: time.Duration(result.Parameters.RetryAfter) * time.Second, Err: err}
+	case result.ErrorCode == http.StatusBadRequest && !strings.Contains(result.Description, "chat not found"):
+		// Message itself is wrong, sending it again won't help.
+		return fmt.Errorf("%w: %w", ErrRejected, err)
+	default:
+		return err
+	}
+}
+
+// FormatTelegram returns message with title, source, excerpt and link of the article
+// in Telegram HTML markup.
+func FormatTelegram(article models.Article) string {
+	var b strings.Builder
+
+	b.WriteString("<b>" + html.EscapeString(article.Title) + "</b>\n")
+
+	if article.SourceName != "" {
+		b.WriteString("<i>" + html.EscapeString(article.SourceName) + "</i>\n")
+	}
+
+	if excerpt := truncate(strings.TrimSpace(article.Excerpt), maxExcerpt); excerpt != "" {
+		b.WriteString("\n" + html.EscapeString(excerpt) + "\n")
+	}
+
+	b.WriteString("\n" + `<a href="` + html.EscapeString(article.Link) + `">` + html.EscapeString(article.Link) + "</a>")
+
+	return b.String()
+}
+
+func truncate(s string, limit int) string {
+	if utf8.RuneCountInString(s) <= limit {
+		return s
+	}
+
+	runes := []rune(s)
+
+	return strings.TrimSpace(string(runes[:limit])) + "…"
+}
@@ -0,0 +1,167 @@
+package sinks
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"newsWebApp/app/newsService/internal/models"
+)
+
+// telegramStub is a local Bot API server answering sendMessage with queued responses,
+// success when the queue is empty.
+type telegramStub struct {
+	mu        sync.Mutex
+	responses []stubResponse
+	messages  []telegramMessage
+	paths     []string
+}
+
+type stubResponse struct {
+	status int
+	body   string
+}
+
+func (s *telegramStub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	msg := telegramMessage{}
+	json.NewDecoder(r.Body).Decode(&msg)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.messages = append(s.messages, msg)
+	s.paths = append(s.paths, r.URL.Path)
+
+	resp := stubResponse{status: http.StatusOK, body: `{"ok":true,"result":{}}`}
+	if len(s.responses) > 0 {
+		resp, s.responses = s.responses[0], s.responses[1:]
+	}
+
+	w.WriteHeader(resp.status)
+	w.Write([]byte(resp.body))
+}
+
+func newTelegramStub(t *testing.T, responses ...stubResponse) (*telegramStub, *Telegram) {
+	stub := &telegramStub{responses: responses}
+
+	srv := httptest.NewServer(stub)
+	t.Cleanup(srv.Close)
+
+	return stub, NewTelegram(srv.URL+"/", "123:secret", "@news", time.Second)
+}
+
+func TestTelegramSend(t *testing.T) {
+	stub, sink := newTelegramStub(t)
+
+	article := models.Article{ID: 1, Title: "Go & you", SourceName: "go.dev", Link: "https://go.dev/blog?a=1&b=2"}
+
+	if err := sink.Send(context.Background(), article); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+
+	if len(stub.messages) != 1 {
+		t.Fatalf("messages = %d, want 1", len(stub.messages))
+	}
+
+	if got := stub.paths[0]; got != "/bot123:secret/sendMessage" {
+		t.Errorf("path = %q", got)
+	}
+
+	msg := stub.messages[0]
+	if msg.ChatID != "@news" || msg.ParseMode != "HTML" || msg.Text != FormatTelegram(article) {
+		t.Errorf("message = %+v", msg)
+	}
+
+	if got := sink.Name(); got != "telegram:@news" {
+		t.Errorf("Name() = %q", got)
+	}
+}
+
+func TestTelegramSendErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		resp     stubResponse
+		rejected bool
+		after    time.Duration
+	}{
+		{
+			name:  "too many requests",
+			resp:  stubResponse{http.StatusTooManyRequests, `{"ok":false,"error_code":429,"description":"Too Many Requests: retry after 30","parameters":{"retry_after":30}}`},
+			after: 30 * time.Second,
+		},
+		{
+			name: "server error",
+			resp: stubResponse{http.StatusInternalServerError, `{"ok":false,"error_code":500,"description":"Internal Server Error"}`},
+		},
+		{
+			name: "bad gateway without json",
+			resp: stubResponse{http.StatusBadGateway, `<html>502 Bad Gateway</html>`},
+		},
+		{
+			name:     "bad message",
+			resp:     stubResponse{http.StatusBadRequest, `{"ok":false,"error_code":400,"description":"Bad Request: can't parse entities"}`},
+			rejected: true,
+		},
+		{
+			// Chat is misconfigured, posts are kept until it's fixed.
+			name: "chat not found",
+			resp: stubResponse{http.StatusBadRequest, `{"ok":false,"error_code":400,"description":"Bad Request: chat not found"}`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, sink := newTelegramStub(t, tt.resp)
+
+			err := sink.Send(context.Background(), models.Article{Title: "A", Link: "https://example.com/a"})
+			if err == nil {
+				t.Fatal("Send() error = nil")
+			}
+
+			if got := errors.Is(err, ErrRejected); got != tt.rejected {
+				t.Errorf("Send() error = %v, rejected = %t, want %t", err, got, tt.rejected)
+			}
+
+			var retry *RetryError
+			if got := errors.As(err, &retry); got != (tt.after > 0) || (got && retry.After != tt.after) {
+				t.Errorf("Send() error = %#v, want retry after %s", err, tt.after)
+			}
+
+			if strings.Contains(err.Error(), "secret") {
+				t.Errorf("Send() error holds the token: %v", err)
+			}
+		})
+	}
+}
+
+func TestFormatTelegram(t *testing.T) {
+	got := FormatTelegram(models.Article{
+		Title:      "<Go> 1.22",
+		SourceName: "go.dev",
+		Excerpt:    "  Loop variables & range over int  ",
+		Link:       `https://go.dev/blog/"go1.22"`,
+	})
+
+	want := "<b>&lt;Go&gt; 1.22</b>\n<i>go.dev</i>\n\nLoop variables &amp; range over int\n\n" +
+		`<a href="https://go.dev/blog/&#34;go1.22&#34;">https://go.dev/blog/&#34;go1.22&#34;</a>`
+
+	if got != want {
+		t.Errorf("FormatTelegram() = %q, want %q", got, want)
+	}
+
+	long := FormatTelegram(models.Article{Title: "T", Excerpt: strings.Repeat("я", maxExcerpt+10), Link: "https://example.com"})
+	if !strings.Contains(long, strings.Repeat("я", maxExcerpt)+"…") || strings.Contains(long, strings.Repeat("я", maxExcerpt+1)) {
+		t.Errorf("long excerpt isn't cut to %d characters", maxExcerpt)
+	}
+
+	bare := FormatTelegram(models.Article{Title: "T", Link: "https://example.com"})
+	if bare != "<b>T</b>\n\n<a href=\"https://example.com\">https://example.com</a>" {
+		t.Errorf("FormatTelegram() without source and excerpt = %q", bare)
+	}
+}
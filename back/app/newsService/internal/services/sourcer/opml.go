@@ -0,0 +1,180 @@
+package sourcer
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"newsWebApp/app/newsService/internal/models"
+	"newsWebApp/app/newsService/internal/services"
+	"newsWebApp/app/newsService/internal/storage"
+)
+
+const opmlTitle = "Go Newsline sources"
+
+type opml struct {
+	XMLName xml.Name `xml:"opml"`
+	Version string   `xml:"version,attr"`
+	Head    opmlHead `xml:"head"`
+	Body    opmlBody `xml:"body"`
+}
+
+type opmlHead struct {
+	Title       string `xml:"title,omitempty"`
+	DateCreated string `xml:"dateCreated,omitempty"`
+}
+
+type opmlBody struct {
+	Outlines []opmlOutline `xml:"outline"`
+}
+
+type opmlOutline struct {
+	Text     string        `xml:"text,attr"`
+	Title    string        `xml:"title,attr,omitempty"`
+	Type     string        `xml:"type,attr,omitempty"`
+	XMLURL   string        `xml:"xmlUrl,attr,omitempty"`
+	HTMLURL  string        `xml:"htmlUrl,attr,omitempty"`
+	Outlines []opmlOutline `xml:"outline"`
+}
+
+// ImportOPML adds feeds from OPML document which are not in sources yet.
+func (s *Sourcer) ImportOPML(ctx context.Context, document []byte) (*models.OPMLReport, error) {
+	const op = "services.sourcer.import_opml"
+
+	doc := opml{}
+
+	if err := xml.Unmarshal(document, &doc); err != nil {
+		s.log.Debug("Can't parse opml document", "err", err.Error())
+		return nil, services.ErrInvalidOPML
+	}
+
+	sources, err := s.sources.GetList(ctx)
+	if err != nil && !errors.Is(err, storage.ErrNoSources) {
+		s.log.Error("Can't get sources", "err", err.Error())
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	known := make(map[string]struct{}, len(sources))
+
+	for _, src := range sources {
+		known[src.FeedURL] = struct{}{}
+	}
+
+	report := models.OPMLReport{}
+
+	for _, outline := range flattenOutlines(doc.Body.Outlines) {
+		entry := models.OPMLEntry{
+			Title:   outlineName(outline),
+			FeedURL: outline.XMLURL,
+		}
+
+		source, err := validSource(entry.Title, entry.FeedURL)
+		if err != nil {
+			entry.Reason = err.Error()
+			report.Rejected = append(report.Rejected, entry)
+			continue
+		}
+
+		if _, ok := known[source.FeedURL]; ok {
+			entry.Reason = "duplicate feed url"
+			report.Skipped = append(report.Skipped, entry)
+			continue
+		}
+
+		if _, err := s.sources.Add(ctx, source); err != nil {
+			if errors.Is(err, storage.ErrSourceExists) {
+				entry.Reason = "duplicate feed url"
+				report.Skipped = append(report.Skipped, entry)
+				continue
+			}
+			s.log.Error("Can't add source from opml", "feed url", source.FeedURL, "err", err.Error())
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		known[source.FeedURL] = struct{}{}
+		report.Added = append(report.Added, entry)
+	}
+
+	return &report, nil
+}
+
+// ExportOPML returns all sources as OPML 2.0 document.
+func (s *Sourcer) ExportOPML(ctx context.Context) ([]byte, error) {
+	const op = "services.sourcer.export_opml"
+
+	sources, err := s.sources.GetList(ctx)
+	if err != nil && !errors.Is(err, storage.ErrNoSources) {
+		s.log.Error("Can't get sources", "err", err.Error())
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	doc := opml{
+		Version: "2.0",
+		Head: opmlHead{
+			Title:       opmlTitle,
+			DateCreated: time.Now().UTC().Format(time.RFC1123Z),
+		},
+	}
+
+	for _, src := range sources {
+		outline := opmlOutline{
+			Text:   src.Name,
+			Title:  src.Name,
+			Type:   "rss",
+			XMLURL: src.FeedURL,
+		}
+
+		if u, err := url.Parse(src.FeedURL); err == nil {
+			outline.HTMLURL = u.Scheme + "://" + u.Host
+		}
+
+		doc.Body.Outlines = append(doc.Body.Outlines, outline)
+	}
+
+	buf := bytes.NewBufferString(xml.Header)
+
+	enc := xml.NewEncoder(buf)
+	enc.Indent("", "  ")
+
+	if err := enc.Encode(doc); err != nil {
+		s.log.Error("Can't encode opml document", "err", err.Error())
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// flattenOutlines returns feed outlines, folders are walked recursively.
+func flattenOutlines(outlines []opmlOutline) []opmlOutline {
+	feeds := []opmlOutline{}
+
+	for _, outline := range outlines {
+		if outline.XMLURL == "" && len(outline.Outlines) > 0 {
+			feeds = append(feeds, flattenOutlines(outline.Outlines)...)
+			continue
+		}
+
+		feeds = append(feeds, outline)
+	}
+
+	return feeds
+}
+
+func outlineName(outline opmlOutline) string {
+	switch {
+	case outline.Title != "":
+		return outline.Title
+	case outline.Text != "":
+		return outline.Text
+	}
+
+	if u, err := url.Parse(outline.XMLURL); err == nil && u.Host != "" {
+		return u.Host
+	}
+
+	return ""
+}
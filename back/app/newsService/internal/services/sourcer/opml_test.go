@@ -0,0 +1,198 @@
+package sourcer
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"io"
+	"log/slog"
+	"reflect"
+	"testing"
+
+	"newsWebApp/app/newsService/internal/models"
+	"newsWebApp/app/newsService/internal/services"
+	"newsWebApp/app/newsService/internal/storage"
+)
+
+// fakeSources keeps sources in memory, feed url is unique like in the database.
+// Feed urls from taken are treated as added by someone else in the meantime.
+// err fails listing of sources.
+type fakeSources struct {
+	SourceStorage
+
+	sources []models.Source
+	taken   map[string]bool
+	err     error
+}
+
+func (s *fakeSources) GetList(ctx context.Context) ([]models.Source, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	if len(s.sources) == 0 {
+		return nil, storage.ErrNoSources
+	}
+
+	return s.sources, nil
+}
+
+func (s *fakeSources) Add(ctx context.Context, source models.Source) (int64, error) {
+	if s.taken[source.FeedURL] {
+		return 0, storage.ErrSourceExists
+	}
+
+	for _, src := range s.sources {
+		if src.FeedURL == source.FeedURL {
+			return 0, storage.ErrSourceExists
+		}
+	}
+
+	source.ID = int64(len(s.sources) + 1)
+	s.sources = append(s.sources, source)
+
+	return source.ID, nil
+}
+
+func newTestSourcer(sources ...models.Source) (*Sourcer, *fakeSources) {
+	stor := &fakeSources{sources: sources}
+
+	return New(stor, slog.New(slog.NewTextHandler(io.Discard, nil))), stor
+}
+
+func TestImportOPML(t *testing.T) {
+	tests := []struct {
+		name     string
+		existing []models.Source
+		taken    map[string]bool
+		document string
+		want     models.OPMLReport
+	}{
+		{
+			name: "nested folders",
+			document: `<opml version="2.0"><body>
+				<outline text="Go">
+					<outline text="Go blog" xmlUrl="https://go.dev/blog/feed.atom"/>
+					<outline text="Deeper">
+						<outline title="Dave" text="ignored" xmlUrl="https://dave.cheney.net/feed"/>
+					</outline>
+				</outline>
+				<outline xmlUrl="https://example.com/rss"/>
+			</body></opml>`,
+			want: models.OPMLReport{
+				Added: []models.OPMLEntry{
+					{Title: "Go blog", FeedURL: "https://go.dev/blog/feed.atom"},
+					{Title: "Dave", FeedURL: "https://dave.cheney.net/feed"},
+					{Title: "example.com", FeedURL: "https://example.com/rss"},
+				},
+			},
+		},
+		{
+			name:     "dedupe against existing and within document",
+			existing: []models.Source{{ID: 1, Name: "dev.to", FeedURL: "https://dev.to/feed/tag/golang"}},
+			taken:    map[string]bool{"https://added.meanwhile/rss": true},
+			document: `<opml version="1.0"><body>
+				<outline text="Dev" xmlUrl="https://dev.to/feed/tag/golang"/>
+				<outline text="Go blog" xmlUrl=" https://go.dev/blog/feed.atom "/>
+				<outline text="Folder"><outline text="Go blog again" xmlUrl="https://go.dev/blog/feed.atom"/></outline>
+				<outline text="Meanwhile" xmlUrl="https://added.meanwhile/rss"/>
+			</body></opml>`,
+			want: models.OPMLReport{
+				Added: []models.OPMLEntry{
+					{Title: "Go blog", FeedURL: " https://go.dev/blog/feed.atom "},
+				},
+				Skipped: []models.OPMLEntry{
+					{Title: "Dev", FeedURL: "https://dev.to/feed/tag/golang", Reason: "duplicate feed url"},
+					{Title: "Go blog again", FeedURL: "https://go.dev/blog/feed.atom", Reason: "duplicate feed url"},
+					{Title: "Meanwhile", FeedURL: "https://added.meanwhile/rss", Reason: "duplicate feed url"},
+				},
+			},
+		},
+		{
+			name: "rejected",
+			document: `<opml version="2.0"><body>
+				<outline text="Local file" xmlUrl="file:///etc/passwd"/>
+				<outline text="Relative" xmlUrl="/feed.xml"/>
+				<outline text="Good" xmlUrl="http://example.com/feed"/>
+			</body></opml>`,
+			want: models.OPMLReport{
+				Added: []models.OPMLEntry{
+					{Title: "Good", FeedURL: "http://example.com/feed"},
+				},
+				Rejected: []models.OPMLEntry{
+					{Title: "Local file", FeedURL: "file:///etc/passwd", Reason: `invalid feed url: "file:///etc/passwd"`},
+					{Title: "Relative", FeedURL: "/feed.xml", Reason: `invalid feed url: "/feed.xml"`},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, stor := newTestSourcer(tt.existing...)
+			stor.taken = tt.taken
+
+			got, err := s.ImportOPML(context.Background(), []byte(tt.document))
+			if err != nil {
+				t.Fatalf("ImportOPML() error = %v", err)
+			}
+
+			if !reflect.DeepEqual(*got, tt.want) {
+				t.Errorf("ImportOPML() = %+v, want %+v", *got, tt.want)
+			}
+
+			if want := len(tt.existing) + len(tt.want.Added); len(stor.sources) != want {
+				t.Errorf("sources = %d, want %d", len(stor.sources), want)
+			}
+		})
+	}
+}
+
+func TestImportOPMLInvalid(t *testing.T) {
+	s, _ := newTestSourcer()
+
+	if _, err := s.ImportOPML(context.Background(), []byte("<opml><body>")); !errors.Is(err, services.ErrInvalidOPML) {
+		t.Errorf("ImportOPML() error = %v, want ErrInvalidOPML", err)
+	}
+}
+
+func TestExportOPML(t *testing.T) {
+	s, _ := newTestSourcer(
+		models.Source{ID: 1, Name: "go.dev", FeedURL: "https://go.dev/blog/feed.atom?format=xml"},
+		models.Source{ID: 2, Name: "Dave & co", FeedURL: "https://dave.cheney.net/feed"},
+	)
+
+	document, err := s.ExportOPML(context.Background())
+	if err != nil {
+		t.Fatalf("ExportOPML() error = %v", err)
+	}
+
+	doc := opml{}
+	if err := xml.Unmarshal(document, &doc); err != nil {
+		t.Fatalf("exported document isn't valid: %v", err)
+	}
+
+	want := []opmlOutline{
+		{Text: "go.dev", Title: "go.dev", Type: "rss", XMLURL: "https://go.dev/blog/feed.atom?format=xml", HTMLURL: "https://go.dev"},
+		{Text: "Dave & co", Title: "Dave & co", Type: "rss", XMLURL: "https://dave.cheney.net/feed", HTMLURL: "https://dave.cheney.net"},
+	}
+
+	if doc.Version != "2.0" || !reflect.DeepEqual(doc.Body.Outlines, want) {
+		t.Errorf("exported outlines = %+v, want %+v", doc.Body.Outlines, want)
+	}
+
+	// Exported document is imported back without changes.
+	again, _ := newTestSourcer(
+		models.Source{ID: 1, Name: "go.dev", FeedURL: "https://go.dev/blog/feed.atom?format=xml"},
+		models.Source{ID: 2, Name: "Dave & co", FeedURL: "https://dave.cheney.net/feed"},
+	)
+
+	report, err := again.ImportOPML(context.Background(), document)
+	if err != nil {
+		t.Fatalf("ImportOPML() of export error = %v", err)
+	}
+
+	if len(report.Added) != 0 || len(report.Skipped) != 2 || len(report.Rejected) != 0 {
+		t.Errorf("ImportOPML() of export = %+v, want all skipped", *report)
+	}
+}
@@ -0,0 +1,171 @@
+package sourcer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"strings"
+
+	"newsWebApp/app/newsService/internal/models"
+	"newsWebApp/app/newsService/internal/services"
+	"newsWebApp/app/newsService/internal/storage"
+)
+
+type SourceStorage interface {
+	GetList(ctx context.Context) ([]models.Source, error)
+	GetByID(ctx context.Context, id int64) (*models.Source, error)
+	Add(ctx context.Context, source models.Source) (int64, error)
+	Update(ctx context.Context, source models.Source) (*models.Source, error)
+	SetEnabled(ctx context.Context, id int64, enabled bool) (*models.Source, error)
+	Delete(ctx context.Context, id int64) error
+}
+
+type Sourcer struct {
+	sources SourceStorage
+
+	log *slog.Logger
+}
+
+func New(sources SourceStorage, log *slog.Logger) *Sourcer {
+	return &Sourcer{
+		sources: sources,
+		log:     log,
+	}
+}
+
+func (s *Sourcer) SourcesHealth(ctx context.Context) ([]models.Source, error) {
+	const op = "services.sourcer.sources_health"
+
+	sources, err := s.sources.GetList(ctx)
+	if err != nil {
+		if errors.Is(err, storage.ErrNoSources) {
+			s.log.Debug("Can't get sources", "err", err.Error())
+			return nil, services.ErrNoSources
+		}
+		s.log.Error("Can't get sources", "err", err.Error())
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if len(sources) == 0 {
+		s.log.Debug("There are no sources")
+		return nil, services.ErrNoSources
+	}
+
+	return sources, nil
+}
+
+func (s *Sourcer) AddSource(ctx context.Context, name string, feedURL string) (*models.Source, error) {
+	const op = "services.sourcer.add_source"
+
+	source, err := validSource(name, feedURL)
+	if err != nil {
+		s.log.Debug("Can't add source", "err", err.Error())
+		return nil, services.ErrInvalidSource
+	}
+
+	id, err := s.sources.Add(ctx, source)
+	if err != nil {
+		if errors.Is(err, storage.ErrSourceExists) {
+			s.log.Debug("Can't add source", "err", err.Error())
+			return nil, services.ErrSourceExists
+		}
+		s.log.Error("Can't add source", "err", err.Error())
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	added, err := s.sources.GetByID(ctx, id)
+	if err != nil {
+		s.log.Error("Can't get added source", "source id", id, "err", err.Error())
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return added, nil
+}
+
+func (s *Sourcer) UpdateSource(ctx context.Context, id int64, name string, feedURL string) (*models.Source, error) {
+	const op = "services.sourcer.update_source"
+
+	source, err := validSource(name, feedURL)
+	if err != nil {
+		s.log.Debug("Can't update source", "err", err.Error())
+		return nil, services.ErrInvalidSource
+	}
+
+	source.ID = id
+
+	updated, err := s.sources.Update(ctx, source)
+	if err != nil {
+		switch {
+		case errors.Is(err, storage.ErrSourceExists):
+			s.log.Debug("Can't update source", "err", err.Error())
+			return nil, services.ErrSourceExists
+		case errors.Is(err, storage.ErrSourceNotFound):
+			s.log.Debug("Can't update source", "err", err.Error())
+			return nil, services.ErrSourceNotFound
+		default:
+			s.log.Error("Can't update source", "err", err.Error())
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	return updated, nil
+}
+
+func (s *Sourcer) DeleteSource(ctx context.Context, id int64) error {
+	const op = "services.sourcer.delete_source"
+
+	if err := s.sources.Delete(ctx, id); err != nil {
+		if errors.Is(err, storage.ErrSourceNotFound) {
+			s.log.Debug("Can't delete source", "err", err.Error())
+			return services.ErrSourceNotFound
+		}
+		s.log.Error("Can't delete source", "err", err.Error())
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (s *Sourcer) SetSourceEnabled(ctx context.Context, id int64, enabled bool) (*models.Source, error) {
+	const op = "services.sourcer.set_source_enabled"
+
+	updated, err := s.sources.SetEnabled(ctx, id, enabled)
+	if err != nil {
+		if errors.Is(err, storage.ErrSourceNotFound) {
+			s.log.Debug("Can't change source state", "err", err.Error())
+			return nil, services.ErrSourceNotFound
+		}
+		s.log.Error("Can't change source state", "err", err.Error())
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return updated, nil
+}
+
+func validSource(name string, feedURL string) (models.Source, error) {
+	source := models.Source{
+		Name:    strings.TrimSpace(name),
+		FeedURL: strings.TrimSpace(feedURL),
+	}
+
+	if source.Name == "" || len(source.Name) > 255 {
+		return source, fmt.Errorf("invalid name: %q", name)
+	}
+
+	if len(source.FeedURL) > 255 {
+		return source, fmt.Errorf("feed url is too long")
+	}
+
+	u, err := url.ParseRequestURI(source.FeedURL)
+	if err != nil {
+		return source, err
+	}
+
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return source, fmt.Errorf("invalid feed url: %q", feedURL)
+	}
+
+	return source, nil
+}
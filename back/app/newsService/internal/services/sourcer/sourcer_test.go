@@ -0,0 +1,42 @@
+package sourcer
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"newsWebApp/app/newsService/internal/models"
+	"newsWebApp/app/newsService/internal/services"
+)
+
+func TestSourcesHealth(t *testing.T) {
+	dbErr := errors.New("connection refused")
+
+	tests := []struct {
+		name    string
+		sources []models.Source
+		err     error
+		want    int
+		wantErr error
+	}{
+		{name: "sources", sources: []models.Source{{ID: 1, FeedURL: "https://go.dev/blog/feed.atom"}}, want: 1},
+		{name: "no sources", wantErr: services.ErrNoSources},
+		{name: "storage error", err: dbErr, wantErr: dbErr},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, stor := newTestSourcer(tt.sources...)
+			stor.err = tt.err
+
+			got, err := s.SourcesHealth(context.Background())
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("SourcesHealth() error = %v, want %v", err, tt.wantErr)
+			}
+
+			if len(got) != tt.want {
+				t.Errorf("SourcesHealth() = %d sources, want %d", len(got), tt.want)
+			}
+		})
+	}
+}
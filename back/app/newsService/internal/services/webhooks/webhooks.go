@@ -0,0 +1,445 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"newsWebApp/app/newsService/internal/models"
+	"newsWebApp/app/newsService/internal/services"
+	"newsWebApp/app/newsService/internal/storage"
+)
+
+const (
+	defaultDeliveries = 50
+	maxDeliveries     = 500
+	// maxResponseBody is read from webhook response before it's dropped.
+	maxResponseBody = 64 << 10
+)
+
+var knownEvents = []string{
+	models.EventArticlePublished,
+	models.EventArticleSubmitted,
+	models.EventSourceFailed,
+}
+
+type WebhookStorage interface {
+	ListWebhooks(ctx context.Context) ([]models.Webhook, error)
+	AddWebhook(ctx context.Context, hook models.Webhook) (int64, error)
+	DeleteWebhook(ctx context.Context, id int64) error
+	EnqueueDeliveries(ctx context.Context, event string, payload []byte) (int64, error)
+	ClaimDeliveries(ctx context.Context, limit int, lease time.Duration) ([]models.WebhookDelivery, error)
+	SaveAttempt(ctx context.Context, delivery models.WebhookDelivery) error
+	Deliveries(ctx context.Context, webhookID int64, status string, limit int) ([]models.WebhookDelivery, error)
+}
+
+// Dispatcher queues events for subscribed webhooks and delivers them. Queue is in
+// the database, so a delivery survives restarts and is sent by one replica.
+type Dispatcher struct {
+	hooks  WebhookStorage
+	client *http.Client
+
+	interval    time.Duration
+	batch       int
+	maxAttempts int
+	backoff     time.Duration
+	maxBackoff  time.Duration
+
+	log *slog.Logger
+}
+
+// New returns dispatcher checking the queue every interval and sending up to batch
+// deliveries at a time. Failed delivery is retried after backoff doubled on every
+// attempt up to maxBackoff, after maxAttempts it's dead.
+func New(hooks WebhookStorage,
+	timeout time.Duration,
+	interval time.Duration,
+	batch int,
+	maxAttempts int,
+	backoff time.Duration,
+	maxBackoff time.Duration,
+	log *slog.Logger,
+) *Dispatcher {
+	return &Dispatcher{
+		hooks: hooks,
+		client: &http.Client{
+			Timeout: timeout,
+			// Redirect is a failure, secret isn't sent to another address.
+			CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse },
+		},
+		interval:    interval,
+		batch:       batch,
+		maxAttempts: maxAttempts,
+		backoff:     backoff,
+		maxBackoff:  maxBackoff,
+		log:         log,
+	}
+}
+
+type envelope struct {
+	Event     string `json:"event"`
+	CreatedAt string `json:"created_at"`
+	Data      any    `json:"data"`
+}
+
+type articleData struct {
+	ArticleID   int64  `json:"article_id"`
+	UserID      int64  `json:"user_id,omitempty"`
+	UserName    string `json:"user_name,omitempty"`
+	SourceName  string `json:"source_name"`
+	Title       string `json:"title"`
+	Link        string `json:"link"`
+	Excerpt     string `json:"excerpt,omitempty"`
+	ImageURL    string `json:"image_url,omitempty"`
+	PublishedAt string `json:"published_at,omitempty"`
+	PostedAt    string `json:"posted_at,omitempty"`
+}
+
+type sourceData struct {
+	SourceID     int64  `json:"source_id"`
+	Name         string `json:"name"`
+	FeedURL      string `json:"feed_url"`
+	Enabled      bool   `json:"enabled"`
+	FailureCount int    `json:"failure_count"`
+	LastError    string `json:"last_error"`
+	NextFetchAt  string `json:"next_fetch_at,omitempty"`
+}
+
+// ArticlePublished queues article.published event.
+func (d *Dispatcher) ArticlePublished(ctx context.Context, article models.Article) {
+	d.enqueue(ctx, models.EventArticlePublished, newArticleData(article))
+}
+
+// ArticleSubmitted queues article.submitted event.
+func (d *Dispatcher) ArticleSubmitted(ctx context.Context, article models.Article) {
+	d.enqueue(ctx, models.EventArticleSubmitted, newArticleData(article))
+}
+
+// SourceFailed queues source.failed event.
+func (d *Dispatcher) SourceFailed(ctx context.Context, source models.Source) {
+	d.enqueue(ctx, models.EventSourceFailed, sourceData{
+		SourceID:     source.ID,
+		Name:         source.Name,
+		FeedURL:      source.FeedURL,
+		Enabled:      source.Enabled,
+		FailureCount: source.FailureCount,
+		LastError:    source.LastError,
+		NextFetchAt:  formatTime(source.NextFetchAt),
+	})
+}
+
+func newArticleData(article models.Article) articleData {
+	return articleData{
+		ArticleID:   article.ID,
+		UserID:      article.UserID,
+		UserName:    article.UserName,
+		SourceName:  article.SourceName,
+		Title:       article.Title,
+		Link:        article.Link,
+		Excerpt:     article.Excerpt,
+		ImageURL:    article.ImageURL,
+		PublishedAt: formatTime(article.PublishedAt),
+		PostedAt:    formatTime(article.PostedAt),
+	}
+}
+
+// enqueue saves the event for subscribed webhooks. Event is lost if it can't be
+// saved, what happened isn't rolled back because of a webhook.
+func (d *Dispatcher) enqueue(ctx context.Context, event string, data any) {
+	payload, err := json.Marshal(envelope{
+		Event:     event,
+		CreatedAt: formatTime(time.Now()),
+		Data:      data,
+	})
+	if err != nil {
+		d.log.Error("Can't marshal webhook event", "event", event, "err", err.Error())
+		return
+	}
+
+	queued, err := d.hooks.EnqueueDeliveries(ctx, event, payload)
+	if err != nil {
+		d.log.Error("Can't queue webhook event", "event", event, "err", err.Error())
+		return
+	}
+
+	if queued > 0 {
+		d.log.Debug("Webhook event queued", "event", event, "deliveries", queued)
+	}
+}
+
+// Start sends due deliveries till ctx is done.
+func (d *Dispatcher) Start(ctx context.Context) error {
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+
+	for {
+		d.dispatch(ctx)
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+func (d *Dispatcher) dispatch(ctx context.Context) {
+	// Claimed deliveries are hidden from other replicas while they are being sent.
+	deliveries, err := d.hooks.ClaimDeliveries(ctx, d.batch, 2*d.client.Timeout)
+	if err != nil {
+		if ctx.Err() == nil {
+			d.log.Error("Can't get webhook deliveries", "err", err.Error())
+		}
+		return
+	}
+
+	var wg sync.WaitGroup
+
+	for _, delivery := range deliveries {
+		wg.Add(1)
+
+		go func(delivery models.WebhookDelivery) {
+			defer wg.Done()
+			d.deliver(ctx, delivery)
+		}(delivery)
+	}
+
+	wg.Wait()
+}
+
+func (d *Dispatcher) deliver(ctx context.Context, delivery models.WebhookDelivery) {
+	code, err := d.send(ctx, delivery)
+	if ctx.Err() != nil {
+		// Delivery is sent again after its lease.
+		return
+	}
+
+	delivery.Attempts++
+	delivery.ResponseCode = code
+
+	now := time.Now()
+
+	switch {
+	case err == nil:
+		delivery.Status = models.DeliveryDelivered
+		delivery.LastError = ""
+		delivery.DeliveredAt = now
+		delivery.NextAttemptAt = now
+	case delivery.Attempts >= d.maxAttempts:
+		delivery.Status = models.DeliveryDead
+		delivery.LastError = err.Error()
+		delivery.NextAttemptAt = now
+		d.log.Warn("Webhook delivery is dead", "delivery id", delivery.ID, "url", delivery.URL, "attempts", delivery.Attempts, "err", err.Error())
+	default:
+		delivery.Status = models.DeliveryPending
+		delivery.LastError = err.Error()
+		delivery.NextAttemptAt = now.Add(d.backoffDelay(delivery.Attempts))
+		d.log.Debug("Can't deliver webhook", "delivery id", delivery.ID, "url", delivery.URL, "attempts", delivery.Attempts, "err", err.Error())
+	}
+
+	if err := d.hooks.SaveAttempt(context.Background(), delivery); err != nil {
+		d.log.Error("Can't save webhook delivery", "delivery id", delivery.ID, "err", err.Error())
+	}
+}
+
+// send posts payload of the delivery signed by the secret of its webhook. Any response
+// but 2xx is a failure.
+func (d *Dispatcher) send(ctx context.Context, delivery models.WebhookDelivery) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, err
+	}
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "newsWebApp-Webhook/1.0")
+	req.Header.Set("X-Webhook-Event", delivery.Event)
+	req.Header.Set("X-Webhook-Delivery", strconv.FormatInt(delivery.ID, 10))
+	req.Header.Set("X-Webhook-Timestamp", timestamp)
+	req.Header.Set("X-Webhook-Signature", "sha256="+Sign(delivery.Secret, timestamp, delivery.Payload))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, maxResponseBody))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+
+	return resp.StatusCode, nil
+}
+
+// Sign returns hex HMAC-SHA256 of "timestamp.payload" with the secret. Receiver
+// checks it and the timestamp to reject forged and replayed requests.
+func Sign(secret string, timestamp string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte{'.'})
+	mac.Write(payload)
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func (d *Dispatcher) backoffDelay(attempts int) time.Duration {
+	delay := d.backoff
+
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= d.maxBackoff {
+			return d.maxBackoff
+		}
+	}
+
+	return delay
+}
+
+func (d *Dispatcher) ListWebhooks(ctx context.Context) ([]models.Webhook, error) {
+	const op = "services.webhooks.list_webhooks"
+
+	hooks, err := d.hooks.ListWebhooks(ctx)
+	if err != nil {
+		d.log.Error("Can't get webhooks", "err", err.Error())
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if len(hooks) == 0 {
+		return nil, services.ErrNoWebhooks
+	}
+
+	return hooks, nil
+}
+
+// AddWebhook subscribes the endpoint to events. Secret is generated when it's empty.
+func (d *Dispatcher) AddWebhook(ctx context.Context, endpoint string, secret string, events []string) (*models.Webhook, error) {
+	const op = "services.webhooks.add_webhook"
+
+	hook, err := validWebhook(endpoint, events)
+	if err != nil {
+		d.log.Debug("Can't add webhook", "err", err.Error())
+		return nil, services.ErrInvalidWebhook
+	}
+
+	if secret == "" {
+		if secret, err = newSecret(); err != nil {
+			d.log.Error("Can't generate webhook secret", "err", err.Error())
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	hook.Secret = secret
+	hook.Enabled = true
+	hook.CreatedAt = time.Now().UTC().Truncate(time.Second)
+
+	hook.ID, err = d.hooks.AddWebhook(ctx, hook)
+	if err != nil {
+		d.log.Error("Can't add webhook", "err", err.Error())
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &hook, nil
+}
+
+func (d *Dispatcher) DeleteWebhook(ctx context.Context, id int64) error {
+	const op = "services.webhooks.delete_webhook"
+
+	if err := d.hooks.DeleteWebhook(ctx, id); err != nil {
+		if errors.Is(err, storage.ErrWebhookNotFound) {
+			d.log.Debug("Can't delete webhook", "err", err.Error())
+			return services.ErrWebhookNotFound
+		}
+		d.log.Error("Can't delete webhook", "err", err.Error())
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// Deliveries returns delivery log, newest first. Zero webhookID and empty status
+// match any, zero limit is the default one.
+func (d *Dispatcher) Deliveries(ctx context.Context, webhookID int64, status string, limit int) ([]models.WebhookDelivery, error) {
+	const op = "services.webhooks.deliveries"
+
+	switch status {
+	case "", models.DeliveryPending, models.DeliveryDelivered, models.DeliveryDead:
+	default:
+		return nil, services.ErrInvalidStatus
+	}
+
+	if limit <= 0 {
+		limit = defaultDeliveries
+	}
+
+	deliveries, err := d.hooks.Deliveries(ctx, webhookID, status, min(limit, maxDeliveries))
+	if err != nil {
+		d.log.Error("Can't get webhook deliveries", "err", err.Error())
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return deliveries, nil
+}
+
+func validWebhook(endpoint string, events []string) (models.Webhook, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return models.Webhook{}, err
+	}
+
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return models.Webhook{}, fmt.Errorf("unsupported url %q", endpoint)
+	}
+
+	if len(events) == 0 {
+		return models.Webhook{}, errors.New("no events")
+	}
+
+	hook := models.Webhook{URL: u.String()}
+
+	for _, event := range events {
+		if !slices.Contains(knownEvents, event) {
+			return models.Webhook{}, fmt.Errorf("unknown event %q", event)
+		}
+
+		if !slices.Contains(hook.Events, event) {
+			hook.Events = append(hook.Events, event)
+		}
+	}
+
+	return hook, nil
+}
+
+func newSecret() (string, error) {
+	secret := make([]byte, 32)
+
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(secret), nil
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+
+	return t.UTC().Format(time.RFC3339)
+}
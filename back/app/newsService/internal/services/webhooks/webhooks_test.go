@@ -0,0 +1,182 @@
+package webhooks
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"newsWebApp/app/newsService/internal/models"
+)
+
+func TestSign(t *testing.T) {
+	tests := []struct {
+		name      string
+		secret    string
+		timestamp string
+		payload   string
+		want      string
+	}{
+		{
+			name:      "payload",
+			secret:    "secret",
+			timestamp: "1700000000",
+			payload:   `{"event":"article.published"}`,
+			want:      "232e72872fb999d09181a95b88968a619c1b47fdc1471ec8d41f231661e4b46c",
+		},
+		{
+			name:      "empty",
+			timestamp: "0",
+			want:      "b849d5a581847b281957065739df36df2463d1977ea8d6e1e4e6cf33fadc68c3",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Sign(tt.secret, tt.timestamp, []byte(tt.payload)); got != tt.want {
+				t.Errorf("Sign() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSignDependsOnEveryPart(t *testing.T) {
+	base := Sign("secret", "1700000000", []byte("{}"))
+
+	for name, got := range map[string]string{
+		"secret":    Sign("secret2", "1700000000", []byte("{}")),
+		"timestamp": Sign("secret", "1700000001", []byte("{}")),
+		"payload":   Sign("secret", "1700000000", []byte("{ }")),
+		"separator": Sign("secret", "170000000", []byte("0.{}")),
+	} {
+		if got == base {
+			t.Errorf("signature doesn't change with %s", name)
+		}
+	}
+}
+
+func TestSend(t *testing.T) {
+	delivery := models.WebhookDelivery{
+		ID:      7,
+		Event:   models.EventArticlePublished,
+		Secret:  "secret",
+		Payload: []byte(`{"event":"article.published"}`),
+	}
+
+	tests := []struct {
+		name    string
+		status  int
+		wantErr bool
+	}{
+		{"ok", http.StatusOK, false},
+		{"no content", http.StatusNoContent, false},
+		{"redirect", http.StatusFound, true},
+		{"server error", http.StatusInternalServerError, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+
+				if string(body) != string(delivery.Payload) {
+					t.Errorf("body = %s, want %s", body, delivery.Payload)
+				}
+
+				if got := r.Header.Get("X-Webhook-Event"); got != delivery.Event {
+					t.Errorf("event = %q, want %q", got, delivery.Event)
+				}
+
+				if got := r.Header.Get("X-Webhook-Delivery"); got != "7" {
+					t.Errorf("delivery = %q, want 7", got)
+				}
+
+				timestamp := r.Header.Get("X-Webhook-Timestamp")
+				if _, err := strconv.ParseInt(timestamp, 10, 64); err != nil {
+					t.Errorf("timestamp %q isn't unix time", timestamp)
+				}
+
+				if got, want := r.Header.Get("X-Webhook-Signature"), "sha256="+Sign("secret", timestamp, body); got != want {
+					t.Errorf("signature = %q, want %q", got, want)
+				}
+
+				if tt.status == http.StatusFound {
+					w.Header().Set("Location", "/elsewhere")
+				}
+				w.WriteHeader(tt.status)
+			}))
+			defer srv.Close()
+
+			d := New(nil, time.Second, time.Second, 1, 1, time.Second, time.Second, slog.Default())
+
+			delivery := delivery
+			delivery.URL = srv.URL
+
+			status, err := d.send(context.Background(), delivery)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("send() error = %v, want error %v", err, tt.wantErr)
+			}
+
+			if status != tt.status {
+				t.Errorf("send() status = %d, want %d", status, tt.status)
+			}
+		})
+	}
+}
+
+func TestBackoffDelay(t *testing.T) {
+	d := &Dispatcher{backoff: time.Minute, maxBackoff: 10 * time.Minute}
+
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{0, time.Minute},
+		{1, time.Minute},
+		{2, 2 * time.Minute},
+		{4, 8 * time.Minute},
+		{5, 10 * time.Minute},
+		{60, 10 * time.Minute},
+	}
+
+	for _, tt := range tests {
+		if got := d.backoffDelay(tt.attempts); got != tt.want {
+			t.Errorf("backoffDelay(%d) = %v, want %v", tt.attempts, got, tt.want)
+		}
+	}
+}
+
+func TestValidWebhook(t *testing.T) {
+	tests := []struct {
+		name       string
+		endpoint   string
+		events     []string
+		wantEvents int
+		wantErr    bool
+	}{
+		{"ok", "https://example.com/hook", []string{models.EventArticlePublished}, 1, false},
+		{"duplicate events", "http://example.com/hook", []string{models.EventSourceFailed, models.EventSourceFailed}, 1, false},
+		{"unknown event", "https://example.com/hook", []string{"article.deleted"}, 0, true},
+		{"no events", "https://example.com/hook", nil, 0, true},
+		{"ftp", "ftp://example.com/hook", []string{models.EventArticlePublished}, 0, true},
+		{"no host", "https:///hook", []string{models.EventArticlePublished}, 0, true},
+		{"bad url", "://", []string{models.EventArticlePublished}, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hook, err := validWebhook(tt.endpoint, tt.events)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("validWebhook() error = %v, want error %v", err, tt.wantErr)
+			}
+
+			if len(hook.Events) != tt.wantEvents {
+				t.Errorf("validWebhook() events = %v, want %d", hook.Events, tt.wantEvents)
+			}
+		})
+	}
+}
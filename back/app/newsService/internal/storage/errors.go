@@ -3,13 +3,19 @@ package storage
 import "errors"
 
 var (
-	ErrNoSources           = errors.New("there are no sources")
-	ErrSourceNotFound      = errors.New("source not found")
-	ErrSourceExists        = errors.New("source exists")
-	ErrNoNewArticles       = errors.New("there are no new articles")
-	ErrNoLatestArticles    = errors.New("there are no latest articles")
-	ErrArticleExists       = errors.New("article already exists")
-	ErrLinkExists          = errors.New("link already exists")
-	ErrNoLink              = errors.New("link doesn't exist")
-	ErrArticleNotAvailable = errors.New("article not available")
+	ErrNoSources            = errors.New("there are no sources")
+	ErrSourceNotFound       = errors.New("source not found")
+	ErrSourceExists         = errors.New("source exists")
+	ErrNoNewArticles        = errors.New("there are no new articles")
+	ErrNoLatestArticles     = errors.New("there are no latest articles")
+	ErrArticleExists        = errors.New("article already exists")
+	ErrLinkExists           = errors.New("link already exists")
+	ErrNoLink               = errors.New("link doesn't exist")
+	ErrArticleNotAvailable  = errors.New("article not available")
+	ErrArticleNotFound      = errors.New("article not found")
+	ErrWebhookNotFound      = errors.New("webhook not found")
+	ErrFollowerNotFound     = errors.New("follower not found")
+	ErrSubscriptionNotFound = errors.New("subscription not found")
+	ErrSubmissionNotFound   = errors.New("submission not found")
+	ErrNotPending           = errors.New("submission isn't pending")
 )
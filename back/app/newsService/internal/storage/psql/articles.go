@@ -21,11 +21,11 @@ func NewArticleStorage(db *sql.DB) *ArticleStorage {
 	return &ArticleStorage{db: db}
 }
 
-func (s *ArticleStorage) SaveArticle(ctx context.Context, article models.Article) error {
-	stmt, err := s.prepareStmt(ctx, `INSERT INTO articles (user_id, source_name, title, link, excerpt, image, published_at) 
-	VALUES ($1, $2, $3, $4, $5, $6, $7::timestamp)`)
+func (s *ArticleStorage) SaveArticle(ctx context.Context, article models.Article) (int64, error) {
+	stmt, err := s.prepareStmt(ctx, `INSERT INTO articles (user_id, source_name, title, link, original_link, excerpt, image, published_at, fingerprint, duplicate_of, status) 
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8::timestamp, $9, NULLIF($10, 0), $11) RETURNING article_id`)
 	if err != nil {
-		return fmt.Errorf("can't prepare statement: %w", err)
+		return 0, fmt.Errorf("can't prepare statement: %w", err)
 	}
 	defer stmt.Close()
 
@@ -33,21 +33,29 @@ func (s *ArticleStorage) SaveArticle(ctx context.Context, article models.Article
 		article.UserID = 1
 	}
 
-	if err := s.retrySave(ctx, stmt, article); err != nil {
+	if article.Status == "" {
+		article.Status = models.StatusApproved
+	}
+
+	id, err := s.retrySave(ctx, stmt, article)
+	if err != nil {
 		if errors.Is(err, storage.ErrArticleExists) {
-			return storage.ErrArticleExists
+			return 0, storage.ErrArticleExists
 		} else {
-			return fmt.Errorf("can't save article: %v", err)
+			return 0, fmt.Errorf("can't save article: %v", err)
 		}
 	}
 
-	return nil
+	return id, nil
 }
 
+// UpdateArticle replaces not posted article. Decision of a moderator is dropped,
+// changed article gets the status again.
 func (s *ArticleStorage) UpdateArticle(ctx context.Context, artID int64, article models.Article) error {
 	stmt, err := s.prepareStmt(ctx, `UPDATE articles 
-	SET source_name = $1, title = $2, link = $3, excerpt = $4, image = $5, created_at = $6::timestamp, published_at = $7::timestamp 
-	WHERE article_id = $8 AND posted_at IS NULL RETURNING article_id`)
+	SET source_name = $1, title = $2, link = $3, original_link = $4, excerpt = $5, image = $6, created_at = $7::timestamp, published_at = $8::timestamp, fingerprint = $9, 
+	status = $10, moderator_note = '', moderated_by = '', moderated_at = NULL 
+	WHERE article_id = $11 AND posted_at IS NULL RETURNING article_id`)
 	if err != nil {
 		return fmt.Errorf("can't prepare statement: %w", err)
 	}
@@ -55,16 +63,23 @@ func (s *ArticleStorage) UpdateArticle(ctx context.Context, artID int64, article
 
 	now := time.Now().UTC().Format(time.RFC3339)
 
+	if article.Status == "" {
+		article.Status = models.StatusApproved
+	}
+
 	var id int64
 
 	if err := stmt.QueryRowContext(ctx,
 		article.SourceName,
 		article.Title,
 		article.Link,
+		article.OriginalLink,
 		article.Excerpt,
 		article.ImageURL,
 		now,
 		article.PublishedAt,
+		int64(article.Fingerprint),
+		article.Status,
 		artID,
 	).Scan(&id); err != nil {
 		pqErr, ok := err.(*pq.Error)
@@ -80,8 +95,20 @@ func (s *ArticleStorage) UpdateArticle(ctx context.Context, artID int64, article
 	return nil
 }
 
-func (s *ArticleStorage) DeleteArticle(ctx context.Context, userID int64, artID int64) error {
-	stmt, err := s.prepareStmt(ctx, `DELETE FROM articles WHERE article_id = $1 AND user_id = $2 AND posted_at IS NULL RETURNING article_id`)
+// DeleteArticle deletes not posted article of the user. When the article is head of
+// a group of duplicates, nextHeadID, or the oldest copy if it isn't in the group,
+// becomes the head in the same statement, so the rest of copies aren't all released.
+func (s *ArticleStorage) DeleteArticle(ctx context.Context, userID int64, artID int64, nextHeadID int64) error {
+	stmt, err := s.prepareStmt(ctx, `WITH deleted AS (
+		DELETE FROM articles WHERE article_id = $1 AND user_id = $2 AND posted_at IS NULL RETURNING article_id
+	), next_head AS (
+		SELECT article_id FROM articles WHERE duplicate_of = $1 AND EXISTS (SELECT 1 FROM deleted) 
+		ORDER BY article_id = $3 DESC, created_at, article_id LIMIT 1
+	), relinked AS (
+		UPDATE articles SET duplicate_of = NULLIF((SELECT article_id FROM next_head), article_id) 
+		WHERE duplicate_of = $1 AND EXISTS (SELECT 1 FROM next_head)
+	)
+	SELECT article_id FROM deleted`)
 	if err != nil {
 		return fmt.Errorf("can't prepare statement: %w", err)
 	}
@@ -89,7 +116,7 @@ func (s *ArticleStorage) DeleteArticle(ctx context.Context, userID int64, artID
 
 	var id int64
 
-	if err := stmt.QueryRowContext(ctx, artID, userID).Scan(&id); err != nil {
+	if err := stmt.QueryRowContext(ctx, artID, userID, nextHeadID).Scan(&id); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return storage.ErrArticleNotAvailable
 		}
@@ -127,7 +154,7 @@ func (s *ArticleStorage) LinkById(ctx context.Context, artID int64) (string, err
 }
 
 func (s *ArticleStorage) ArticlesByUid(ctx context.Context, userID int64) ([]models.Article, error) {
-	stmt, err := s.db.PrepareContext(ctx, `SELECT article_id, u.user_name AS user_name, source_name, title, link, excerpt, image FROM articles a 
+	stmt, err := s.db.PrepareContext(ctx, `SELECT article_id, u.user_name AS user_name, source_name, title, link, excerpt, image, status, moderator_note FROM articles a 
 	LEFT JOIN users u ON u.user_id = a.user_id 
 	WHERE a.posted_at IS NULL AND a.user_id = $1 
 	ORDER BY a.created_at DESC`)
@@ -156,6 +183,8 @@ func (s *ArticleStorage) ArticlesByUid(ctx context.Context, userID int64) ([]mod
 			&articl.Link,
 			&articl.Excerpt,
 			&articl.ImageURL,
+			&articl.Status,
+			&articl.ModeratorNote,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("can't scan model article: %w", err)
@@ -209,11 +238,13 @@ func (s *ArticleStorage) LatestPosted(ctx context.Context) ([]models.Article, er
 	return articles, nil
 }
 
-func (s *ArticleStorage) LatestPostedWithLimit(ctx context.Context, limit int64) ([]models.Article, error) {
+// LatestPostedAfter returns newest posted articles older than the cursor. Zero cursor
+// starts from the newest one.
+func (s *ArticleStorage) LatestPostedAfter(ctx context.Context, after models.PageCursor, limit int) ([]models.Article, error) {
 	stmt, err := s.db.PrepareContext(ctx, `SELECT article_id, u.user_name AS user_name, source_name, title, link, excerpt, image, posted_at FROM articles a 
 	LEFT JOIN users u ON u.user_id = a.user_id 
-	WHERE a.posted_at IS NOT NULL 
-	ORDER BY a.posted_at DESC LIMIT $1`)
+	WHERE a.posted_at IS NOT NULL AND ($1::int = 0 OR (a.posted_at, a.article_id) < ($2::timestamp, $1::int)) 
+	ORDER BY a.posted_at DESC, a.article_id DESC LIMIT $3`)
 	if err != nil {
 		return nil, fmt.Errorf("can't prepare statement: %w", err)
 	}
@@ -221,11 +252,8 @@ func (s *ArticleStorage) LatestPostedWithLimit(ctx context.Context, limit int64)
 
 	var articles []models.Article
 
-	rows, err := stmt.QueryContext(ctx, limit)
+	rows, err := stmt.QueryContext(ctx, after.ID, after.PostedAt.UTC().Format(time.RFC3339Nano), limit)
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return nil, storage.ErrNoLatestArticles
-		}
 		return nil, fmt.Errorf("can't get articles from db: %v", err)
 	}
 	defer rows.Close()
@@ -251,14 +279,16 @@ func (s *ArticleStorage) LatestPostedWithLimit(ctx context.Context, limit int64)
 	return articles, nil
 }
 
-func (s *ArticleStorage) NewestNotPosted(ctx context.Context) (*models.Article, error) {
+// NewestNotPosted returns the newest approved article offered by users or else fetched by bot.
+// Articles of skipSource are left out unless it's empty.
+func (s *ArticleStorage) NewestNotPosted(ctx context.Context, skipSource string) (*models.Article, error) {
 	var article = new(models.Article)
 	var err error
 
-	article, err = s.notPostedFromUsers(ctx)
+	article, err = s.notPostedFromUsers(ctx, skipSource)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			article, err = s.notPostedFromBot(ctx)
+			article, err = s.notPostedFromBot(ctx, skipSource)
 			if err != nil {
 				if errors.Is(err, sql.ErrNoRows) {
 					return nil, storage.ErrNoNewArticles
@@ -283,7 +313,8 @@ func (s *ArticleStorage) MarkPosted(ctx context.Context, id int64) (time.Time, e
 	}
 	defer stmt.Close()
 
-	postedAt := time.Now().UTC()
+	// Truncated to what is stored, so watchers compare it with articles read back.
+	postedAt := time.Now().UTC().Truncate(time.Second)
 
 	if _, err := stmt.ExecContext(ctx, postedAt.Format(time.RFC3339), id); err != nil {
 		return time.Time{}, fmt.Errorf("can't update article in db: %v", err)
@@ -292,17 +323,185 @@ func (s *ArticleStorage) MarkPosted(ctx context.Context, id int64) (time.Time, e
 	return postedAt, nil
 }
 
-func (s *ArticleStorage) notPostedFromUsers(ctx context.Context) (*models.Article, error) {
+// PostedAfter returns articles posted after the article with artID in posting order,
+// zero artID returns all posted articles.
+func (s *ArticleStorage) PostedAfter(ctx context.Context, artID int64) ([]models.Article, error) {
+	stmt, err := s.db.PrepareContext(ctx, `SELECT a.article_id, u.user_name AS user_name, a.source_name, a.title, a.link, a.excerpt, a.image, a.posted_at FROM articles a 
+	LEFT JOIN users u ON u.user_id = a.user_id 
+	LEFT JOIN articles l ON l.article_id = $1 
+	WHERE a.posted_at IS NOT NULL AND ($1 = 0 OR (a.posted_at, a.article_id) > (l.posted_at, l.article_id)) 
+	ORDER BY a.posted_at, a.article_id`)
+	if err != nil {
+		return nil, fmt.Errorf("can't prepare statement: %w", err)
+	}
+	defer stmt.Close()
+
+	if artID != 0 {
+		var exists bool
+
+		if err := s.db.QueryRowContext(ctx, "SELECT EXISTS (SELECT 1 FROM articles WHERE article_id = $1 AND posted_at IS NOT NULL)", artID).Scan(&exists); err != nil {
+			return nil, fmt.Errorf("can't check article: %w", err)
+		}
+
+		if !exists {
+			return nil, storage.ErrArticleNotFound
+		}
+	}
+
+	rows, err := stmt.QueryContext(ctx, artID)
+	if err != nil {
+		return nil, fmt.Errorf("can't get articles from db: %w", err)
+	}
+	defer rows.Close()
+
+	articles := []models.Article{}
+
+	for rows.Next() {
+		articl := models.Article{}
+		err = rows.Scan(&articl.ID,
+			&articl.UserName,
+			&articl.SourceName,
+			&articl.Title,
+			&articl.Link,
+			&articl.Excerpt,
+			&articl.ImageURL,
+			&articl.PostedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("can't scan model article: %w", err)
+		}
+
+		articles = append(articles, articl)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("can't get articles from db: %w", err)
+	}
+
+	return articles, nil
+}
+
+// PostedBetween returns up to limit newest articles posted in [from, to).
+func (s *ArticleStorage) PostedBetween(ctx context.Context, from time.Time, to time.Time, limit int) ([]models.Article, error) {
+	stmt, err := s.db.PrepareContext(ctx, `SELECT a.article_id, u.user_name AS user_name, COALESCE(a.source_name, ''), a.title, a.link, a.excerpt, a.image, a.posted_at FROM articles a 
+	LEFT JOIN users u ON u.user_id = a.user_id 
+	WHERE a.posted_at >= $1::timestamp AND a.posted_at < $2::timestamp 
+	ORDER BY a.posted_at DESC, a.article_id DESC LIMIT $3`)
+	if err != nil {
+		return nil, fmt.Errorf("can't prepare statement: %w", err)
+	}
+	defer stmt.Close()
+
+	rows, err := stmt.QueryContext(ctx, from.UTC().Format(time.RFC3339), to.UTC().Format(time.RFC3339), limit)
+	if err != nil {
+		return nil, fmt.Errorf("can't get articles from db: %w", err)
+	}
+	defer rows.Close()
+
+	articles := []models.Article{}
+
+	for rows.Next() {
+		articl := models.Article{}
+		err = rows.Scan(&articl.ID,
+			&articl.UserName,
+			&articl.SourceName,
+			&articl.Title,
+			&articl.Link,
+			&articl.Excerpt,
+			&articl.ImageURL,
+			&articl.PostedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("can't scan model article: %w", err)
+		}
+
+		articles = append(articles, articl)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("can't get articles from db: %w", err)
+	}
+
+	return articles, nil
+}
+
+// RecentFingerprints returns fingerprints of articles created since the time.
+func (s *ArticleStorage) RecentFingerprints(ctx context.Context, since time.Time) ([]models.ArticleFingerprint, error) {
+	stmt, err := s.db.PrepareContext(ctx, `SELECT article_id, user_id, COALESCE(source_name, ''), fingerprint, COALESCE(duplicate_of, 0), posted_at IS NOT NULL 
+	FROM articles WHERE created_at >= $1::timestamp AND fingerprint <> 0`)
+	if err != nil {
+		return nil, fmt.Errorf("can't prepare statement: %w", err)
+	}
+	defer stmt.Close()
+
+	rows, err := stmt.QueryContext(ctx, since.UTC().Format(time.RFC3339))
+	if err != nil {
+		return nil, fmt.Errorf("can't get fingerprints: %w", err)
+	}
+	defer rows.Close()
+
+	fingerprints := []models.ArticleFingerprint{}
+
+	for rows.Next() {
+		fp := models.ArticleFingerprint{}
+
+		var fingerprint int64
+
+		if err := rows.Scan(&fp.ID, &fp.UserID, &fp.SourceName, &fingerprint, &fp.DuplicateOf, &fp.Posted); err != nil {
+			return nil, fmt.Errorf("can't scan fingerprint: %w", err)
+		}
+
+		fp.Fingerprint = uint64(fingerprint)
+		fingerprints = append(fingerprints, fp)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("can't get fingerprints: %w", err)
+	}
+
+	return fingerprints, nil
+}
+
+// LinkDuplicates makes newHeadID, which is a duplicate of headID, the copy of the group
+// which may be posted, old head and the rest of its copies become its duplicates.
+// Posted head isn't replaced, then ErrArticleNotAvailable is returned.
+func (s *ArticleStorage) LinkDuplicates(ctx context.Context, headID int64, newHeadID int64) error {
+	stmt, err := s.db.PrepareContext(ctx, `UPDATE articles SET duplicate_of = NULLIF($1, article_id) 
+	WHERE (article_id = $2 OR duplicate_of = $2) 
+	AND NOT EXISTS (SELECT 1 FROM articles WHERE article_id = $2 AND posted_at IS NOT NULL)`)
+	if err != nil {
+		return fmt.Errorf("can't prepare statement: %w", err)
+	}
+	defer stmt.Close()
+
+	res, err := stmt.ExecContext(ctx, newHeadID, headID)
+	if err != nil {
+		return fmt.Errorf("can't link duplicates: %v", err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("can't link duplicates: %v", err)
+	}
+
+	if affected == 0 {
+		return storage.ErrArticleNotAvailable
+	}
+
+	return nil
+}
+
+func (s *ArticleStorage) notPostedFromUsers(ctx context.Context, skipSource string) (*models.Article, error) {
 	stmt, err := s.db.PrepareContext(ctx, `SELECT u.user_name AS user_name, article_id, source_name, title, link, excerpt, image, published_at, created_at FROM articles a 
 	LEFT JOIN users u ON u.user_id = a.user_id 
-	WHERE a.posted_at IS NULL AND a.user_id > 1 
+	WHERE a.posted_at IS NULL AND a.duplicate_of IS NULL AND a.status = 'approved' AND a.user_id > 1 AND ($1 = '' OR COALESCE(a.source_name, '') <> $1) 
 	ORDER BY published_at DESC LIMIT 1`)
 	if err != nil {
 		return nil, fmt.Errorf("can't prepare statement: %w", err)
 	}
 	defer stmt.Close()
 
-	row := stmt.QueryRowContext(ctx)
+	row := stmt.QueryRowContext(ctx, skipSource)
 
 	if err := row.Err(); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -332,17 +531,17 @@ func (s *ArticleStorage) notPostedFromUsers(ctx context.Context) (*models.Articl
 	return &article, nil
 }
 
-func (s *ArticleStorage) notPostedFromBot(ctx context.Context) (*models.Article, error) {
+func (s *ArticleStorage) notPostedFromBot(ctx context.Context, skipSource string) (*models.Article, error) {
 	stmt, err := s.db.PrepareContext(ctx, `SELECT u.user_name AS user_name, article_id, source_name, title, link, excerpt, image, published_at, created_at FROM articles a 
 	LEFT JOIN users u ON u.user_id = a.user_id 
-	WHERE posted_at IS NULL 
+	WHERE posted_at IS NULL AND duplicate_of IS NULL AND a.status = 'approved' AND ($1 = '' OR COALESCE(a.source_name, '') <> $1) 
 	ORDER BY published_at DESC LIMIT 1`)
 	if err != nil {
 		return nil, fmt.Errorf("can't prepare statement: %w", err)
 	}
 	defer stmt.Close()
 
-	row := stmt.QueryRowContext(ctx)
+	row := stmt.QueryRowContext(ctx, skipSource)
 
 	if err := row.Err(); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -397,22 +596,28 @@ func (s *ArticleStorage) prepareStmt(ctx context.Context, query string) (*sql.St
 	return stmt, nil
 }
 
-func (s *ArticleStorage) retrySave(ctx context.Context, stmt *sql.Stmt, article models.Article) error {
+func (s *ArticleStorage) retrySave(ctx context.Context, stmt *sql.Stmt, article models.Article) (int64, error) {
 	var err error
+	var id int64
 
 	for i := 1; i <= 3; i++ {
-		if _, err := stmt.ExecContext(ctx,
+		err = stmt.QueryRowContext(ctx,
 			article.UserID,
 			article.SourceName,
 			article.Title,
 			article.Link,
+			article.OriginalLink,
 			article.Excerpt,
 			article.ImageURL,
 			article.PublishedAt.Format(time.RFC3339),
-		); err != nil {
+			int64(article.Fingerprint),
+			article.DuplicateOf,
+			article.Status,
+		).Scan(&id)
+		if err != nil {
 			pqErr, ok := err.(*pq.Error)
 			if ok && pqErr.Code.Name() == "unique_violation" {
-				return storage.ErrArticleExists
+				return 0, storage.ErrArticleExists
 			} else {
 				time.Sleep(time.Duration(i) * time.Second)
 			}
@@ -422,8 +627,8 @@ func (s *ArticleStorage) retrySave(ctx context.Context, stmt *sql.Stmt, article
 	}
 
 	if err != nil {
-		return err
+		return 0, err
 	}
 
-	return nil
+	return id, nil
 }
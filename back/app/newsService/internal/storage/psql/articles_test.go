@@ -0,0 +1,65 @@
+package psql
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"newsWebApp/app/newsService/internal/models"
+	"newsWebApp/app/newsService/internal/storage"
+)
+
+func TestDeleteArticleHeadOfDuplicates(t *testing.T) {
+	db := testDB(t)
+	s := NewArticleStorage(db)
+	ctx := context.Background()
+
+	userID := testUser(t, db, "reader")
+
+	save := func(article models.Article) int64 {
+		t.Helper()
+
+		article.Title = "Bike lanes"
+		article.PublishedAt = time.Now()
+
+		id, err := s.SaveArticle(ctx, article)
+		if err != nil {
+			t.Fatalf("SaveArticle() error = %v", err)
+		}
+
+		return id
+	}
+
+	head := save(models.Article{UserID: userID, Link: "https://example.com/user"})
+	blog := save(models.Article{SourceName: "blog", Link: "https://example.com/blog", DuplicateOf: head})
+	wire := save(models.Article{SourceName: "wire", Link: "https://example.com/wire", DuplicateOf: head})
+
+	if err := s.DeleteArticle(ctx, 1, head, wire); !errors.Is(err, storage.ErrArticleNotAvailable) {
+		t.Fatalf("DeleteArticle() of other user error = %v, want ErrArticleNotAvailable", err)
+	}
+
+	if err := s.DeleteArticle(ctx, userID, head, wire); err != nil {
+		t.Fatalf("DeleteArticle() error = %v", err)
+	}
+
+	rows, err := db.Query(`SELECT article_id, COALESCE(duplicate_of, 0) FROM articles`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+
+	got := map[int64]int64{}
+	for rows.Next() {
+		var id, dupOf int64
+		if err := rows.Scan(&id, &dupOf); err != nil {
+			t.Fatal(err)
+		}
+		got[id] = dupOf
+	}
+
+	want := map[int64]int64{wire: 0, blog: wire}
+	if len(got) != len(want) || got[wire] != want[wire] || got[blog] != want[blog] {
+		t.Errorf("groups after delete = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,81 @@
+package psql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"newsWebApp/app/newsService/internal/models"
+	"newsWebApp/app/newsService/internal/storage"
+)
+
+// SaveContent saves extracted content of the article replacing the previous one.
+func (s *ArticleStorage) SaveContent(ctx context.Context, content models.ArticleContent) error {
+	stmt, err := s.db.PrepareContext(ctx, `INSERT INTO article_contents (article_id, html, text, word_count, reading_minutes, byline, language) 
+	VALUES ($1, $2, $3, $4, $5, $6, $7) 
+	ON CONFLICT (article_id) DO UPDATE 
+	SET html = EXCLUDED.html, text = EXCLUDED.text, word_count = EXCLUDED.word_count, 
+	reading_minutes = EXCLUDED.reading_minutes, byline = EXCLUDED.byline, language = EXCLUDED.language`)
+	if err != nil {
+		return fmt.Errorf("can't prepare statement: %w", err)
+	}
+	defer stmt.Close()
+
+	if _, err := stmt.ExecContext(ctx,
+		content.ArticleID,
+		content.HTML,
+		content.Text,
+		content.WordCount,
+		content.ReadingMinutes,
+		content.Byline,
+		content.Language,
+	); err != nil {
+		return fmt.Errorf("can't save article content: %w", err)
+	}
+
+	return nil
+}
+
+// ArticleWithContent returns posted article with its extracted content. Content is
+// empty for articles saved before extraction was stored.
+func (s *ArticleStorage) ArticleWithContent(ctx context.Context, artID int64) (*models.ReaderArticle, error) {
+	stmt, err := s.db.PrepareContext(ctx, `SELECT a.article_id, u.user_name AS user_name, source_name, title, link, excerpt, image, posted_at, 
+	COALESCE(c.html, ''), COALESCE(c.text, ''), COALESCE(c.word_count, 0), COALESCE(c.reading_minutes, 0), COALESCE(c.byline, ''), COALESCE(c.language, '') 
+	FROM articles a 
+	LEFT JOIN users u ON u.user_id = a.user_id 
+	LEFT JOIN article_contents c ON c.article_id = a.article_id 
+	WHERE a.article_id = $1 AND a.posted_at IS NOT NULL`)
+	if err != nil {
+		return nil, fmt.Errorf("can't prepare statement: %w", err)
+	}
+	defer stmt.Close()
+
+	article := new(models.ReaderArticle)
+
+	err = stmt.QueryRowContext(ctx, artID).Scan(&article.Article.ID,
+		&article.Article.UserName,
+		&article.Article.SourceName,
+		&article.Article.Title,
+		&article.Article.Link,
+		&article.Article.Excerpt,
+		&article.Article.ImageURL,
+		&article.Article.PostedAt,
+		&article.Content.HTML,
+		&article.Content.Text,
+		&article.Content.WordCount,
+		&article.Content.ReadingMinutes,
+		&article.Content.Byline,
+		&article.Content.Language,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, storage.ErrArticleNotFound
+		}
+		return nil, fmt.Errorf("can't get article from db: %w", err)
+	}
+
+	article.Content.ArticleID = article.Article.ID
+
+	return article, nil
+}
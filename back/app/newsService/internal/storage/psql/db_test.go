@@ -0,0 +1,62 @@
+package psql
+
+import (
+	"database/sql"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/golang-migrate/migrate/v4"
+	_ "github.com/golang-migrate/migrate/v4/database/postgres"
+	_ "github.com/golang-migrate/migrate/v4/source/file"
+)
+
+// testDB returns migrated database from TEST_POSTGRES_DSN with no sources, articles
+// and users except the bot. Test is skipped when the variable isn't set.
+func testDB(t *testing.T) *sql.DB {
+	t.Helper()
+
+	dsn := os.Getenv("TEST_POSTGRES_DSN")
+	if dsn == "" {
+		t.Skip("TEST_POSTGRES_DSN isn't set")
+	}
+
+	m, err := migrate.New("file://../../../../../migrations", dsn)
+	if err != nil {
+		t.Fatalf("can't create migrator: %v", err)
+	}
+
+	if err := m.Up(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		t.Fatalf("can't apply migrations: %v", err)
+	}
+
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		t.Fatalf("can't open db: %v", err)
+	}
+
+	t.Cleanup(func() { db.Close() })
+
+	if _, err := db.Exec(`TRUNCATE articles, sources RESTART IDENTITY CASCADE`); err != nil {
+		t.Fatalf("can't clean db: %v", err)
+	}
+
+	if _, err := db.Exec(`DELETE FROM users WHERE user_id > 1`); err != nil {
+		t.Fatalf("can't clean db: %v", err)
+	}
+
+	return db
+}
+
+// testUser saves user and returns its id.
+func testUser(t *testing.T, db *sql.DB, name string) int64 {
+	t.Helper()
+
+	var id int64
+
+	if err := db.QueryRow(`INSERT INTO users (user_name, email, password_hash) VALUES ($1, $1, '') RETURNING user_id`, name).Scan(&id); err != nil {
+		t.Fatalf("can't save user: %v", err)
+	}
+
+	return id
+}
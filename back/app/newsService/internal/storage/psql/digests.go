@@ -0,0 +1,167 @@
+package psql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"newsWebApp/app/newsService/internal/models"
+	"newsWebApp/app/newsService/internal/storage"
+)
+
+type DigestStorage struct {
+	db *sql.DB
+}
+
+func NewDigestStorage(db *sql.DB) *DigestStorage {
+	return &DigestStorage{db: db}
+}
+
+// SaveSubscription subscribes the user or changes frequency of the subscription.
+func (s *DigestStorage) SaveSubscription(ctx context.Context, sub models.DigestSubscription) (*models.DigestSubscription, error) {
+	stmt, err := s.db.PrepareContext(ctx, `INSERT INTO digest_subscriptions (user_id, frequency, created_at, next_send_at) 
+	VALUES ($1, $2, $3::timestamp, $4::timestamp) 
+	ON CONFLICT (user_id) DO UPDATE SET frequency = EXCLUDED.frequency, next_send_at = EXCLUDED.next_send_at 
+	RETURNING created_at, last_sent_at`)
+	if err != nil {
+		return nil, fmt.Errorf("can't prepare statement: %w", err)
+	}
+	defer stmt.Close()
+
+	var lastSentAt sql.NullTime
+
+	err = stmt.QueryRowContext(ctx,
+		sub.UserID,
+		sub.Frequency,
+		sub.CreatedAt.UTC().Format(time.RFC3339),
+		sub.NextSendAt.UTC().Format(time.RFC3339),
+	).Scan(&sub.CreatedAt, &lastSentAt)
+	if err != nil {
+		return nil, fmt.Errorf("can't save subscription: %w", err)
+	}
+
+	sub.LastSentAt = lastSentAt.Time
+
+	return &sub, nil
+}
+
+func (s *DigestStorage) Subscription(ctx context.Context, userID int64) (*models.DigestSubscription, error) {
+	stmt, err := s.db.PrepareContext(ctx, `SELECT frequency, created_at, last_sent_at, next_send_at FROM digest_subscriptions WHERE user_id = $1`)
+	if err != nil {
+		return nil, fmt.Errorf("can't prepare statement: %w", err)
+	}
+	defer stmt.Close()
+
+	sub := models.DigestSubscription{UserID: userID}
+
+	var lastSentAt sql.NullTime
+
+	if err := stmt.QueryRowContext(ctx, userID).Scan(&sub.Frequency, &sub.CreatedAt, &lastSentAt, &sub.NextSendAt); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, storage.ErrSubscriptionNotFound
+		}
+		return nil, fmt.Errorf("can't get subscription: %w", err)
+	}
+
+	sub.LastSentAt = lastSentAt.Time
+
+	return &sub, nil
+}
+
+func (s *DigestStorage) DeleteSubscription(ctx context.Context, userID int64) error {
+	stmt, err := s.db.PrepareContext(ctx, `DELETE FROM digest_subscriptions WHERE user_id = $1`)
+	if err != nil {
+		return fmt.Errorf("can't prepare statement: %w", err)
+	}
+	defer stmt.Close()
+
+	res, err := stmt.ExecContext(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("can't delete subscription: %w", err)
+	}
+
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return storage.ErrSubscriptionNotFound
+	}
+
+	return nil
+}
+
+// ClaimDueDigests takes up to limit subscriptions due to be sent, with names and emails
+// of their users, till lease ends. Taken subscriptions are skipped by other replicas.
+func (s *DigestStorage) ClaimDueDigests(ctx context.Context, limit int, lease time.Duration) ([]models.DigestSubscription, error) {
+	stmt, err := s.db.PrepareContext(ctx, `UPDATE digest_subscriptions d SET lease_until = $1::timestamp 
+	FROM users u 
+	WHERE u.user_id = d.user_id AND d.user_id IN (
+		SELECT user_id FROM digest_subscriptions 
+		WHERE next_send_at <= $2::timestamp AND (lease_until IS NULL OR lease_until <= $2::timestamp) 
+		ORDER BY next_send_at LIMIT $3 
+		FOR UPDATE SKIP LOCKED
+	) 
+	RETURNING d.user_id, u.user_name, u.email, d.frequency, d.created_at, d.last_sent_at, d.next_send_at`)
+	if err != nil {
+		return nil, fmt.Errorf("can't prepare statement: %w", err)
+	}
+	defer stmt.Close()
+
+	now := time.Now().UTC()
+
+	rows, err := stmt.QueryContext(ctx, now.Add(lease).Format(time.RFC3339), now.Format(time.RFC3339), limit)
+	if err != nil {
+		return nil, fmt.Errorf("can't claim digests: %w", err)
+	}
+	defer rows.Close()
+
+	subs := []models.DigestSubscription{}
+
+	for rows.Next() {
+		sub := models.DigestSubscription{}
+
+		var lastSentAt sql.NullTime
+
+		if err := rows.Scan(&sub.UserID,
+			&sub.UserName,
+			&sub.Email,
+			&sub.Frequency,
+			&sub.CreatedAt,
+			&lastSentAt,
+			&sub.NextSendAt,
+		); err != nil {
+			return nil, fmt.Errorf("can't scan model subscription: %w", err)
+		}
+
+		sub.LastSentAt = lastSentAt.Time
+
+		subs = append(subs, sub)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("can't claim digests: %w", err)
+	}
+
+	return subs, nil
+}
+
+// SaveDigestSchedule saves when the digest was sent and when it's due next and
+// releases the subscription.
+func (s *DigestStorage) SaveDigestSchedule(ctx context.Context, sub models.DigestSubscription) error {
+	stmt, err := s.db.PrepareContext(ctx, `UPDATE digest_subscriptions 
+	SET last_sent_at = $1, next_send_at = $2::timestamp, lease_until = NULL 
+	WHERE user_id = $3`)
+	if err != nil {
+		return fmt.Errorf("can't prepare statement: %w", err)
+	}
+	defer stmt.Close()
+
+	if _, err := stmt.ExecContext(ctx,
+		nullTime(sub.LastSentAt),
+		sub.NextSendAt.UTC().Format(time.RFC3339),
+		sub.UserID,
+	); err != nil {
+		return fmt.Errorf("can't save digest schedule: %w", err)
+	}
+
+	return nil
+}
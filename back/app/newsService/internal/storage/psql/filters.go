@@ -0,0 +1,97 @@
+package psql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"newsWebApp/app/newsService/internal/models"
+
+	"github.com/lib/pq"
+)
+
+type FilterStorage struct {
+	db *sql.DB
+}
+
+func NewFilterStorage(db *sql.DB) *FilterStorage {
+	return &FilterStorage{db: db}
+}
+
+// Rules returns enabled filter rules in order they are applied.
+func (s *FilterStorage) Rules(ctx context.Context) ([]models.FilterRule, error) {
+	stmt, err := s.db.PrepareContext(ctx, `SELECT name, action, expression, sources FROM filter_rules 
+	WHERE enabled ORDER BY position, rule_id`)
+	if err != nil {
+		return nil, fmt.Errorf("can't prepare statement: %w", err)
+	}
+	defer stmt.Close()
+
+	rows, err := stmt.QueryContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("can't get filter rules: %w", err)
+	}
+	defer rows.Close()
+
+	rules := []models.FilterRule{}
+
+	for rows.Next() {
+		rule := models.FilterRule{}
+
+		if err := rows.Scan(&rule.Name, &rule.Action, &rule.Expr, pq.Array(&rule.Sources)); err != nil {
+			return nil, fmt.Errorf("can't scan filter rule: %w", err)
+		}
+
+		rules = append(rules, rule)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("can't get filter rules: %w", err)
+	}
+
+	return rules, nil
+}
+
+func (s *FilterStorage) SaveDecision(ctx context.Context, decision models.FilterDecision) error {
+	stmt, err := s.db.PrepareContext(ctx, `INSERT INTO filter_decisions (link, source_name, title, accepted, rule, decided_at) 
+	VALUES ($1, $2, $3, $4, $5, $6::timestamp)`)
+	if err != nil {
+		return fmt.Errorf("can't prepare statement: %w", err)
+	}
+	defer stmt.Close()
+
+	if _, err := stmt.ExecContext(ctx,
+		decision.Link,
+		decision.Source,
+		decision.Title,
+		decision.Accepted,
+		decision.Rule,
+		decision.DecidedAt.UTC().Format(time.RFC3339),
+	); err != nil {
+		return fmt.Errorf("can't save filter decision: %v", err)
+	}
+
+	return nil
+}
+
+// DeleteDecisionsBefore deletes filter decisions made before the time and returns their number.
+func (s *FilterStorage) DeleteDecisionsBefore(ctx context.Context, before time.Time) (int64, error) {
+	stmt, err := s.db.PrepareContext(ctx, "DELETE FROM filter_decisions WHERE decided_at < $1::timestamp")
+	if err != nil {
+		return 0, fmt.Errorf("can't prepare statement: %w", err)
+	}
+	defer stmt.Close()
+
+	res, err := stmt.ExecContext(ctx, before.UTC().Format(time.RFC3339))
+	if err != nil {
+		return 0, fmt.Errorf("can't delete filter decisions: %v", err)
+	}
+
+	deleted, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("can't delete filter decisions: %v", err)
+	}
+
+	return deleted, nil
+}
@@ -0,0 +1,97 @@
+package psql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"newsWebApp/app/newsService/internal/models"
+	"newsWebApp/app/newsService/internal/storage"
+)
+
+type FollowerStorage struct {
+	db *sql.DB
+}
+
+func NewFollowerStorage(db *sql.DB) *FollowerStorage {
+	return &FollowerStorage{db: db}
+}
+
+// SaveFollower adds the follower or updates inboxes of the known one.
+func (s *FollowerStorage) SaveFollower(ctx context.Context, follower models.Follower) error {
+	stmt, err := s.db.PrepareContext(ctx, `INSERT INTO followers (actor_id, inbox, shared_inbox, created_at) 
+	VALUES ($1, $2, $3, $4::timestamp)
+	ON CONFLICT (actor_id) DO UPDATE SET inbox = EXCLUDED.inbox, shared_inbox = EXCLUDED.shared_inbox`)
+	if err != nil {
+		return fmt.Errorf("can't prepare statement: %w", err)
+	}
+	defer stmt.Close()
+
+	if _, err := stmt.ExecContext(ctx,
+		follower.ActorID,
+		follower.Inbox,
+		follower.SharedInbox,
+		follower.CreatedAt.UTC().Format(time.RFC3339),
+	); err != nil {
+		return fmt.Errorf("can't save follower: %w", err)
+	}
+
+	return nil
+}
+
+func (s *FollowerStorage) DeleteFollower(ctx context.Context, actorID string) error {
+	stmt, err := s.db.PrepareContext(ctx, "DELETE FROM followers WHERE actor_id = $1")
+	if err != nil {
+		return fmt.Errorf("can't prepare statement: %w", err)
+	}
+	defer stmt.Close()
+
+	res, err := stmt.ExecContext(ctx, actorID)
+	if err != nil {
+		return fmt.Errorf("can't delete follower: %v", err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("can't delete follower: %v", err)
+	}
+
+	if affected == 0 {
+		return storage.ErrFollowerNotFound
+	}
+
+	return nil
+}
+
+func (s *FollowerStorage) Followers(ctx context.Context) ([]models.Follower, error) {
+	stmt, err := s.db.PrepareContext(ctx, "SELECT actor_id, inbox, shared_inbox, created_at FROM followers ORDER BY follower_id")
+	if err != nil {
+		return nil, fmt.Errorf("can't prepare statement: %w", err)
+	}
+	defer stmt.Close()
+
+	rows, err := stmt.QueryContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("can't get followers: %w", err)
+	}
+	defer rows.Close()
+
+	followers := []models.Follower{}
+
+	for rows.Next() {
+		follower := models.Follower{}
+
+		if err := rows.Scan(&follower.ActorID, &follower.Inbox, &follower.SharedInbox, &follower.CreatedAt); err != nil {
+			return nil, fmt.Errorf("can't scan follower: %w", err)
+		}
+
+		followers = append(followers, follower)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("can't get followers: %w", err)
+	}
+
+	return followers, nil
+}
@@ -0,0 +1,59 @@
+package psql
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"time"
+)
+
+// publishLockKey is an advisory lock taken by the replica which is publishing.
+const publishLockKey = 7_301_001
+
+// TryPublishLock takes the publishing lock if no other replica holds it. Lock is
+// released by calling unlock.
+func (s *ArticleStorage) TryPublishLock(ctx context.Context) (func(), bool, error) {
+	conn, err := s.db.Conn(ctx)
+	if err != nil {
+		return nil, false, fmt.Errorf("can't get connection: %w", err)
+	}
+
+	var locked bool
+
+	if err := conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock($1)", publishLockKey).Scan(&locked); err != nil {
+		conn.Close()
+		return nil, false, fmt.Errorf("can't take publish lock: %w", err)
+	}
+
+	if !locked {
+		conn.Close()
+		return nil, false, nil
+	}
+
+	unlock := func() {
+		if _, err := conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock($1)", publishLockKey); err != nil {
+			// Lock lives as long as the session, so the session is dropped from the pool.
+			_ = conn.Raw(func(any) error { return driver.ErrBadConn })
+		}
+		conn.Close()
+	}
+
+	return unlock, true, nil
+}
+
+// CountPostedSince returns how many articles were posted since the time.
+func (s *ArticleStorage) CountPostedSince(ctx context.Context, since time.Time) (int, error) {
+	stmt, err := s.db.PrepareContext(ctx, "SELECT COUNT(*) FROM articles WHERE posted_at >= $1::timestamp")
+	if err != nil {
+		return 0, fmt.Errorf("can't prepare statement: %w", err)
+	}
+	defer stmt.Close()
+
+	var count int
+
+	if err := stmt.QueryRowContext(ctx, since.UTC().Format(time.RFC3339)).Scan(&count); err != nil {
+		return 0, fmt.Errorf("can't count posted articles: %w", err)
+	}
+
+	return count, nil
+}
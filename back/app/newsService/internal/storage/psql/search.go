@@ -0,0 +1,91 @@
+package psql
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"strings"
+
+	"newsWebApp/app/newsService/internal/models"
+)
+
+// Matches are marked by characters of private use area, so text of the article
+// is escaped before marks become html.
+const (
+	startSel = "\uE000"
+	stopSel  = "\uE001"
+)
+
+var headlineOptions = "StartSel=" + startSel + ", StopSel=" + stopSel + ", MaxFragments=2, MaxWords=30, MinWords=10"
+
+var markReplacer = strings.NewReplacer(startSel, "<mark>", stopSel, "</mark>")
+
+// SearchArticles returns posted articles matching the query in russian or english
+// ordered by rank, starting after the cursor. Snippets are html escaped text with
+// matches marked by <mark>.
+func (s *ArticleStorage) SearchArticles(ctx context.Context, query string, after models.SearchCursor, limit int) ([]models.SearchResult, error) {
+	stmt, err := s.db.PrepareContext(ctx, `WITH q AS (
+		SELECT websearch_to_tsquery('russian', $1) || websearch_to_tsquery('english', $1) AS query
+	)
+	SELECT r.article_id, r.user_name, r.source_name, r.title, r.link, r.excerpt, r.image, r.posted_at, r.rank, 
+	ts_headline('russian', r.body, q.query, $5) 
+	FROM (
+		SELECT a.article_id, COALESCE(u.user_name, '') AS user_name, COALESCE(a.source_name, '') AS source_name, a.title, a.link, a.excerpt, a.image, a.posted_at, 
+		ts_rank_cd(a.search_vector, q.query) AS rank, translate(COALESCE(NULLIF(c.text, ''), a.excerpt), $6, '') AS body 
+		FROM articles a 
+		CROSS JOIN q 
+		LEFT JOIN users u ON u.user_id = a.user_id 
+		LEFT JOIN article_contents c ON c.article_id = a.article_id 
+		WHERE a.posted_at IS NOT NULL AND a.search_vector @@ q.query
+	) r 
+	CROSS JOIN q 
+	WHERE $2::int = 0 OR (r.rank, r.article_id) < ($3::real, $2::int) 
+	ORDER BY r.rank DESC, r.article_id DESC 
+	LIMIT $4`)
+	if err != nil {
+		return nil, fmt.Errorf("can't prepare statement: %w", err)
+	}
+	defer stmt.Close()
+
+	rows, err := stmt.QueryContext(ctx, query, after.ID, after.Rank, limit, headlineOptions, startSel+stopSel)
+	if err != nil {
+		return nil, fmt.Errorf("can't search articles: %w", err)
+	}
+	defer rows.Close()
+
+	results := []models.SearchResult{}
+
+	for rows.Next() {
+		res := models.SearchResult{}
+
+		err = rows.Scan(&res.Article.ID,
+			&res.Article.UserName,
+			&res.Article.SourceName,
+			&res.Article.Title,
+			&res.Article.Link,
+			&res.Article.Excerpt,
+			&res.Article.ImageURL,
+			&res.Article.PostedAt,
+			&res.Rank,
+			&res.Snippet,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("can't scan search result: %w", err)
+		}
+
+		res.Snippet = markSnippet(res.Snippet)
+
+		results = append(results, res)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("can't search articles: %w", err)
+	}
+
+	return results, nil
+}
+
+// markSnippet escapes the headline and turns its marks of matches into <mark>.
+func markSnippet(headline string) string {
+	return markReplacer.Replace(html.EscapeString(headline))
+}
@@ -0,0 +1,25 @@
+package psql
+
+import "testing"
+
+func TestMarkSnippet(t *testing.T) {
+	tests := []struct {
+		name     string
+		headline string
+		want     string
+	}{
+		{"plain", "nothing found", "nothing found"},
+		{"match", "new " + startSel + "golang" + stopSel + " release", "new <mark>golang</mark> release"},
+		{"html in text", `<script>alert("x")</script> ` + startSel + "go" + stopSel, "&lt;script&gt;alert(&#34;x&#34;)&lt;/script&gt; <mark>go</mark>"},
+		{"html in match", startSel + "<b>go</b>" + stopSel, "<mark>&lt;b&gt;go&lt;/b&gt;</mark>"},
+		{"mark in text", "<mark>fake</mark> & real", "&lt;mark&gt;fake&lt;/mark&gt; &amp; real"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := markSnippet(tt.headline); got != tt.want {
+				t.Errorf("markSnippet(%q) = %q, want %q", tt.headline, got, tt.want)
+			}
+		})
+	}
+}
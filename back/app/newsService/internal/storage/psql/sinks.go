@@ -0,0 +1,159 @@
+package psql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"newsWebApp/app/newsService/internal/models"
+)
+
+type SinkStorage struct {
+	db *sql.DB
+}
+
+func NewSinkStorage(db *sql.DB) *SinkStorage {
+	return &SinkStorage{db: db}
+}
+
+// EnsureSink adds state of the sink if there is none. New sink starts after the
+// newest posted article, so it doesn't receive the whole history.
+func (s *SinkStorage) EnsureSink(ctx context.Context, sink string) error {
+	stmt, err := s.db.PrepareContext(ctx, `INSERT INTO sink_states (sink, last_posted_at, last_article_id)
+	SELECT $1, newest.posted_at, COALESCE(newest.article_id, 0) FROM (SELECT 1) one
+	LEFT JOIN (SELECT posted_at, article_id FROM articles WHERE posted_at IS NOT NULL 
+		ORDER BY posted_at DESC, article_id DESC LIMIT 1) newest ON TRUE
+	ON CONFLICT (sink) DO NOTHING`)
+	if err != nil {
+		return fmt.Errorf("can't prepare statement: %w", err)
+	}
+	defer stmt.Close()
+
+	if _, err := stmt.ExecContext(ctx, sink); err != nil {
+		return fmt.Errorf("can't add sink state: %w", err)
+	}
+
+	return nil
+}
+
+// ClaimSink takes the sink till lease ends if it's due and no other replica holds it.
+func (s *SinkStorage) ClaimSink(ctx context.Context, sink string, lease time.Duration) (*models.SinkState, bool, error) {
+	stmt, err := s.db.PrepareContext(ctx, `UPDATE sink_states SET lease_until = $1::timestamp
+	WHERE sink = $2 
+	AND (lease_until IS NULL OR lease_until <= $3::timestamp) 
+	AND (next_attempt_at IS NULL OR next_attempt_at <= $3::timestamp)
+	RETURNING last_posted_at, last_article_id, failure_count, last_error, last_error_at, last_sent_at`)
+	if err != nil {
+		return nil, false, fmt.Errorf("can't prepare statement: %w", err)
+	}
+	defer stmt.Close()
+
+	now := time.Now().UTC()
+
+	state := models.SinkState{Sink: sink}
+
+	var lastPostedAt, lastErrorAt, lastSentAt sql.NullTime
+
+	err = stmt.QueryRowContext(ctx, now.Add(lease).Format(time.RFC3339), sink, now.Format(time.RFC3339)).Scan(
+		&lastPostedAt,
+		&state.Cursor.ID,
+		&state.FailureCount,
+		&state.LastError,
+		&lastErrorAt,
+		&lastSentAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, false, nil
+		}
+		return nil, false, fmt.Errorf("can't claim sink: %w", err)
+	}
+
+	state.Cursor.PostedAt = lastPostedAt.Time
+	state.LastErrorAt = lastErrorAt.Time
+	state.LastSentAt = lastSentAt.Time
+
+	return &state, true, nil
+}
+
+// SaveSinkState saves position and health of the sink and releases it.
+func (s *SinkStorage) SaveSinkState(ctx context.Context, state models.SinkState) error {
+	stmt, err := s.db.PrepareContext(ctx, `UPDATE sink_states
+	SET last_posted_at = $1, last_article_id = $2, failure_count = $3, last_error = $4, 
+	last_error_at = $5, last_sent_at = $6, next_attempt_at = $7, lease_until = NULL
+	WHERE sink = $8`)
+	if err != nil {
+		return fmt.Errorf("can't prepare statement: %w", err)
+	}
+	defer stmt.Close()
+
+	if _, err := stmt.ExecContext(ctx,
+		nullTime(state.Cursor.PostedAt),
+		state.Cursor.ID,
+		state.FailureCount,
+		state.LastError,
+		nullTime(state.LastErrorAt),
+		nullTime(state.LastSentAt),
+		nullTime(state.NextAttemptAt),
+		state.Sink,
+	); err != nil {
+		return fmt.Errorf("can't save sink state: %v", err)
+	}
+
+	return nil
+}
+
+// PostedSince returns up to limit articles posted after the cursor in posting order,
+// zero cursor starts from the first posted article.
+func (s *SinkStorage) PostedSince(ctx context.Context, after models.PageCursor, limit int) ([]models.Article, error) {
+	stmt, err := s.db.PrepareContext(ctx, `SELECT a.article_id, u.user_name AS user_name, a.source_name, a.title, a.link, a.excerpt, a.image, a.posted_at FROM articles a 
+	LEFT JOIN users u ON u.user_id = a.user_id 
+	WHERE a.posted_at IS NOT NULL AND ($1::timestamp IS NULL OR (a.posted_at, a.article_id) > ($1::timestamp, $2)) 
+	ORDER BY a.posted_at, a.article_id LIMIT $3`)
+	if err != nil {
+		return nil, fmt.Errorf("can't prepare statement: %w", err)
+	}
+	defer stmt.Close()
+
+	rows, err := stmt.QueryContext(ctx, nullTime(after.PostedAt), after.ID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("can't get articles from db: %w", err)
+	}
+	defer rows.Close()
+
+	articles := []models.Article{}
+
+	for rows.Next() {
+		articl := models.Article{}
+		err = rows.Scan(&articl.ID,
+			&articl.UserName,
+			&articl.SourceName,
+			&articl.Title,
+			&articl.Link,
+			&articl.Excerpt,
+			&articl.ImageURL,
+			&articl.PostedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("can't scan model article: %w", err)
+		}
+
+		articles = append(articles, articl)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("can't get articles from db: %w", err)
+	}
+
+	return articles, nil
+}
+
+func nullTime(t time.Time) sql.NullString {
+	if t.IsZero() {
+		return sql.NullString{}
+	}
+
+	return sql.NullString{String: t.UTC().Format(time.RFC3339), Valid: true}
+}
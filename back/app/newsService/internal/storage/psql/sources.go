@@ -5,11 +5,17 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"time"
 
 	"newsWebApp/app/newsService/internal/models"
 	"newsWebApp/app/newsService/internal/storage"
+
+	"github.com/lib/pq"
 )
 
+const sourceColumns = `source_id, name, feed_url, etag, last_modified, content_hash,
+	enabled, failure_count, last_error, last_error_at, last_success_at, next_fetch_at`
+
 type SourceStorage struct {
 	db *sql.DB
 }
@@ -19,14 +25,12 @@ func NewSourceStorage(db *sql.DB) *SourceStorage {
 }
 
 func (s *SourceStorage) GetList(ctx context.Context) ([]models.Source, error) {
-	stmt, err := s.db.PrepareContext(ctx, "SELECT source_id, name, feed_url FROM sources")
+	stmt, err := s.db.PrepareContext(ctx, "SELECT "+sourceColumns+" FROM sources ORDER BY source_id")
 	if err != nil {
 		return nil, fmt.Errorf("can't prepare statement: %w", err)
 	}
 	defer stmt.Close()
 
-	sources := []models.Source{}
-
 	rows, err := stmt.QueryContext(ctx)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -36,21 +40,35 @@ func (s *SourceStorage) GetList(ctx context.Context) ([]models.Source, error) {
 	}
 	defer rows.Close()
 
-	for rows.Next() {
-		sour := models.Source{}
-		err = rows.Scan(&sour.ID, &sour.Name, &sour.FeedURL)
-		if err != nil {
-			return nil, fmt.Errorf("can't scan model source: %w", err)
-		}
+	return scanSources(rows)
+}
 
-		sources = append(sources, sour)
+// GetDueList returns enabled sources whose backoff period is over.
+func (s *SourceStorage) GetDueList(ctx context.Context) ([]models.Source, error) {
+	stmt, err := s.db.PrepareContext(ctx, "SELECT "+sourceColumns+` FROM sources
+	WHERE enabled AND (next_fetch_at IS NULL OR next_fetch_at <= $1::timestamp)
+	ORDER BY source_id`)
+	if err != nil {
+		return nil, fmt.Errorf("can't prepare statement: %w", err)
 	}
+	defer stmt.Close()
 
-	return sources, nil
+	now := time.Now().UTC().Format(time.RFC3339)
+
+	rows, err := stmt.QueryContext(ctx, now)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, storage.ErrNoSources
+		}
+		return nil, fmt.Errorf("can't get sources: %w", err)
+	}
+	defer rows.Close()
+
+	return scanSources(rows)
 }
 
 func (s *SourceStorage) GetByID(ctx context.Context, id int64) (*models.Source, error) {
-	stmt, err := s.db.PrepareContext(ctx, "SELECT source_id, name, feed_url FROM sources WHERE source_id = $1")
+	stmt, err := s.db.PrepareContext(ctx, "SELECT "+sourceColumns+" FROM sources WHERE source_id = $1")
 	if err != nil {
 		return nil, fmt.Errorf("can't prepare statement: %w", err)
 	}
@@ -65,9 +83,8 @@ func (s *SourceStorage) GetByID(ctx context.Context, id int64) (*models.Source,
 		return nil, fmt.Errorf("can't get source: %w", err)
 	}
 
-	source := models.Source{}
-
-	if err := row.Scan(&source.ID, &source.Name, &source.FeedURL); err != nil {
+	source, err := scanSource(row)
+	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, storage.ErrSourceNotFound
 		}
@@ -84,25 +101,66 @@ func (s *SourceStorage) Add(ctx context.Context, source models.Source) (int64, e
 	}
 	defer stmt.Close()
 
-	row := stmt.QueryRowContext(ctx, source)
+	var id int64
 
-	if err := row.Err(); err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
+	if err := stmt.QueryRowContext(ctx, source.Name, source.FeedURL).Scan(&id); err != nil {
+		pqErr, ok := err.(*pq.Error)
+		if ok && pqErr.Code.Name() == "unique_violation" {
 			return 0, storage.ErrSourceExists
 		}
 		return 0, fmt.Errorf("can't insert source: %w", err)
 	}
 
-	var id int64
+	return id, nil
+}
+
+// Update changes name and feed url of the source and drops its feed validators.
+func (s *SourceStorage) Update(ctx context.Context, source models.Source) (*models.Source, error) {
+	stmt, err := s.db.PrepareContext(ctx, `UPDATE sources
+	SET name = $1, feed_url = $2, etag = '', last_modified = '', content_hash = ''
+	WHERE source_id = $3 RETURNING `+sourceColumns)
+	if err != nil {
+		return nil, fmt.Errorf("can't prepare statement: %w", err)
+	}
+	defer stmt.Close()
+
+	updated, err := scanSource(stmt.QueryRowContext(ctx, source.Name, source.FeedURL, source.ID))
+	if err != nil {
+		pqErr, ok := err.(*pq.Error)
+		switch {
+		case ok && pqErr.Code.Name() == "unique_violation":
+			return nil, storage.ErrSourceExists
+		case errors.Is(err, sql.ErrNoRows):
+			return nil, storage.ErrSourceNotFound
+		default:
+			return nil, fmt.Errorf("can't update source: %w", err)
+		}
+	}
+
+	return &updated, nil
+}
+
+// SetEnabled turns fetching of the source on or off. Enabled source starts without backoff.
+func (s *SourceStorage) SetEnabled(ctx context.Context, id int64, enabled bool) (*models.Source, error) {
+	stmt, err := s.db.PrepareContext(ctx, `UPDATE sources
+	SET enabled = $1,
+		failure_count = CASE WHEN $1 THEN 0 ELSE failure_count END,
+		next_fetch_at = CASE WHEN $1 THEN NULL ELSE next_fetch_at END
+	WHERE source_id = $2 RETURNING `+sourceColumns)
+	if err != nil {
+		return nil, fmt.Errorf("can't prepare statement: %w", err)
+	}
+	defer stmt.Close()
 
-	if err := row.Scan(&id); err != nil {
+	updated, err := scanSource(stmt.QueryRowContext(ctx, enabled, id))
+	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			return 0, storage.ErrSourceExists
+			return nil, storage.ErrSourceNotFound
 		}
-		return 0, fmt.Errorf("can't get last insert id: %w", err)
+		return nil, fmt.Errorf("can't update source: %w", err)
 	}
 
-	return id, nil
+	return &updated, nil
 }
 
 func (s *SourceStorage) Delete(ctx context.Context, id int64) error {
@@ -112,9 +170,140 @@ func (s *SourceStorage) Delete(ctx context.Context, id int64) error {
 	}
 	defer stmt.Close()
 
-	if _, err := stmt.ExecContext(ctx, id); err != nil {
-		return fmt.Errorf("can't delete source from db: %v", err)
+	res, err := stmt.ExecContext(ctx, id)
+	if err != nil {
+		return fmt.Errorf("can't delete source from db: %w", err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("can't delete source from db: %w", err)
+	}
+
+	if affected == 0 {
+		return storage.ErrSourceNotFound
+	}
+
+	return nil
+}
+
+func (s *SourceStorage) UpdateFeedState(ctx context.Context, source models.Source) error {
+	stmt, err := s.db.PrepareContext(ctx, "UPDATE sources SET etag = $1, last_modified = $2, content_hash = $3 WHERE source_id = $4")
+	if err != nil {
+		return fmt.Errorf("can't prepare statement: %w", err)
+	}
+	defer stmt.Close()
+
+	if _, err := stmt.ExecContext(ctx, source.ETag, source.LastModified, source.ContentHash, source.ID); err != nil {
+		return fmt.Errorf("can't update source feed state: %w", err)
+	}
+
+	return nil
+}
+
+// MarkFetchSucceeded resets failure counter and backoff of the source.
+func (s *SourceStorage) MarkFetchSucceeded(ctx context.Context, id int64) error {
+	stmt, err := s.db.PrepareContext(ctx, `UPDATE sources
+	SET failure_count = 0, last_success_at = $1::timestamp, next_fetch_at = NULL
+	WHERE source_id = $2`)
+	if err != nil {
+		return fmt.Errorf("can't prepare statement: %w", err)
+	}
+	defer stmt.Close()
+
+	now := time.Now().UTC().Format(time.RFC3339)
+
+	if _, err := stmt.ExecContext(ctx, now, id); err != nil {
+		return fmt.Errorf("can't update source health: %w", err)
 	}
 
 	return nil
 }
+
+// MarkFetchFailed counts one more failed fetch of the source and returns the source.
+// Next fetch is put off by backoff doubled on every failure up to maxBackoff, after
+// maxFailures failures in a row the source is disabled, zero maxFailures never disables it.
+// Counter and flag are changed relatively, so enabling or disabling the source while
+// it's fetched isn't overwritten.
+func (s *SourceStorage) MarkFetchFailed(ctx context.Context,
+	id int64,
+	lastError string,
+	backoff time.Duration,
+	maxBackoff time.Duration,
+	maxFailures int,
+) (*models.Source, error) {
+	stmt, err := s.db.PrepareContext(ctx, `UPDATE sources
+	SET failure_count = failure_count + 1,
+		last_error = $1,
+		last_error_at = $2::timestamp,
+		next_fetch_at = $2::timestamp + make_interval(secs => LEAST($3::float8 * power(2, LEAST(failure_count, 32)), $4::float8)),
+		enabled = enabled AND ($5::int = 0 OR failure_count + 1 < $5::int)
+	WHERE source_id = $6 RETURNING `+sourceColumns)
+	if err != nil {
+		return nil, fmt.Errorf("can't prepare statement: %w", err)
+	}
+	defer stmt.Close()
+
+	now := time.Now().UTC().Format(time.RFC3339)
+
+	updated, err := scanSource(stmt.QueryRowContext(ctx, lastError, now, backoff.Seconds(), maxBackoff.Seconds(), maxFailures, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, storage.ErrSourceNotFound
+		}
+		return nil, fmt.Errorf("can't update source health: %w", err)
+	}
+
+	return &updated, nil
+}
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanSource(sc scanner) (models.Source, error) {
+	source := models.Source{}
+
+	var lastErrorAt, lastSuccessAt, nextFetchAt sql.NullTime
+
+	if err := sc.Scan(&source.ID,
+		&source.Name,
+		&source.FeedURL,
+		&source.ETag,
+		&source.LastModified,
+		&source.ContentHash,
+		&source.Enabled,
+		&source.FailureCount,
+		&source.LastError,
+		&lastErrorAt,
+		&lastSuccessAt,
+		&nextFetchAt,
+	); err != nil {
+		return source, err
+	}
+
+	source.LastErrorAt = lastErrorAt.Time
+	source.LastSuccessAt = lastSuccessAt.Time
+	source.NextFetchAt = nextFetchAt.Time
+
+	return source, nil
+}
+
+func scanSources(rows *sql.Rows) ([]models.Source, error) {
+	sources := []models.Source{}
+
+	for rows.Next() {
+		sour, err := scanSource(rows)
+		if err != nil {
+			return nil, fmt.Errorf("can't scan model source: %w", err)
+		}
+
+		sources = append(sources, sour)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("can't get sources: %w", err)
+	}
+
+	return sources, nil
+}
@@ -0,0 +1,120 @@
+package psql
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"newsWebApp/app/newsService/internal/models"
+	"newsWebApp/app/newsService/internal/storage"
+)
+
+func TestMarkFetchFailed(t *testing.T) {
+	type want struct {
+		failures int
+		delay    time.Duration
+		enabled  bool
+	}
+
+	tests := []struct {
+		name        string
+		maxFailures int
+		want        []want
+	}{
+		{
+			name:        "backoff grows up to max and source is disabled",
+			maxFailures: 4,
+			want: []want{
+				{1, time.Minute, true},
+				{2, 2 * time.Minute, true},
+				{3, 4 * time.Minute, true},
+				{4, 5 * time.Minute, false},
+				{5, 5 * time.Minute, false},
+			},
+		},
+		{
+			name:        "zero max failures never disables",
+			maxFailures: 0,
+			want: []want{
+				{1, time.Minute, true},
+				{2, 2 * time.Minute, true},
+				{3, 4 * time.Minute, true},
+				{4, 5 * time.Minute, true},
+				{5, 5 * time.Minute, true},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewSourceStorage(testDB(t))
+			ctx := context.Background()
+
+			id, err := s.Add(ctx, models.Source{Name: "blog", FeedURL: "https://example.com/feed"})
+			if err != nil {
+				t.Fatalf("Add() error = %v", err)
+			}
+
+			for i, w := range tt.want {
+				got, err := s.MarkFetchFailed(ctx, id, "timeout", time.Minute, 5*time.Minute, tt.maxFailures)
+				if err != nil {
+					t.Fatalf("MarkFetchFailed() %d error = %v", i+1, err)
+				}
+
+				delay := got.NextFetchAt.Sub(got.LastErrorAt)
+
+				if got.FailureCount != w.failures || delay != w.delay || got.Enabled != w.enabled || got.LastError != "timeout" {
+					t.Errorf("after failure %d source has %d failures, delay %s, enabled %t, want %d, %s, %t",
+						i+1, got.FailureCount, delay, got.Enabled, w.failures, w.delay, w.enabled)
+				}
+			}
+		})
+	}
+}
+
+func TestMarkFetchFailedKeepsStoredState(t *testing.T) {
+	s := NewSourceStorage(testDB(t))
+	ctx := context.Background()
+
+	id, err := s.Add(ctx, models.Source{Name: "blog", FeedURL: "https://example.com/feed"})
+	if err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+
+	if _, err := s.MarkFetchFailed(ctx, id, "timeout", time.Minute, time.Hour, 3); err != nil {
+		t.Fatalf("MarkFetchFailed() error = %v", err)
+	}
+
+	// Source disabled by admin while it's fetched stays disabled.
+	if _, err := s.SetEnabled(ctx, id, false); err != nil {
+		t.Fatalf("SetEnabled() error = %v", err)
+	}
+
+	got, err := s.MarkFetchFailed(ctx, id, "timeout", time.Minute, time.Hour, 3)
+	if err != nil {
+		t.Fatalf("MarkFetchFailed() error = %v", err)
+	}
+
+	if got.Enabled || got.FailureCount != 2 {
+		t.Errorf("source is enabled %t with %d failures, want disabled with 2", got.Enabled, got.FailureCount)
+	}
+
+	// Enabled source starts counting again.
+	if _, err := s.SetEnabled(ctx, id, true); err != nil {
+		t.Fatalf("SetEnabled() error = %v", err)
+	}
+
+	got, err = s.MarkFetchFailed(ctx, id, "timeout", time.Minute, time.Hour, 3)
+	if err != nil {
+		t.Fatalf("MarkFetchFailed() error = %v", err)
+	}
+
+	if !got.Enabled || got.FailureCount != 1 {
+		t.Errorf("source is enabled %t with %d failures, want enabled with 1", got.Enabled, got.FailureCount)
+	}
+
+	if _, err := s.MarkFetchFailed(ctx, id+1, "timeout", time.Minute, time.Hour, 3); !errors.Is(err, storage.ErrSourceNotFound) {
+		t.Errorf("MarkFetchFailed() of unknown source error = %v, want ErrSourceNotFound", err)
+	}
+}
@@ -0,0 +1,147 @@
+package psql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"newsWebApp/app/newsService/internal/models"
+	"newsWebApp/app/newsService/internal/storage"
+)
+
+// PendingSubmissions returns not posted articles offered by users which wait for a
+// moderator, the oldest first.
+func (s *ArticleStorage) PendingSubmissions(ctx context.Context, limit int) ([]models.Article, error) {
+	stmt, err := s.db.PrepareContext(ctx, `SELECT article_id, a.user_id, u.user_name AS user_name, COALESCE(source_name, ''), title, link, excerpt, image, published_at, created_at, status 
+	FROM articles a 
+	LEFT JOIN users u ON u.user_id = a.user_id 
+	WHERE a.status = 'pending' AND a.posted_at IS NULL AND a.user_id > 1 
+	ORDER BY a.created_at, a.article_id LIMIT $1`)
+	if err != nil {
+		return nil, fmt.Errorf("can't prepare statement: %w", err)
+	}
+	defer stmt.Close()
+
+	rows, err := stmt.QueryContext(ctx, limit)
+	if err != nil {
+		return nil, fmt.Errorf("can't get submissions from db: %w", err)
+	}
+	defer rows.Close()
+
+	articles := []models.Article{}
+
+	for rows.Next() {
+		articl := models.Article{}
+		err = rows.Scan(&articl.ID,
+			&articl.UserID,
+			&articl.UserName,
+			&articl.SourceName,
+			&articl.Title,
+			&articl.Link,
+			&articl.Excerpt,
+			&articl.ImageURL,
+			&articl.PublishedAt,
+			&articl.CreatedAt,
+			&articl.Status,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("can't scan model article: %w", err)
+		}
+
+		articles = append(articles, articl)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("can't get submissions from db: %w", err)
+	}
+
+	return articles, nil
+}
+
+// ModerateSubmission moves pending article offered by a user to the status with the
+// note of moderator and returns the article. Only pending articles are moderated.
+func (s *ArticleStorage) ModerateSubmission(ctx context.Context, artID int64, status string, note string, moderator string) (*models.Article, error) {
+	stmt, err := s.db.PrepareContext(ctx, `UPDATE articles a 
+	SET status = $1, moderator_note = $2, moderated_by = $3, moderated_at = $4::timestamp 
+	FROM users u 
+	WHERE u.user_id = a.user_id AND a.article_id = $5 AND a.user_id > 1 AND a.posted_at IS NULL AND a.status = 'pending' 
+	RETURNING a.user_id, u.user_name, COALESCE(a.source_name, ''), a.title, a.link, a.excerpt, a.image, a.published_at, a.created_at`)
+	if err != nil {
+		return nil, fmt.Errorf("can't prepare statement: %w", err)
+	}
+	defer stmt.Close()
+
+	now := time.Now().UTC()
+
+	articl := models.Article{
+		ID:            artID,
+		Status:        status,
+		ModeratorNote: note,
+		ModeratedBy:   moderator,
+		ModeratedAt:   now,
+	}
+
+	err = stmt.QueryRowContext(ctx, status, note, moderator, now.Format(time.RFC3339), artID).Scan(
+		&articl.UserID,
+		&articl.UserName,
+		&articl.SourceName,
+		&articl.Title,
+		&articl.Link,
+		&articl.Excerpt,
+		&articl.ImageURL,
+		&articl.PublishedAt,
+		&articl.CreatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, s.notModerated(ctx, artID)
+		}
+		return nil, fmt.Errorf("can't moderate submission: %w", err)
+	}
+
+	return &articl, nil
+}
+
+// ReleaseDuplicates makes the oldest copy of the group the one which may be posted,
+// the rest of copies, headID too, become its duplicates. It's used when head of
+// the group is rejected, so the group isn't held back by it.
+func (s *ArticleStorage) ReleaseDuplicates(ctx context.Context, headID int64) error {
+	stmt, err := s.db.PrepareContext(ctx, `WITH next_head AS (
+		SELECT article_id FROM articles WHERE duplicate_of = $1 ORDER BY created_at, article_id LIMIT 1
+	)
+	UPDATE articles SET duplicate_of = NULLIF((SELECT article_id FROM next_head), article_id) 
+	WHERE (article_id = $1 OR duplicate_of = $1) AND EXISTS (SELECT 1 FROM next_head)`)
+	if err != nil {
+		return fmt.Errorf("can't prepare statement: %w", err)
+	}
+	defer stmt.Close()
+
+	if _, err := stmt.ExecContext(ctx, headID); err != nil {
+		return fmt.Errorf("can't release duplicates: %w", err)
+	}
+
+	return nil
+}
+
+// notModerated tells why article can't be moderated.
+func (s *ArticleStorage) notModerated(ctx context.Context, artID int64) error {
+	stmt, err := s.db.PrepareContext(ctx, `SELECT status FROM articles 
+	WHERE article_id = $1 AND user_id > 1 AND posted_at IS NULL`)
+	if err != nil {
+		return fmt.Errorf("can't prepare statement: %w", err)
+	}
+	defer stmt.Close()
+
+	var status string
+
+	if err := stmt.QueryRowContext(ctx, artID).Scan(&status); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return storage.ErrSubmissionNotFound
+		}
+		return fmt.Errorf("can't get submission status: %w", err)
+	}
+
+	return storage.ErrNotPending
+}
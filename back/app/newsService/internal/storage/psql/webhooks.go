@@ -0,0 +1,263 @@
+package psql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"newsWebApp/app/newsService/internal/models"
+	"newsWebApp/app/newsService/internal/storage"
+
+	"github.com/lib/pq"
+)
+
+const deliveryColumns = `delivery_id, webhook_id, event, payload, status, attempts, response_code,
+	last_error, created_at, next_attempt_at, delivered_at`
+
+type WebhookStorage struct {
+	db *sql.DB
+}
+
+func NewWebhookStorage(db *sql.DB) *WebhookStorage {
+	return &WebhookStorage{db: db}
+}
+
+func (s *WebhookStorage) ListWebhooks(ctx context.Context) ([]models.Webhook, error) {
+	stmt, err := s.db.PrepareContext(ctx, `SELECT webhook_id, url, secret, events, enabled, created_at 
+	FROM webhooks ORDER BY webhook_id`)
+	if err != nil {
+		return nil, fmt.Errorf("can't prepare statement: %w", err)
+	}
+	defer stmt.Close()
+
+	rows, err := stmt.QueryContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("can't get webhooks: %w", err)
+	}
+	defer rows.Close()
+
+	hooks := []models.Webhook{}
+
+	for rows.Next() {
+		hook := models.Webhook{}
+
+		if err := rows.Scan(&hook.ID, &hook.URL, &hook.Secret, pq.Array(&hook.Events), &hook.Enabled, &hook.CreatedAt); err != nil {
+			return nil, fmt.Errorf("can't scan webhook: %w", err)
+		}
+
+		hooks = append(hooks, hook)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("can't get webhooks: %w", err)
+	}
+
+	return hooks, nil
+}
+
+func (s *WebhookStorage) AddWebhook(ctx context.Context, hook models.Webhook) (int64, error) {
+	stmt, err := s.db.PrepareContext(ctx, `INSERT INTO webhooks (url, secret, events, enabled, created_at) 
+	VALUES ($1, $2, $3, $4, $5::timestamp) RETURNING webhook_id`)
+	if err != nil {
+		return 0, fmt.Errorf("can't prepare statement: %w", err)
+	}
+	defer stmt.Close()
+
+	var id int64
+
+	if err := stmt.QueryRowContext(ctx,
+		hook.URL,
+		hook.Secret,
+		pq.Array(hook.Events),
+		hook.Enabled,
+		hook.CreatedAt.UTC().Format(time.RFC3339),
+	).Scan(&id); err != nil {
+		return 0, fmt.Errorf("can't insert webhook: %w", err)
+	}
+
+	return id, nil
+}
+
+// DeleteWebhook deletes the webhook with all its deliveries.
+func (s *WebhookStorage) DeleteWebhook(ctx context.Context, id int64) error {
+	stmt, err := s.db.PrepareContext(ctx, "DELETE FROM webhooks WHERE webhook_id = $1")
+	if err != nil {
+		return fmt.Errorf("can't prepare statement: %w", err)
+	}
+	defer stmt.Close()
+
+	res, err := stmt.ExecContext(ctx, id)
+	if err != nil {
+		return fmt.Errorf("can't delete webhook: %v", err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("can't delete webhook: %v", err)
+	}
+
+	if affected == 0 {
+		return storage.ErrWebhookNotFound
+	}
+
+	return nil
+}
+
+// EnqueueDeliveries adds a pending delivery of the event for every enabled webhook
+// subscribed to it and returns how many were added.
+func (s *WebhookStorage) EnqueueDeliveries(ctx context.Context, event string, payload []byte) (int64, error) {
+	stmt, err := s.db.PrepareContext(ctx, `INSERT INTO webhook_deliveries (webhook_id, event, payload, created_at, next_attempt_at)
+	SELECT webhook_id, $1, $2, $3::timestamp, $3::timestamp FROM webhooks 
+	WHERE enabled AND $1 = ANY(events)`)
+	if err != nil {
+		return 0, fmt.Errorf("can't prepare statement: %w", err)
+	}
+	defer stmt.Close()
+
+	now := time.Now().UTC().Format(time.RFC3339)
+
+	res, err := stmt.ExecContext(ctx, event, string(payload), now)
+	if err != nil {
+		return 0, fmt.Errorf("can't enqueue webhook deliveries: %w", err)
+	}
+
+	added, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("can't enqueue webhook deliveries: %w", err)
+	}
+
+	return added, nil
+}
+
+// ClaimDeliveries takes up to limit due deliveries of enabled webhooks and hides them
+// from other replicas till lease ends. Delivery not saved before that is sent again.
+func (s *WebhookStorage) ClaimDeliveries(ctx context.Context, limit int, lease time.Duration) ([]models.WebhookDelivery, error) {
+	stmt, err := s.db.PrepareContext(ctx, `UPDATE webhook_deliveries d SET next_attempt_at = $1::timestamp
+	FROM webhooks w
+	WHERE w.webhook_id = d.webhook_id AND d.delivery_id IN (
+		SELECT dd.delivery_id FROM webhook_deliveries dd 
+		JOIN webhooks ww ON ww.webhook_id = dd.webhook_id
+		WHERE dd.status = 'pending' AND dd.next_attempt_at <= $2::timestamp AND ww.enabled
+		ORDER BY dd.next_attempt_at LIMIT $3
+		FOR UPDATE OF dd SKIP LOCKED
+	)
+	RETURNING d.delivery_id, d.webhook_id, w.url, w.secret, d.event, d.payload, d.attempts, d.created_at`)
+	if err != nil {
+		return nil, fmt.Errorf("can't prepare statement: %w", err)
+	}
+	defer stmt.Close()
+
+	now := time.Now().UTC()
+
+	rows, err := stmt.QueryContext(ctx, now.Add(lease).Format(time.RFC3339), now.Format(time.RFC3339), limit)
+	if err != nil {
+		return nil, fmt.Errorf("can't claim webhook deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	deliveries := []models.WebhookDelivery{}
+
+	for rows.Next() {
+		d := models.WebhookDelivery{Status: models.DeliveryPending}
+
+		var payload string
+
+		if err := rows.Scan(&d.ID, &d.WebhookID, &d.URL, &d.Secret, &d.Event, &payload, &d.Attempts, &d.CreatedAt); err != nil {
+			return nil, fmt.Errorf("can't scan webhook delivery: %w", err)
+		}
+
+		d.Payload = []byte(payload)
+
+		deliveries = append(deliveries, d)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("can't claim webhook deliveries: %w", err)
+	}
+
+	return deliveries, nil
+}
+
+// SaveAttempt saves result of the delivery attempt.
+func (s *WebhookStorage) SaveAttempt(ctx context.Context, d models.WebhookDelivery) error {
+	stmt, err := s.db.PrepareContext(ctx, `UPDATE webhook_deliveries
+	SET status = $1, attempts = $2, response_code = $3, last_error = $4, next_attempt_at = $5::timestamp, delivered_at = $6
+	WHERE delivery_id = $7`)
+	if err != nil {
+		return fmt.Errorf("can't prepare statement: %w", err)
+	}
+	defer stmt.Close()
+
+	var deliveredAt sql.NullString
+	if !d.DeliveredAt.IsZero() {
+		deliveredAt = sql.NullString{String: d.DeliveredAt.UTC().Format(time.RFC3339), Valid: true}
+	}
+
+	if _, err := stmt.ExecContext(ctx,
+		d.Status,
+		d.Attempts,
+		d.ResponseCode,
+		d.LastError,
+		d.NextAttemptAt.UTC().Format(time.RFC3339),
+		deliveredAt,
+		d.ID,
+	); err != nil {
+		return fmt.Errorf("can't save webhook delivery: %v", err)
+	}
+
+	return nil
+}
+
+// Deliveries returns up to limit latest deliveries, newest first. Zero webhookID
+// and empty status match any.
+func (s *WebhookStorage) Deliveries(ctx context.Context, webhookID int64, status string, limit int) ([]models.WebhookDelivery, error) {
+	stmt, err := s.db.PrepareContext(ctx, "SELECT "+deliveryColumns+` FROM webhook_deliveries
+	WHERE ($1 = 0 OR webhook_id = $1) AND ($2 = '' OR status = $2)
+	ORDER BY delivery_id DESC LIMIT $3`)
+	if err != nil {
+		return nil, fmt.Errorf("can't prepare statement: %w", err)
+	}
+	defer stmt.Close()
+
+	rows, err := stmt.QueryContext(ctx, webhookID, status, limit)
+	if err != nil {
+		return nil, fmt.Errorf("can't get webhook deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	deliveries := []models.WebhookDelivery{}
+
+	for rows.Next() {
+		d := models.WebhookDelivery{}
+
+		var payload string
+		var deliveredAt sql.NullTime
+
+		if err := rows.Scan(&d.ID,
+			&d.WebhookID,
+			&d.Event,
+			&payload,
+			&d.Status,
+			&d.Attempts,
+			&d.ResponseCode,
+			&d.LastError,
+			&d.CreatedAt,
+			&d.NextAttemptAt,
+			&deliveredAt,
+		); err != nil {
+			return nil, fmt.Errorf("can't scan webhook delivery: %w", err)
+		}
+
+		d.Payload = []byte(payload)
+		d.DeliveredAt = deliveredAt.Time
+
+		deliveries = append(deliveries, d)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("can't get webhook deliveries: %w", err)
+	}
+
+	return deliveries, nil
+}
@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS sink_states (
+    sink VARCHAR(64) PRIMARY KEY,
+    last_posted_at TIMESTAMP,
+    last_article_id INT NOT NULL DEFAULT 0,
+    failure_count INT NOT NULL DEFAULT 0,
+    last_error TEXT NOT NULL DEFAULT '',
+    last_error_at TIMESTAMP,
+    last_sent_at TIMESTAMP,
+    next_attempt_at TIMESTAMP,
+    lease_until TIMESTAMP
+);
@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS followers (
+    follower_id INT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    actor_id TEXT NOT NULL UNIQUE,
+    inbox TEXT NOT NULL,
+    shared_inbox TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL
+);
@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS digest_subscriptions (
+    user_id BIGINT PRIMARY KEY REFERENCES users (user_id) ON DELETE CASCADE,
+    frequency VARCHAR(16) NOT NULL CHECK (frequency IN ('daily', 'weekly')),
+    created_at TIMESTAMP NOT NULL,
+    last_sent_at TIMESTAMP,
+    next_send_at TIMESTAMP NOT NULL,
+    lease_until TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_digest_subscriptions_next_send_at ON digest_subscriptions (next_send_at);
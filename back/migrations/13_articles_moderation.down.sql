@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_articles_pending;
+
+ALTER TABLE articles
+    DROP COLUMN IF EXISTS moderated_at,
+    DROP COLUMN IF EXISTS moderated_by,
+    DROP COLUMN IF EXISTS moderator_note,
+    DROP COLUMN IF EXISTS status;
@@ -0,0 +1,7 @@
+ALTER TABLE articles
+    ADD COLUMN IF NOT EXISTS status VARCHAR(16) NOT NULL DEFAULT 'approved' CHECK (status IN ('pending', 'approved', 'rejected')),
+    ADD COLUMN IF NOT EXISTS moderator_note TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS moderated_by VARCHAR(255) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS moderated_at TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS idx_articles_pending ON articles (created_at) WHERE status = 'pending';
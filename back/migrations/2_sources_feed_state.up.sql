@@ -0,0 +1,4 @@
+ALTER TABLE sources
+    ADD COLUMN IF NOT EXISTS etag VARCHAR(255) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS last_modified VARCHAR(255) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS content_hash VARCHAR(64) NOT NULL DEFAULT '';
@@ -0,0 +1,7 @@
+ALTER TABLE sources
+    DROP COLUMN IF EXISTS enabled,
+    DROP COLUMN IF EXISTS failure_count,
+    DROP COLUMN IF EXISTS last_error,
+    DROP COLUMN IF EXISTS last_error_at,
+    DROP COLUMN IF EXISTS last_success_at,
+    DROP COLUMN IF EXISTS next_fetch_at;
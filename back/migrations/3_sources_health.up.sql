@@ -0,0 +1,7 @@
+ALTER TABLE sources
+    ADD COLUMN IF NOT EXISTS enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    ADD COLUMN IF NOT EXISTS failure_count INT NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS last_error TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS last_error_at TIMESTAMP,
+    ADD COLUMN IF NOT EXISTS last_success_at TIMESTAMP,
+    ADD COLUMN IF NOT EXISTS next_fetch_at TIMESTAMP;
@@ -0,0 +1,4 @@
+ALTER TABLE articles
+    ADD COLUMN IF NOT EXISTS original_link TEXT NOT NULL DEFAULT '';
+
+UPDATE articles SET original_link = link WHERE original_link = '';
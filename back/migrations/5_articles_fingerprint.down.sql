@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_articles_created_at;
+
+ALTER TABLE articles
+    DROP COLUMN IF EXISTS duplicate_of,
+    DROP COLUMN IF EXISTS fingerprint;
@@ -0,0 +1,5 @@
+ALTER TABLE articles
+    ADD COLUMN IF NOT EXISTS fingerprint BIGINT NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS duplicate_of INT REFERENCES articles (article_id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_articles_created_at ON articles(created_at);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS filter_decisions;
+DROP TABLE IF EXISTS filter_rules;
@@ -0,0 +1,21 @@
+CREATE TABLE IF NOT EXISTS filter_rules (
+    rule_id INT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    name VARCHAR(255) NOT NULL UNIQUE,
+    action VARCHAR(16) NOT NULL CHECK (action IN ('include', 'exclude')),
+    expression TEXT NOT NULL,
+    sources TEXT[] NOT NULL DEFAULT '{}',
+    position INT NOT NULL DEFAULT 0,
+    enabled BOOLEAN NOT NULL DEFAULT TRUE
+);
+
+CREATE TABLE IF NOT EXISTS filter_decisions (
+    decision_id BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    link TEXT NOT NULL,
+    source_name VARCHAR(255) NOT NULL,
+    title TEXT NOT NULL,
+    accepted BOOLEAN NOT NULL,
+    rule VARCHAR(255) NOT NULL,
+    decided_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_filter_decisions_decided_at ON filter_decisions(decided_at);
@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS article_contents (
+    article_id INT PRIMARY KEY REFERENCES articles(article_id) ON DELETE CASCADE,
+    html TEXT NOT NULL DEFAULT '',
+    text TEXT NOT NULL DEFAULT '',
+    word_count INT NOT NULL DEFAULT 0,
+    reading_minutes INT NOT NULL DEFAULT 0,
+    byline TEXT NOT NULL DEFAULT '',
+    language VARCHAR(35) NOT NULL DEFAULT ''
+);
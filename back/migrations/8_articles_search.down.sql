@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_articles_search;
+DROP TRIGGER IF EXISTS trg_article_contents_search ON article_contents;
+DROP TRIGGER IF EXISTS trg_articles_search ON articles;
+DROP FUNCTION IF EXISTS article_contents_search_update();
+DROP FUNCTION IF EXISTS articles_search_update();
+DROP FUNCTION IF EXISTS articles_search_vector(TEXT, TEXT, TEXT);
+ALTER TABLE articles DROP COLUMN IF EXISTS search_vector;
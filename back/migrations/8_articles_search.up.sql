@@ -0,0 +1,42 @@
+ALTER TABLE articles ADD COLUMN IF NOT EXISTS search_vector TSVECTOR NOT NULL DEFAULT ''::tsvector;
+
+-- Habr items are Russian, the rest are mostly English, so both configs are indexed.
+-- Extracted text is cut to keep the vector under its size limit.
+CREATE OR REPLACE FUNCTION articles_search_vector(title TEXT, excerpt TEXT, body TEXT) RETURNS TSVECTOR AS $$
+    SELECT setweight(to_tsvector('russian', COALESCE(title, '')), 'A') ||
+        setweight(to_tsvector('english', COALESCE(title, '')), 'A') ||
+        setweight(to_tsvector('russian', COALESCE(excerpt, '')), 'B') ||
+        setweight(to_tsvector('english', COALESCE(excerpt, '')), 'B') ||
+        setweight(to_tsvector('russian', LEFT(COALESCE(body, ''), 100000)), 'C') ||
+        setweight(to_tsvector('english', LEFT(COALESCE(body, ''), 100000)), 'C')
+$$ LANGUAGE SQL IMMUTABLE;
+
+CREATE OR REPLACE FUNCTION articles_search_update() RETURNS TRIGGER AS $$
+BEGIN
+    NEW.search_vector := articles_search_vector(NEW.title, NEW.excerpt,
+        (SELECT text FROM article_contents WHERE article_id = NEW.article_id));
+    RETURN NEW;
+END
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE FUNCTION article_contents_search_update() RETURNS TRIGGER AS $$
+BEGIN
+    UPDATE articles SET search_vector = articles_search_vector(title, excerpt, NEW.text)
+    WHERE article_id = NEW.article_id;
+    RETURN NEW;
+END
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trg_articles_search ON articles;
+CREATE TRIGGER trg_articles_search BEFORE INSERT OR UPDATE OF title, excerpt ON articles
+    FOR EACH ROW EXECUTE FUNCTION articles_search_update();
+
+DROP TRIGGER IF EXISTS trg_article_contents_search ON article_contents;
+CREATE TRIGGER trg_article_contents_search AFTER INSERT OR UPDATE OF text ON article_contents
+    FOR EACH ROW EXECUTE FUNCTION article_contents_search_update();
+
+UPDATE articles a SET search_vector = articles_search_vector(a.title, a.excerpt, c.text)
+FROM articles x LEFT JOIN article_contents c ON c.article_id = x.article_id
+WHERE x.article_id = a.article_id;
+
+CREATE INDEX IF NOT EXISTS idx_articles_search ON articles USING GIN (search_vector);
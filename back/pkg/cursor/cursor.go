@@ -0,0 +1,113 @@
+// Package cursor encodes positions of keyset pagination into opaque tokens shared
+// by news and api services.
+package cursor
+
+import (
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+const sep = "|"
+
+// Page is a position in the feed ordered by posting time and id.
+type Page struct {
+	PostedAt time.Time
+	ID       int64
+}
+
+// Search is a position in search results ordered by rank and id.
+type Search struct {
+	Rank float32
+	ID   int64
+}
+
+// EncodePage returns token of the position after the article.
+func EncodePage(p Page) string {
+	return encode("p", strconv.FormatInt(p.PostedAt.UnixMicro(), 10), strconv.FormatInt(p.ID, 10))
+}
+
+// DecodePage returns position encoded by EncodePage. Empty token is the first page.
+func DecodePage(token string) (Page, error) {
+	if token == "" {
+		return Page{}, nil
+	}
+
+	parts, err := decode(token, "p", 2)
+	if err != nil {
+		return Page{}, err
+	}
+
+	usec, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return Page{}, ErrInvalidCursor
+	}
+
+	id, err := parseID(parts[1])
+	if err != nil {
+		return Page{}, err
+	}
+
+	return Page{PostedAt: time.UnixMicro(usec).UTC(), ID: id}, nil
+}
+
+// EncodeSearch returns token of the position after the search result.
+func EncodeSearch(s Search) string {
+	return encode("s", strconv.FormatFloat(float64(s.Rank), 'g', -1, 32), strconv.FormatInt(s.ID, 10))
+}
+
+// DecodeSearch returns position encoded by EncodeSearch. Empty token is the first page.
+func DecodeSearch(token string) (Search, error) {
+	if token == "" {
+		return Search{}, nil
+	}
+
+	parts, err := decode(token, "s", 2)
+	if err != nil {
+		return Search{}, err
+	}
+
+	rank, err := strconv.ParseFloat(parts[0], 32)
+	if err != nil {
+		return Search{}, ErrInvalidCursor
+	}
+
+	id, err := parseID(parts[1])
+	if err != nil {
+		return Search{}, err
+	}
+
+	return Search{Rank: float32(rank), ID: id}, nil
+}
+
+func encode(kind string, values ...string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(kind + sep + strings.Join(values, sep)))
+}
+
+// decode checks kind of the token so a cursor of one listing isn't accepted by another.
+func decode(token string, kind string, n int) ([]string, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	parts := strings.Split(string(raw), sep)
+	if len(parts) != n+1 || parts[0] != kind {
+		return nil, ErrInvalidCursor
+	}
+
+	return parts[1:], nil
+}
+
+func parseID(s string) (int64, error) {
+	id, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || id <= 0 {
+		return 0, ErrInvalidCursor
+	}
+
+	return id, nil
+}
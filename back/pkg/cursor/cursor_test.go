@@ -0,0 +1,110 @@
+package cursor
+
+import (
+	"encoding/base64"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestPageRoundTrip(t *testing.T) {
+	tests := []Page{
+		{PostedAt: time.Date(2024, 3, 1, 12, 30, 0, 0, time.UTC), ID: 1},
+		{PostedAt: time.Date(2024, 3, 1, 12, 30, 0, 123456000, time.UTC), ID: 9007199254740993},
+		{PostedAt: time.Date(1969, 12, 31, 23, 59, 59, 0, time.UTC), ID: 42},
+	}
+
+	for _, p := range tests {
+		token := EncodePage(p)
+
+		got, err := DecodePage(token)
+		if err != nil {
+			t.Fatalf("DecodePage(%q) error = %v", token, err)
+		}
+
+		if !got.PostedAt.Equal(p.PostedAt) || got.ID != p.ID {
+			t.Errorf("DecodePage(EncodePage(%+v)) = %+v", p, got)
+		}
+
+		if got.PostedAt.Location() != time.UTC {
+			t.Errorf("DecodePage() location = %v, want UTC", got.PostedAt.Location())
+		}
+	}
+}
+
+func TestPageTruncatesToMicroseconds(t *testing.T) {
+	p := Page{PostedAt: time.Date(2024, 3, 1, 0, 0, 0, 1999, time.FixedZone("X", 3600)), ID: 5}
+
+	got, err := DecodePage(EncodePage(p))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := p.PostedAt.Truncate(time.Microsecond); !got.PostedAt.Equal(want) {
+		t.Errorf("PostedAt = %v, want %v", got.PostedAt, want)
+	}
+}
+
+func TestSearchRoundTrip(t *testing.T) {
+	tests := []Search{
+		{Rank: 0.0607927, ID: 1},
+		{Rank: 1e-20, ID: 77},
+		{Rank: 0, ID: 3},
+	}
+
+	for _, s := range tests {
+		token := EncodeSearch(s)
+
+		got, err := DecodeSearch(token)
+		if err != nil {
+			t.Fatalf("DecodeSearch(%q) error = %v", token, err)
+		}
+
+		if got != s {
+			t.Errorf("DecodeSearch(EncodeSearch(%+v)) = %+v", s, got)
+		}
+	}
+}
+
+func TestEmptyToken(t *testing.T) {
+	if p, err := DecodePage(""); err != nil || p != (Page{}) {
+		t.Errorf("DecodePage(\"\") = %+v, %v, want zero page", p, err)
+	}
+
+	if s, err := DecodeSearch(""); err != nil || s != (Search{}) {
+		t.Errorf("DecodeSearch(\"\") = %+v, %v, want zero position", s, err)
+	}
+}
+
+func TestDecodeInvalid(t *testing.T) {
+	raw := func(s string) string { return base64.RawURLEncoding.EncodeToString([]byte(s)) }
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{"not base64", "!!!"},
+		{"padded base64", base64.URLEncoding.EncodeToString([]byte("p|1|1"))},
+		{"kind of search", EncodeSearch(Search{Rank: 0.5, ID: 1})},
+		{"no kind", raw("1|1")},
+		{"too many parts", raw("p|1|1|1")},
+		{"bad time", raw("p|yesterday|1")},
+		{"zero id", raw("p|1|0")},
+		{"negative id", raw("p|1|-5")},
+		{"bad id", raw("p|1|x")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := DecodePage(tt.token); !errors.Is(err, ErrInvalidCursor) {
+				t.Errorf("DecodePage(%q) error = %v, want %v", tt.token, err, ErrInvalidCursor)
+			}
+		})
+	}
+
+	for _, token := range []string{EncodePage(Page{ID: 1}), raw("s|high|1"), raw("s|0.5|0")} {
+		if _, err := DecodeSearch(token); !errors.Is(err, ErrInvalidCursor) {
+			t.Errorf("DecodeSearch(%q) error = %v, want %v", token, err, ErrInvalidCursor)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+// Package netguard keeps connections for untrusted links away from internal networks.
+// It's shared by news and api services.
+package netguard
+
+import (
+	"errors"
+	"fmt"
+	"net/netip"
+	"syscall"
+)
+
+var ErrForbiddenAddress = errors.New("address is forbidden")
+
+// reservedPrefixes are special purpose networks which are not covered by netip.Addr methods.
+// 6to4 and Teredo addresses carry IPv4 address which may be a private one.
+var reservedPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),
+	netip.MustParsePrefix("100.64.0.0/10"),
+	netip.MustParsePrefix("192.0.0.0/24"),
+	netip.MustParsePrefix("198.18.0.0/15"),
+	netip.MustParsePrefix("240.0.0.0/4"),
+	netip.MustParsePrefix("64:ff9b::/96"),
+	netip.MustParsePrefix("64:ff9b:1::/48"),
+	netip.MustParsePrefix("2001::/32"),
+	netip.MustParsePrefix("2002::/16"),
+}
+
+// Control is called by dialer with resolved address before connecting to it, so
+// redirects and DNS answers changed after validation can't lead to internal network.
+func Control(network, address string, _ syscall.RawConn) error {
+	addrPort, err := netip.ParseAddrPort(address)
+	if err != nil || !IsPublic(addrPort.Addr()) {
+		return fmt.Errorf("%w: %s", ErrForbiddenAddress, address)
+	}
+
+	return nil
+}
+
+// IsPublic reports whether the address is reachable from the internet.
+func IsPublic(addr netip.Addr) bool {
+	addr = addr.Unmap()
+
+	if addr.IsLoopback() ||
+		addr.IsPrivate() ||
+		addr.IsLinkLocalUnicast() ||
+		addr.IsLinkLocalMulticast() ||
+		addr.IsInterfaceLocalMulticast() ||
+		addr.IsMulticast() ||
+		addr.IsUnspecified() {
+		return false
+	}
+
+	for _, prefix := range reservedPrefixes {
+		if prefix.Contains(addr) {
+			return false
+		}
+	}
+
+	return true
+}
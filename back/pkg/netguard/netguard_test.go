@@ -0,0 +1,74 @@
+package netguard
+
+import (
+	"errors"
+	"net/netip"
+	"testing"
+)
+
+func TestIsPublic(t *testing.T) {
+	tests := []struct {
+		addr string
+		want bool
+	}{
+		{"93.184.216.34", true},
+		{"8.8.8.8", true},
+		{"2606:4700:4700::1111", true},
+		{"127.0.0.1", false},
+		{"10.1.2.3", false},
+		{"172.16.0.1", false},
+		{"192.168.1.1", false},
+		{"169.254.169.254", false},
+		{"100.64.0.1", false},
+		{"0.0.0.0", false},
+		{"198.18.0.1", false},
+		{"224.0.0.1", false},
+		{"255.255.255.255", false},
+		{"::1", false},
+		{"::", false},
+		{"fc00::1", false},
+		{"fe80::1", false},
+		{"::ffff:127.0.0.1", false},
+		{"::ffff:8.8.8.8", true},
+		{"64:ff9b::a00:1", false},
+		{"64:ff9b:1::a00:1", false},
+		{"2002:a00:1::1", false},
+		{"2002:c0a8:101::1", false},
+		{"2001:0:4136:e378:8000:63bf:f5ff:fffe", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.addr, func(t *testing.T) {
+			if got := IsPublic(netip.MustParseAddr(tt.addr)); got != tt.want {
+				t.Errorf("IsPublic(%s) = %v, want %v", tt.addr, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestControl(t *testing.T) {
+	tests := []struct {
+		address string
+		wantErr bool
+	}{
+		{"93.184.216.34:443", false},
+		{"[2606:4700:4700::1111]:443", false},
+		{"127.0.0.1:80", true},
+		{"[::1]:80", true},
+		{"[2002:7f00:1::1]:80", true},
+		{"localhost:80", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.address, func(t *testing.T) {
+			err := Control("tcp", tt.address, nil)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Control(%s) error = %v, wantErr %v", tt.address, err, tt.wantErr)
+			}
+
+			if err != nil && !errors.Is(err, ErrForbiddenAddress) {
+				t.Errorf("Control(%s) error = %v, want ErrForbiddenAddress", tt.address, err)
+			}
+		})
+	}
+}
@@ -29,6 +29,33 @@ type NewsClient interface {
 	GetArticles(ctx context.Context, in *GetArticlesRequest, opts ...grpc.CallOption) (*GetArticlesResponse, error)
 	GetNewestArticle(ctx context.Context, in *GetNewestArticleRequest, opts ...grpc.CallOption) (*GetNewestArticleResponse, error)
 	GetArticlesByPage(ctx context.Context, in *GetArticlesByPageRequest, opts ...grpc.CallOption) (*GetArticlesByPageResponse, error)
+	ListSourceHealth(ctx context.Context, in *ListSourceHealthRequest, opts ...grpc.CallOption) (*ListSourceHealthResponse, error)
+	ListSources(ctx context.Context, in *ListSourcesRequest, opts ...grpc.CallOption) (*ListSourcesResponse, error)
+	AddSource(ctx context.Context, in *AddSourceRequest, opts ...grpc.CallOption) (*AddSourceResponse, error)
+	UpdateSource(ctx context.Context, in *UpdateSourceRequest, opts ...grpc.CallOption) (*UpdateSourceResponse, error)
+	DeleteSource(ctx context.Context, in *DeleteSourceRequest, opts ...grpc.CallOption) (*DeleteSourceResponse, error)
+	SetSourceEnabled(ctx context.Context, in *SetSourceEnabledRequest, opts ...grpc.CallOption) (*SetSourceEnabledResponse, error)
+	ImportOPML(ctx context.Context, in *ImportOPMLRequest, opts ...grpc.CallOption) (*ImportOPMLResponse, error)
+	ExportOPML(ctx context.Context, in *ExportOPMLRequest, opts ...grpc.CallOption) (*ExportOPMLResponse, error)
+	ReloadConfig(ctx context.Context, in *ReloadConfigRequest, opts ...grpc.CallOption) (*ReloadConfigResponse, error)
+	ExplainFilter(ctx context.Context, in *ExplainFilterRequest, opts ...grpc.CallOption) (*ExplainFilterResponse, error)
+	GetArticle(ctx context.Context, in *GetArticleRequest, opts ...grpc.CallOption) (*GetArticleResponse, error)
+	SearchArticles(ctx context.Context, in *SearchArticlesRequest, opts ...grpc.CallOption) (*SearchArticlesResponse, error)
+	WatchPublished(ctx context.Context, in *WatchPublishedRequest, opts ...grpc.CallOption) (News_WatchPublishedClient, error)
+	ListWebhooks(ctx context.Context, in *ListWebhooksRequest, opts ...grpc.CallOption) (*ListWebhooksResponse, error)
+	AddWebhook(ctx context.Context, in *AddWebhookRequest, opts ...grpc.CallOption) (*AddWebhookResponse, error)
+	DeleteWebhook(ctx context.Context, in *DeleteWebhookRequest, opts ...grpc.CallOption) (*DeleteWebhookResponse, error)
+	ListWebhookDeliveries(ctx context.Context, in *ListWebhookDeliveriesRequest, opts ...grpc.CallOption) (*ListWebhookDeliveriesResponse, error)
+	AddFollower(ctx context.Context, in *AddFollowerRequest, opts ...grpc.CallOption) (*AddFollowerResponse, error)
+	RemoveFollower(ctx context.Context, in *RemoveFollowerRequest, opts ...grpc.CallOption) (*RemoveFollowerResponse, error)
+	ListFollowers(ctx context.Context, in *ListFollowersRequest, opts ...grpc.CallOption) (*ListFollowersResponse, error)
+	GetDigestSubscription(ctx context.Context, in *GetDigestSubscriptionRequest, opts ...grpc.CallOption) (*GetDigestSubscriptionResponse, error)
+	SubscribeDigest(ctx context.Context, in *SubscribeDigestRequest, opts ...grpc.CallOption) (*SubscribeDigestResponse, error)
+	UnsubscribeDigest(ctx context.Context, in *UnsubscribeDigestRequest, opts ...grpc.CallOption) (*UnsubscribeDigestResponse, error)
+	UnsubscribeDigestByToken(ctx context.Context, in *UnsubscribeDigestByTokenRequest, opts ...grpc.CallOption) (*UnsubscribeDigestByTokenResponse, error)
+	ListPendingSubmissions(ctx context.Context, in *ListPendingSubmissionsRequest, opts ...grpc.CallOption) (*ListPendingSubmissionsResponse, error)
+	ApproveSubmission(ctx context.Context, in *ApproveSubmissionRequest, opts ...grpc.CallOption) (*ApproveSubmissionResponse, error)
+	RejectSubmission(ctx context.Context, in *RejectSubmissionRequest, opts ...grpc.CallOption) (*RejectSubmissionResponse, error)
 }
 
 type newsClient struct {
@@ -102,6 +129,272 @@ func (c *newsClient) GetArticlesByPage(ctx context.Context, in *GetArticlesByPag
 	return out, nil
 }
 
+func (c *newsClient) ListSourceHealth(ctx context.Context, in *ListSourceHealthRequest, opts ...grpc.CallOption) (*ListSourceHealthResponse, error) {
+	out := new(ListSourceHealthResponse)
+	err := c.cc.Invoke(ctx, "/news.News/ListSourceHealth", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *newsClient) ListSources(ctx context.Context, in *ListSourcesRequest, opts ...grpc.CallOption) (*ListSourcesResponse, error) {
+	out := new(ListSourcesResponse)
+	err := c.cc.Invoke(ctx, "/news.News/ListSources", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *newsClient) AddSource(ctx context.Context, in *AddSourceRequest, opts ...grpc.CallOption) (*AddSourceResponse, error) {
+	out := new(AddSourceResponse)
+	err := c.cc.Invoke(ctx, "/news.News/AddSource", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *newsClient) UpdateSource(ctx context.Context, in *UpdateSourceRequest, opts ...grpc.CallOption) (*UpdateSourceResponse, error) {
+	out := new(UpdateSourceResponse)
+	err := c.cc.Invoke(ctx, "/news.News/UpdateSource", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *newsClient) DeleteSource(ctx context.Context, in *DeleteSourceRequest, opts ...grpc.CallOption) (*DeleteSourceResponse, error) {
+	out := new(DeleteSourceResponse)
+	err := c.cc.Invoke(ctx, "/news.News/DeleteSource", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *newsClient) SetSourceEnabled(ctx context.Context, in *SetSourceEnabledRequest, opts ...grpc.CallOption) (*SetSourceEnabledResponse, error) {
+	out := new(SetSourceEnabledResponse)
+	err := c.cc.Invoke(ctx, "/news.News/SetSourceEnabled", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *newsClient) ImportOPML(ctx context.Context, in *ImportOPMLRequest, opts ...grpc.CallOption) (*ImportOPMLResponse, error) {
+	out := new(ImportOPMLResponse)
+	err := c.cc.Invoke(ctx, "/news.News/ImportOPML", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *newsClient) ExportOPML(ctx context.Context, in *ExportOPMLRequest, opts ...grpc.CallOption) (*ExportOPMLResponse, error) {
+	out := new(ExportOPMLResponse)
+	err := c.cc.Invoke(ctx, "/news.News/ExportOPML", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *newsClient) ReloadConfig(ctx context.Context, in *ReloadConfigRequest, opts ...grpc.CallOption) (*ReloadConfigResponse, error) {
+	out := new(ReloadConfigResponse)
+	err := c.cc.Invoke(ctx, "/news.News/ReloadConfig", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *newsClient) ExplainFilter(ctx context.Context, in *ExplainFilterRequest, opts ...grpc.CallOption) (*ExplainFilterResponse, error) {
+	out := new(ExplainFilterResponse)
+	err := c.cc.Invoke(ctx, "/news.News/ExplainFilter", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *newsClient) GetArticle(ctx context.Context, in *GetArticleRequest, opts ...grpc.CallOption) (*GetArticleResponse, error) {
+	out := new(GetArticleResponse)
+	err := c.cc.Invoke(ctx, "/news.News/GetArticle", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *newsClient) SearchArticles(ctx context.Context, in *SearchArticlesRequest, opts ...grpc.CallOption) (*SearchArticlesResponse, error) {
+	out := new(SearchArticlesResponse)
+	err := c.cc.Invoke(ctx, "/news.News/SearchArticles", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *newsClient) WatchPublished(ctx context.Context, in *WatchPublishedRequest, opts ...grpc.CallOption) (News_WatchPublishedClient, error) {
+	stream, err := c.cc.NewStream(ctx, &News_ServiceDesc.Streams[0], "/news.News/WatchPublished", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &newsWatchPublishedClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type News_WatchPublishedClient interface {
+	Recv() (*Article, error)
+	grpc.ClientStream
+}
+
+type newsWatchPublishedClient struct {
+	grpc.ClientStream
+}
+
+func (x *newsWatchPublishedClient) Recv() (*Article, error) {
+	m := new(Article)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *newsClient) ListWebhooks(ctx context.Context, in *ListWebhooksRequest, opts ...grpc.CallOption) (*ListWebhooksResponse, error) {
+	out := new(ListWebhooksResponse)
+	err := c.cc.Invoke(ctx, "/news.News/ListWebhooks", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *newsClient) AddWebhook(ctx context.Context, in *AddWebhookRequest, opts ...grpc.CallOption) (*AddWebhookResponse, error) {
+	out := new(AddWebhookResponse)
+	err := c.cc.Invoke(ctx, "/news.News/AddWebhook", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *newsClient) DeleteWebhook(ctx context.Context, in *DeleteWebhookRequest, opts ...grpc.CallOption) (*DeleteWebhookResponse, error) {
+	out := new(DeleteWebhookResponse)
+	err := c.cc.Invoke(ctx, "/news.News/DeleteWebhook", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *newsClient) ListWebhookDeliveries(ctx context.Context, in *ListWebhookDeliveriesRequest, opts ...grpc.CallOption) (*ListWebhookDeliveriesResponse, error) {
+	out := new(ListWebhookDeliveriesResponse)
+	err := c.cc.Invoke(ctx, "/news.News/ListWebhookDeliveries", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *newsClient) AddFollower(ctx context.Context, in *AddFollowerRequest, opts ...grpc.CallOption) (*AddFollowerResponse, error) {
+	out := new(AddFollowerResponse)
+	err := c.cc.Invoke(ctx, "/news.News/AddFollower", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *newsClient) RemoveFollower(ctx context.Context, in *RemoveFollowerRequest, opts ...grpc.CallOption) (*RemoveFollowerResponse, error) {
+	out := new(RemoveFollowerResponse)
+	err := c.cc.Invoke(ctx, "/news.News/RemoveFollower", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *newsClient) ListFollowers(ctx context.Context, in *ListFollowersRequest, opts ...grpc.CallOption) (*ListFollowersResponse, error) {
+	out := new(ListFollowersResponse)
+	err := c.cc.Invoke(ctx, "/news.News/ListFollowers", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *newsClient) GetDigestSubscription(ctx context.Context, in *GetDigestSubscriptionRequest, opts ...grpc.CallOption) (*GetDigestSubscriptionResponse, error) {
+	out := new(GetDigestSubscriptionResponse)
+	err := c.cc.Invoke(ctx, "/news.News/GetDigestSubscription", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *newsClient) SubscribeDigest(ctx context.Context, in *SubscribeDigestRequest, opts ...grpc.CallOption) (*SubscribeDigestResponse, error) {
+	out := new(SubscribeDigestResponse)
+	err := c.cc.Invoke(ctx, "/news.News/SubscribeDigest", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *newsClient) UnsubscribeDigest(ctx context.Context, in *UnsubscribeDigestRequest, opts ...grpc.CallOption) (*UnsubscribeDigestResponse, error) {
+	out := new(UnsubscribeDigestResponse)
+	err := c.cc.Invoke(ctx, "/news.News/UnsubscribeDigest", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *newsClient) UnsubscribeDigestByToken(ctx context.Context, in *UnsubscribeDigestByTokenRequest, opts ...grpc.CallOption) (*UnsubscribeDigestByTokenResponse, error) {
+	out := new(UnsubscribeDigestByTokenResponse)
+	err := c.cc.Invoke(ctx, "/news.News/UnsubscribeDigestByToken", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *newsClient) ListPendingSubmissions(ctx context.Context, in *ListPendingSubmissionsRequest, opts ...grpc.CallOption) (*ListPendingSubmissionsResponse, error) {
+	out := new(ListPendingSubmissionsResponse)
+	err := c.cc.Invoke(ctx, "/news.News/ListPendingSubmissions", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *newsClient) ApproveSubmission(ctx context.Context, in *ApproveSubmissionRequest, opts ...grpc.CallOption) (*ApproveSubmissionResponse, error) {
+	out := new(ApproveSubmissionResponse)
+	err := c.cc.Invoke(ctx, "/news.News/ApproveSubmission", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *newsClient) RejectSubmission(ctx context.Context, in *RejectSubmissionRequest, opts ...grpc.CallOption) (*RejectSubmissionResponse, error) {
+	out := new(RejectSubmissionResponse)
+	err := c.cc.Invoke(ctx, "/news.News/RejectSubmission", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // NewsServer is the server API for News service.
 // All implementations must embed UnimplementedNewsServer
 // for forward compatibility
@@ -113,6 +406,33 @@ type NewsServer interface {
 	GetArticles(context.Context, *GetArticlesRequest) (*GetArticlesResponse, error)
 	GetNewestArticle(context.Context, *GetNewestArticleRequest) (*GetNewestArticleResponse, error)
 	GetArticlesByPage(context.Context, *GetArticlesByPageRequest) (*GetArticlesByPageResponse, error)
+	ListSourceHealth(context.Context, *ListSourceHealthRequest) (*ListSourceHealthResponse, error)
+	ListSources(context.Context, *ListSourcesRequest) (*ListSourcesResponse, error)
+	AddSource(context.Context, *AddSourceRequest) (*AddSourceResponse, error)
+	UpdateSource(context.Context, *UpdateSourceRequest) (*UpdateSourceResponse, error)
+	DeleteSource(context.Context, *DeleteSourceRequest) (*DeleteSourceResponse, error)
+	SetSourceEnabled(context.Context, *SetSourceEnabledRequest) (*SetSourceEnabledResponse, error)
+	ImportOPML(context.Context, *ImportOPMLRequest) (*ImportOPMLResponse, error)
+	ExportOPML(context.Context, *ExportOPMLRequest) (*ExportOPMLResponse, error)
+	ReloadConfig(context.Context, *ReloadConfigRequest) (*ReloadConfigResponse, error)
+	ExplainFilter(context.Context, *ExplainFilterRequest) (*ExplainFilterResponse, error)
+	GetArticle(context.Context, *GetArticleRequest) (*GetArticleResponse, error)
+	SearchArticles(context.Context, *SearchArticlesRequest) (*SearchArticlesResponse, error)
+	WatchPublished(*WatchPublishedRequest, News_WatchPublishedServer) error
+	ListWebhooks(context.Context, *ListWebhooksRequest) (*ListWebhooksResponse, error)
+	AddWebhook(context.Context, *AddWebhookRequest) (*AddWebhookResponse, error)
+	DeleteWebhook(context.Context, *DeleteWebhookRequest) (*DeleteWebhookResponse, error)
+	ListWebhookDeliveries(context.Context, *ListWebhookDeliveriesRequest) (*ListWebhookDeliveriesResponse, error)
+	AddFollower(context.Context, *AddFollowerRequest) (*AddFollowerResponse, error)
+	RemoveFollower(context.Context, *RemoveFollowerRequest) (*RemoveFollowerResponse, error)
+	ListFollowers(context.Context, *ListFollowersRequest) (*ListFollowersResponse, error)
+	GetDigestSubscription(context.Context, *GetDigestSubscriptionRequest) (*GetDigestSubscriptionResponse, error)
+	SubscribeDigest(context.Context, *SubscribeDigestRequest) (*SubscribeDigestResponse, error)
+	UnsubscribeDigest(context.Context, *UnsubscribeDigestRequest) (*UnsubscribeDigestResponse, error)
+	UnsubscribeDigestByToken(context.Context, *UnsubscribeDigestByTokenRequest) (*UnsubscribeDigestByTokenResponse, error)
+	ListPendingSubmissions(context.Context, *ListPendingSubmissionsRequest) (*ListPendingSubmissionsResponse, error)
+	ApproveSubmission(context.Context, *ApproveSubmissionRequest) (*ApproveSubmissionResponse, error)
+	RejectSubmission(context.Context, *RejectSubmissionRequest) (*RejectSubmissionResponse, error)
 	mustEmbedUnimplementedNewsServer()
 }
 
@@ -141,6 +461,87 @@ func (UnimplementedNewsServer) GetNewestArticle(context.Context, *GetNewestArtic
 func (UnimplementedNewsServer) GetArticlesByPage(context.Context, *GetArticlesByPageRequest) (*GetArticlesByPageResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetArticlesByPage not implemented")
 }
+func (UnimplementedNewsServer) ListSourceHealth(context.Context, *ListSourceHealthRequest) (*ListSourceHealthResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListSourceHealth not implemented")
+}
+func (UnimplementedNewsServer) ListSources(context.Context, *ListSourcesRequest) (*ListSourcesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListSources not implemented")
+}
+func (UnimplementedNewsServer) AddSource(context.Context, *AddSourceRequest) (*AddSourceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddSource not implemented")
+}
+func (UnimplementedNewsServer) UpdateSource(context.Context, *UpdateSourceRequest) (*UpdateSourceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateSource not implemented")
+}
+func (UnimplementedNewsServer) DeleteSource(context.Context, *DeleteSourceRequest) (*DeleteSourceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteSource not implemented")
+}
+func (UnimplementedNewsServer) SetSourceEnabled(context.Context, *SetSourceEnabledRequest) (*SetSourceEnabledResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetSourceEnabled not implemented")
+}
+func (UnimplementedNewsServer) ImportOPML(context.Context, *ImportOPMLRequest) (*ImportOPMLResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ImportOPML not implemented")
+}
+func (UnimplementedNewsServer) ExportOPML(context.Context, *ExportOPMLRequest) (*ExportOPMLResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ExportOPML not implemented")
+}
+func (UnimplementedNewsServer) ReloadConfig(context.Context, *ReloadConfigRequest) (*ReloadConfigResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReloadConfig not implemented")
+}
+func (UnimplementedNewsServer) ExplainFilter(context.Context, *ExplainFilterRequest) (*ExplainFilterResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ExplainFilter not implemented")
+}
+func (UnimplementedNewsServer) GetArticle(context.Context, *GetArticleRequest) (*GetArticleResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetArticle not implemented")
+}
+func (UnimplementedNewsServer) SearchArticles(context.Context, *SearchArticlesRequest) (*SearchArticlesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SearchArticles not implemented")
+}
+func (UnimplementedNewsServer) WatchPublished(*WatchPublishedRequest, News_WatchPublishedServer) error {
+	return status.Errorf(codes.Unimplemented, "method WatchPublished not implemented")
+}
+func (UnimplementedNewsServer) ListWebhooks(context.Context, *ListWebhooksRequest) (*ListWebhooksResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListWebhooks not implemented")
+}
+func (UnimplementedNewsServer) AddWebhook(context.Context, *AddWebhookRequest) (*AddWebhookResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddWebhook not implemented")
+}
+func (UnimplementedNewsServer) DeleteWebhook(context.Context, *DeleteWebhookRequest) (*DeleteWebhookResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteWebhook not implemented")
+}
+func (UnimplementedNewsServer) ListWebhookDeliveries(context.Context, *ListWebhookDeliveriesRequest) (*ListWebhookDeliveriesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListWebhookDeliveries not implemented")
+}
+func (UnimplementedNewsServer) AddFollower(context.Context, *AddFollowerRequest) (*AddFollowerResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddFollower not implemented")
+}
+func (UnimplementedNewsServer) RemoveFollower(context.Context, *RemoveFollowerRequest) (*RemoveFollowerResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemoveFollower not implemented")
+}
+func (UnimplementedNewsServer) ListFollowers(context.Context, *ListFollowersRequest) (*ListFollowersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListFollowers not implemented")
+}
+func (UnimplementedNewsServer) GetDigestSubscription(context.Context, *GetDigestSubscriptionRequest) (*GetDigestSubscriptionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetDigestSubscription not implemented")
+}
+func (UnimplementedNewsServer) SubscribeDigest(context.Context, *SubscribeDigestRequest) (*SubscribeDigestResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SubscribeDigest not implemented")
+}
+func (UnimplementedNewsServer) UnsubscribeDigest(context.Context, *UnsubscribeDigestRequest) (*UnsubscribeDigestResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UnsubscribeDigest not implemented")
+}
+func (UnimplementedNewsServer) UnsubscribeDigestByToken(context.Context, *UnsubscribeDigestByTokenRequest) (*UnsubscribeDigestByTokenResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UnsubscribeDigestByToken not implemented")
+}
+func (UnimplementedNewsServer) ListPendingSubmissions(context.Context, *ListPendingSubmissionsRequest) (*ListPendingSubmissionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListPendingSubmissions not implemented")
+}
+func (UnimplementedNewsServer) ApproveSubmission(context.Context, *ApproveSubmissionRequest) (*ApproveSubmissionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ApproveSubmission not implemented")
+}
+func (UnimplementedNewsServer) RejectSubmission(context.Context, *RejectSubmissionRequest) (*RejectSubmissionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RejectSubmission not implemented")
+}
 func (UnimplementedNewsServer) mustEmbedUnimplementedNewsServer() {}
 
 // UnsafeNewsServer may be embedded to opt out of forward compatibility for this service.
@@ -280,42 +681,641 @@ func _News_GetArticlesByPage_Handler(srv interface{}, ctx context.Context, dec f
 	return interceptor(ctx, in, info, handler)
 }
 
-// News_ServiceDesc is the grpc.ServiceDesc for News service.
-// It's only intended for direct use with grpc.RegisterService,
-// and not to be introspected or modified (even as a copy)
-var News_ServiceDesc = grpc.ServiceDesc{
-	ServiceName: "news.News",
-	HandlerType: (*NewsServer)(nil),
-	Methods: []grpc.MethodDesc{
-		{
-			MethodName: "GetArticlesByUid",
-			Handler:    _News_GetArticlesByUid_Handler,
-		},
-		{
-			MethodName: "SaveArticle",
-			Handler:    _News_SaveArticle_Handler,
-		},
-		{
-			MethodName: "UpdateArticle",
-			Handler:    _News_UpdateArticle_Handler,
-		},
-		{
-			MethodName: "DeleteArticle",
-			Handler:    _News_DeleteArticle_Handler,
-		},
-		{
-			MethodName: "GetArticles",
-			Handler:    _News_GetArticles_Handler,
-		},
-		{
-			MethodName: "GetNewestArticle",
-			Handler:    _News_GetNewestArticle_Handler,
-		},
-		{
-			MethodName: "GetArticlesByPage",
-			Handler:    _News_GetArticlesByPage_Handler,
+func _News_ListSourceHealth_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListSourceHealthRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NewsServer).ListSourceHealth(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/news.News/ListSourceHealth",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NewsServer).ListSourceHealth(ctx, req.(*ListSourceHealthRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _News_ListSources_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListSourcesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NewsServer).ListSources(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/news.News/ListSources",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NewsServer).ListSources(ctx, req.(*ListSourcesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _News_AddSource_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddSourceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NewsServer).AddSource(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/news.News/AddSource",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NewsServer).AddSource(ctx, req.(*AddSourceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _News_UpdateSource_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateSourceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NewsServer).UpdateSource(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/news.News/UpdateSource",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NewsServer).UpdateSource(ctx, req.(*UpdateSourceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _News_DeleteSource_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteSourceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NewsServer).DeleteSource(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/news.News/DeleteSource",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NewsServer).DeleteSource(ctx, req.(*DeleteSourceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _News_SetSourceEnabled_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetSourceEnabledRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NewsServer).SetSourceEnabled(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/news.News/SetSourceEnabled",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NewsServer).SetSourceEnabled(ctx, req.(*SetSourceEnabledRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _News_ImportOPML_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ImportOPMLRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NewsServer).ImportOPML(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/news.News/ImportOPML",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NewsServer).ImportOPML(ctx, req.(*ImportOPMLRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _News_ExportOPML_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExportOPMLRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NewsServer).ExportOPML(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/news.News/ExportOPML",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NewsServer).ExportOPML(ctx, req.(*ExportOPMLRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _News_ReloadConfig_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReloadConfigRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NewsServer).ReloadConfig(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/news.News/ReloadConfig",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NewsServer).ReloadConfig(ctx, req.(*ReloadConfigRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _News_ExplainFilter_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExplainFilterRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NewsServer).ExplainFilter(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/news.News/ExplainFilter",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NewsServer).ExplainFilter(ctx, req.(*ExplainFilterRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _News_GetArticle_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetArticleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NewsServer).GetArticle(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/news.News/GetArticle",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NewsServer).GetArticle(ctx, req.(*GetArticleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _News_SearchArticles_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SearchArticlesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NewsServer).SearchArticles(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/news.News/SearchArticles",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NewsServer).SearchArticles(ctx, req.(*SearchArticlesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _News_WatchPublished_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchPublishedRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(NewsServer).WatchPublished(m, &newsWatchPublishedServer{stream})
+}
+
+type News_WatchPublishedServer interface {
+	Send(*Article) error
+	grpc.ServerStream
+}
+
+type newsWatchPublishedServer struct {
+	grpc.ServerStream
+}
+
+func (x *newsWatchPublishedServer) Send(m *Article) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _News_ListWebhooks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListWebhooksRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NewsServer).ListWebhooks(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/news.News/ListWebhooks",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NewsServer).ListWebhooks(ctx, req.(*ListWebhooksRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _News_AddWebhook_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddWebhookRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NewsServer).AddWebhook(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/news.News/AddWebhook",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NewsServer).AddWebhook(ctx, req.(*AddWebhookRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _News_DeleteWebhook_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteWebhookRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NewsServer).DeleteWebhook(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/news.News/DeleteWebhook",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NewsServer).DeleteWebhook(ctx, req.(*DeleteWebhookRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _News_ListWebhookDeliveries_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListWebhookDeliveriesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NewsServer).ListWebhookDeliveries(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/news.News/ListWebhookDeliveries",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NewsServer).ListWebhookDeliveries(ctx, req.(*ListWebhookDeliveriesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _News_AddFollower_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddFollowerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NewsServer).AddFollower(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/news.News/AddFollower",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NewsServer).AddFollower(ctx, req.(*AddFollowerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _News_RemoveFollower_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemoveFollowerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NewsServer).RemoveFollower(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/news.News/RemoveFollower",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NewsServer).RemoveFollower(ctx, req.(*RemoveFollowerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _News_ListFollowers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListFollowersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NewsServer).ListFollowers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/news.News/ListFollowers",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NewsServer).ListFollowers(ctx, req.(*ListFollowersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _News_GetDigestSubscription_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetDigestSubscriptionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NewsServer).GetDigestSubscription(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/news.News/GetDigestSubscription",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NewsServer).GetDigestSubscription(ctx, req.(*GetDigestSubscriptionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _News_SubscribeDigest_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SubscribeDigestRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NewsServer).SubscribeDigest(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/news.News/SubscribeDigest",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NewsServer).SubscribeDigest(ctx, req.(*SubscribeDigestRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _News_UnsubscribeDigest_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UnsubscribeDigestRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NewsServer).UnsubscribeDigest(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/news.News/UnsubscribeDigest",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NewsServer).UnsubscribeDigest(ctx, req.(*UnsubscribeDigestRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _News_UnsubscribeDigestByToken_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UnsubscribeDigestByTokenRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NewsServer).UnsubscribeDigestByToken(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/news.News/UnsubscribeDigestByToken",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NewsServer).UnsubscribeDigestByToken(ctx, req.(*UnsubscribeDigestByTokenRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _News_ListPendingSubmissions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListPendingSubmissionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NewsServer).ListPendingSubmissions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/news.News/ListPendingSubmissions",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NewsServer).ListPendingSubmissions(ctx, req.(*ListPendingSubmissionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _News_ApproveSubmission_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ApproveSubmissionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NewsServer).ApproveSubmission(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/news.News/ApproveSubmission",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NewsServer).ApproveSubmission(ctx, req.(*ApproveSubmissionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _News_RejectSubmission_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RejectSubmissionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NewsServer).RejectSubmission(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/news.News/RejectSubmission",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NewsServer).RejectSubmission(ctx, req.(*RejectSubmissionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// News_ServiceDesc is the grpc.ServiceDesc for News service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var News_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "news.News",
+	HandlerType: (*NewsServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetArticlesByUid",
+			Handler:    _News_GetArticlesByUid_Handler,
+		},
+		{
+			MethodName: "SaveArticle",
+			Handler:    _News_SaveArticle_Handler,
+		},
+		{
+			MethodName: "UpdateArticle",
+			Handler:    _News_UpdateArticle_Handler,
+		},
+		{
+			MethodName: "DeleteArticle",
+			Handler:    _News_DeleteArticle_Handler,
+		},
+		{
+			MethodName: "GetArticles",
+			Handler:    _News_GetArticles_Handler,
+		},
+		{
+			MethodName: "GetNewestArticle",
+			Handler:    _News_GetNewestArticle_Handler,
+		},
+		{
+			MethodName: "GetArticlesByPage",
+			Handler:    _News_GetArticlesByPage_Handler,
+		},
+		{
+			MethodName: "ListSourceHealth",
+			Handler:    _News_ListSourceHealth_Handler,
+		},
+		{
+			MethodName: "ListSources",
+			Handler:    _News_ListSources_Handler,
+		},
+		{
+			MethodName: "AddSource",
+			Handler:    _News_AddSource_Handler,
+		},
+		{
+			MethodName: "UpdateSource",
+			Handler:    _News_UpdateSource_Handler,
+		},
+		{
+			MethodName: "DeleteSource",
+			Handler:    _News_DeleteSource_Handler,
+		},
+		{
+			MethodName: "SetSourceEnabled",
+			Handler:    _News_SetSourceEnabled_Handler,
+		},
+		{
+			MethodName: "ImportOPML",
+			Handler:    _News_ImportOPML_Handler,
+		},
+		{
+			MethodName: "ExportOPML",
+			Handler:    _News_ExportOPML_Handler,
+		},
+		{
+			MethodName: "ReloadConfig",
+			Handler:    _News_ReloadConfig_Handler,
+		},
+		{
+			MethodName: "ExplainFilter",
+			Handler:    _News_ExplainFilter_Handler,
+		},
+		{
+			MethodName: "GetArticle",
+			Handler:    _News_GetArticle_Handler,
+		},
+		{
+			MethodName: "SearchArticles",
+			Handler:    _News_SearchArticles_Handler,
+		},
+		{
+			MethodName: "ListWebhooks",
+			Handler:    _News_ListWebhooks_Handler,
+		},
+		{
+			MethodName: "AddWebhook",
+			Handler:    _News_AddWebhook_Handler,
+		},
+		{
+			MethodName: "DeleteWebhook",
+			Handler:    _News_DeleteWebhook_Handler,
+		},
+		{
+			MethodName: "ListWebhookDeliveries",
+			Handler:    _News_ListWebhookDeliveries_Handler,
+		},
+		{
+			MethodName: "AddFollower",
+			Handler:    _News_AddFollower_Handler,
+		},
+		{
+			MethodName: "RemoveFollower",
+			Handler:    _News_RemoveFollower_Handler,
+		},
+		{
+			MethodName: "ListFollowers",
+			Handler:    _News_ListFollowers_Handler,
+		},
+		{
+			MethodName: "GetDigestSubscription",
+			Handler:    _News_GetDigestSubscription_Handler,
+		},
+		{
+			MethodName: "SubscribeDigest",
+			Handler:    _News_SubscribeDigest_Handler,
+		},
+		{
+			MethodName: "UnsubscribeDigest",
+			Handler:    _News_UnsubscribeDigest_Handler,
+		},
+		{
+			MethodName: "UnsubscribeDigestByToken",
+			Handler:    _News_UnsubscribeDigestByToken_Handler,
+		},
+		{
+			MethodName: "ListPendingSubmissions",
+			Handler:    _News_ListPendingSubmissions_Handler,
+		},
+		{
+			MethodName: "ApproveSubmission",
+			Handler:    _News_ApproveSubmission_Handler,
+		},
+		{
+			MethodName: "RejectSubmission",
+			Handler:    _News_RejectSubmission_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchPublished",
+			Handler:       _News_WatchPublished_Handler,
+			ServerStreams: true,
 		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "news.proto",
 }
@@ -8,7 +8,8 @@ const baseurl = "/home"
 
 const Articles = () => {
 
-    const [currentPage, setCurrentPage] = useState(1)
+    const [currentCursor, setCurrentCursor] = useState("")
+    const [nextCursor, setNextCursor] = useState("")
     const [currentArticles, setCurrentArticles] = useState([])
 
     useEffect(() => {
@@ -16,9 +17,7 @@ const Articles = () => {
             headers: {
                 "Authorization": localStorage.getItem('access_token'),
             },
-            params: {
-                page: currentPage,
-            }
+            params: currentCursor ? { cursor: currentCursor } : {}
         }
 
         axios.get(baseurl, config).then((res) => {
@@ -31,8 +30,11 @@ const Articles = () => {
             }
 
             if (res.data.body.articles) {
-                setCurrentArticles(res.data.body.articles)
+                const articles = res.data.body.articles
+                setCurrentArticles((prev) => currentCursor ? [...prev, ...articles] : articles)
             }
+
+            setNextCursor(res.data.body.next_cursor || "")
         })
             .catch((error) => {
                 if (error) {
@@ -40,10 +42,29 @@ const Articles = () => {
                     console.error('Internal server error:', error)
                 }
             })
-    }, [currentPage]);
+    }, [currentCursor]);
+
+    useEffect(() => {
+        const token = localStorage.getItem('access_token')
+        const source = new EventSource(token ? "/events?access_token=" + encodeURIComponent(token) : "/events")
+
+        source.addEventListener('article', (e) => {
+            const art = JSON.parse(e.data)
+            setCurrentArticles((prev) => prev.some((elem) => elem.article_id === art.article_id) ? prev : [art, ...prev])
+        })
+
+        source.addEventListener('submission', (e) => {
+            const submission = JSON.parse(e.data)
+            if (submission.status === 'posted') {
+                toast.success("Your article has been published!")
+            }
+        })
+
+        return () => source.close()
+    }, []);
 
     const handleNextPage = () => {
-        setCurrentPage(currentPage + 1)
+        setCurrentCursor(nextCursor)
     }
 
     return (
@@ -54,7 +75,7 @@ const Articles = () => {
                     next={() =>
                         handleNextPage()
                     }
-                    hasMore={true}
+                    hasMore={nextCursor !== ""}
                 >
                     <div className="art">
                         {currentArticles.map((elem) => (